@@ -10,7 +10,9 @@ import (
 
 	"real-erp-mebel/be/internal/config"
 	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
 )
 
 func main() {
@@ -72,14 +74,20 @@ func main() {
 	log.Println("")
 	log.Println("Starting fresh migration...")
 
-	// Auto-migrate models (MVP Schema - 19 tables dengan nama bahasa Indonesia)
-	if err := database.DB.AutoMigrate(
+	// Auto-migrate models (nama tabel bahasa Indonesia)
+	schemaModels := []interface{}{
 		// Core
 		&models.Pengguna{},
+		&models.PeranPengguna{},
+		&models.Izin{},
+		&models.PeranIzin{},
+		&models.UndanganPengguna{},
+		&models.TokenRefresh{},
 		// Master Data
 		&models.Produk{},
 		&models.GambarProduk{},
 		&models.Pemasok{},
+		&models.Pelanggan{}, // Master pelanggan (opsional di penjualan & retur)
 		&models.Gudang{},
 		// Stock Management
 		&models.BarangMasuk{},
@@ -88,9 +96,16 @@ func main() {
 		&models.ItemBarangKeluar{},
 		&models.StokInventori{},
 		&models.PergerakanStok{},
+		&models.PeringatanStok{}, // Peringatan stok menipis (open/acknowledged/resolved)
+		&models.TransferStok{},   // Dokumen transfer antar gudang
+		&models.ItemTransferStok{},
+		&models.ItemTransferStokBatch{},
 		// Sales
 		&models.Penjualan{},
 		&models.ItemPenjualan{},
+		&models.PembayaranPenjualan{}, // Baris pembayaran: tender POS (split) & DP/pelunasan pesanan
+		&models.ShiftKasir{},          // Shift kasir (kas awal & rekonsiliasi tutup shift)
+		&models.RekapShiftKasir{},
 		// Purchase Order
 		&models.PesananPembelian{},
 		&models.ItemPesananPembelian{},
@@ -99,14 +114,27 @@ func main() {
 		&models.ItemReturPenjualan{},
 		&models.ReturPembelian{},
 		&models.ItemReturPembelian{},
+		&models.DisposisiKarantina{}, // Audit tindakan stok karantina retur
 		// Finance
 		&models.HutangPemasok{},
-	); err != nil {
+		&models.PembayaranHutang{},
+		// Event
+		&models.AntreanEvent{}, // Outbox event domain (WebSocket / webhook)
+		&models.LanggananWebhook{},
+		&models.PengirimanWebhook{}, // Log & antrean retry pengiriman webhook
+	}
+	if err := database.DB.AutoMigrate(schemaModels...); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Seed katalog permission & role bawaan (owner, admin_gudang, kasir, finance)
+	if err := repositories.NewRoleRepository().SeedDefaults(middleware.PermissionCatalog, middleware.DefaultRolePermissions); err != nil {
+		log.Fatalf("Failed to seed roles: %v", err)
+	}
+	log.Println("✅ Default roles & permissions seeded")
+
 	log.Println("")
 	log.Println("✅ Fresh migration completed successfully!")
-	log.Println("📊 Total tables migrated: 19 (dengan nama bahasa Indonesia)")
+	log.Printf("📊 Total tables migrated: %d (dengan nama bahasa Indonesia)", len(schemaModels))
 	log.Println("🔄 All tables have been recreated from scratch")
 }
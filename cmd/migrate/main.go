@@ -6,7 +6,10 @@ import (
 
 	"real-erp-mebel/be/internal/config"
 	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/services"
 )
 
 func main() {
@@ -43,14 +46,20 @@ func main() {
 
 	log.Println("Starting database migration...")
 
-	// Auto-migrate models (MVP Schema - 19 tables dengan nama bahasa Indonesia)
-	if err := database.DB.AutoMigrate(
+	// Auto-migrate models (nama tabel bahasa Indonesia)
+	schemaModels := []interface{}{
 		// Core
 		&models.Pengguna{},
+		&models.PeranPengguna{},
+		&models.Izin{},
+		&models.PeranIzin{},
+		&models.UndanganPengguna{},
+		&models.TokenRefresh{},
 		// Master Data
 		&models.Produk{},
 		&models.GambarProduk{},
 		&models.Pemasok{},
+		&models.Pelanggan{}, // Master pelanggan (opsional di penjualan & retur)
 		&models.Gudang{},
 		// Stock Management
 		&models.BarangMasuk{},
@@ -60,10 +69,17 @@ func main() {
 		&models.StokInventori{},
 		&models.StokBatch{}, // FIFO Batch Tracking
 		&models.PergerakanStok{},
+		&models.PeringatanStok{}, // Peringatan stok menipis (open/acknowledged/resolved)
+		&models.TransferStok{},   // Dokumen transfer antar gudang
+		&models.ItemTransferStok{},
+		&models.ItemTransferStokBatch{},
 		// Sales (Mode 1: POS)
 		&models.Penjualan{},
 		&models.ItemPenjualan{},
-		&models.ItemPenjualanBatch{}, // FIFO Batch Breakdown per Item Penjualan
+		&models.ItemPenjualanBatch{},  // FIFO Batch Breakdown per Item Penjualan
+		&models.PembayaranPenjualan{}, // Baris pembayaran: tender POS (split) & DP/pelunasan pesanan
+		&models.ShiftKasir{},          // Shift kasir (kas awal & rekonsiliasi tutup shift)
+		&models.RekapShiftKasir{},
 		// Purchase Order
 		&models.PesananPembelian{},
 		&models.ItemPesananPembelian{},
@@ -72,14 +88,42 @@ func main() {
 		&models.ItemReturPenjualan{},
 		&models.ReturPembelian{},
 		&models.ItemReturPembelian{},
+		&models.DisposisiKarantina{}, // Audit tindakan stok karantina retur
 		// Finance
 		&models.HutangPemasok{},
-	); err != nil {
+		&models.PembayaranHutang{},
+		// Event
+		&models.AntreanEvent{}, // Outbox event domain (WebSocket / webhook)
+		&models.LanggananWebhook{},
+		&models.PengirimanWebhook{}, // Log & antrean retry pengiriman webhook
+	}
+	if err := database.DB.AutoMigrate(schemaModels...); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Seed katalog permission & role bawaan (owner, admin_gudang, kasir, finance)
+	if err := repositories.NewRoleRepository().SeedDefaults(middleware.PermissionCatalog, middleware.DefaultRolePermissions); err != nil {
+		log.Fatalf("Failed to seed roles: %v", err)
+	}
+	log.Println("✅ Default roles & permissions seeded")
+
+	// Migrasi nama pelanggan free-text di penjualan lama ke master pelanggan (idempotent)
+	backfill, err := services.NewPelangganService(repositories.NewPelangganRepository(database.DB)).BackfillFromSales()
+	if err != nil {
+		log.Fatalf("Failed to backfill customers: %v", err)
+	}
+	log.Printf("✅ Customers backfilled: %d created, %d sales & %d returns linked, %d generic names skipped",
+		backfill.PelangganDibuat, backfill.PenjualanDilink, backfill.ReturDilink, backfill.NamaDilewati)
+
+	// Penjualan POS sebelum split tender: satu baris pembayaran dari metode & total header (idempotent)
+	paymentLines, err := repositories.NewSalesRepository(database.DB).BackfillPOSPaymentLines()
+	if err != nil {
+		log.Fatalf("Failed to backfill sales payment lines: %v", err)
+	}
+	log.Printf("✅ Sales payment lines backfilled: %d", paymentLines)
+
 	log.Println("✅ Database migration completed successfully!")
-	log.Println("📊 Total tables migrated: 19 (dengan nama bahasa Indonesia)")
+	log.Printf("📊 Total tables migrated: %d (dengan nama bahasa Indonesia)", len(schemaModels))
 
 	if *fresh {
 		log.Println("🔄 Fresh migration completed - All tables recreated")
@@ -5,7 +5,9 @@ import (
 
 	"real-erp-mebel/be/internal/config"
 	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/events"
 	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
 	"real-erp-mebel/be/internal/routes"
 	"real-erp-mebel/be/internal/utils"
 	"real-erp-mebel/be/internal/websocket"
@@ -102,6 +104,14 @@ func main() {
 	hub := websocket.NewHub()
 	go hub.Run()
 
+	// Outbox event: dispatcher mengirim event yang sudah di-commit ke hub dan antrean webhook (dengan retry),
+	// dan client yang reconnect bisa replay event dari outbox (?since_id=)
+	outboxRepo := repositories.NewOutboxRepository(database.DB)
+	webhookRepo := repositories.NewWebhookRepository(database.DB)
+	hub.SetReplaySource(events.NewReplaySource(outboxRepo))
+	go events.NewDispatcher(outboxRepo, events.NewHubSink(hub), events.NewWebhookSink(webhookRepo)).Run()
+	go events.NewWebhookWorker(webhookRepo).Run()
+
 	// Setup routes
 	routes.SetupRoutes(r, hub)
 
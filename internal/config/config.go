@@ -12,6 +12,7 @@ type Config struct {
 	GinMode  string
 	Database DatabaseConfig
 	JWT      JWTConfig
+	Auth     AuthConfig
 	CORS     CORSConfig
 }
 
@@ -25,8 +26,14 @@ type DatabaseConfig struct {
 }
 
 type JWTConfig struct {
-	Secret     string
-	Expiration string
+	Secret            string
+	Expiration        string // Umur access token (pendek, misal 15m)
+	RefreshExpiration string // Umur refresh token
+}
+
+type AuthConfig struct {
+	AllowRegistration bool   // Self-registration publik (default: false)
+	InviteExpiration  string // Masa berlaku token undangan staff
 }
 
 type CORSConfig struct {
@@ -55,8 +62,13 @@ func LoadConfig() {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
-			Expiration: getEnv("JWT_EXPIRATION", "24h"),
+			Secret:            getEnv("JWT_SECRET", "your-secret-key-change-this-in-production"),
+			Expiration:        getEnv("JWT_EXPIRATION", "15m"),
+			RefreshExpiration: getEnv("JWT_REFRESH_EXPIRATION", "168h"),
+		},
+		Auth: AuthConfig{
+			AllowRegistration: getEnv("AUTH_ALLOW_REGISTRATION", "false") == "true",
+			InviteExpiration:  getEnv("AUTH_INVITE_EXPIRATION", "72h"),
 		},
 		CORS: CORSConfig{
 			AllowOrigins: getEnv("CORS_ALLOW_ORIGINS", "http://localhost:8000,http://localhost:8080,http://localhost:5173,http://localhost:3000"),
@@ -8,19 +8,62 @@ type LoginRequest struct {
 }
 
 // RegisterRequest adalah DTO untuk request register
-// @Description Request untuk register user baru
+// @Description Request untuk register user baru (hanya jika AUTH_ALLOW_REGISTRATION=true, role selalu kasir)
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
 	Password string `json:"password" binding:"required,min=6" example:"password123"`
 	Name     string `json:"name" binding:"required" example:"John Doe"`
-	Role     string `json:"role" example:"admin"` // optional, default: kasir
+}
+
+// BootstrapRequest adalah DTO untuk membuat owner pertama
+// @Description Request untuk bootstrap owner pertama (hanya saat tabel pengguna masih kosong)
+type BootstrapRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"owner@example.com"`
+	Password string `json:"password" binding:"required,min=8" example:"password123"`
+	Name     string `json:"name" binding:"required" example:"Owner"`
+}
+
+// BootstrapStatusResponse adalah DTO untuk status bootstrap
+// @Description Apakah server masih menunggu owner pertama
+type BootstrapStatusResponse struct {
+	BootstrapRequired   bool `json:"bootstrap_required" example:"false"`
+	RegistrationEnabled bool `json:"registration_enabled" example:"false"`
+}
+
+// AcceptInvitationRequest adalah DTO untuk menerima undangan staff
+// @Description Request untuk membuat akun dari token undangan
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required" example:"3f9a...e21c"`
+	Password string `json:"password" binding:"required,min=6" example:"password123"`
+	Name     string `json:"name" binding:"required" example:"John Doe"`
 }
 
 // LoginResponse adalah DTO untuk response login
 // @Description Response setelah login berhasil
 type LoginResponse struct {
-	Token string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string       `json:"refresh_token" example:"8c1d...f07a"`
+	ExpiresIn    int64        `json:"expires_in" example:"900"` // Umur access token dalam detik
+	User         UserResponse `json:"user"`
+}
+
+// RefreshTokenRequest adalah DTO untuk request refresh token
+// @Description Request untuk menukar refresh token dengan access token baru (refresh token ikut dirotasi)
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"8c1d...f07a"`
+}
+
+// LogoutRequest adalah DTO untuk request logout
+// @Description Request logout. all_devices=true mencabut semua sesi user.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"8c1d...f07a"`
+	AllDevices   bool   `json:"all_devices" example:"false"`
+}
+
+// ClientInfo berisi informasi client yang dicatat bersama refresh token (bukan bagian dari body request)
+type ClientInfo struct {
+	UserAgent string
+	IP        string
 }
 
 // UserResponse dipindahkan ke dto/user.go untuk konsistensi
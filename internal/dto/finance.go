@@ -0,0 +1,91 @@
+package dto
+
+import "time"
+
+// ===========================
+// REQUEST DTOs
+// ===========================
+
+// ListHutangRequest adalah DTO untuk filter list hutang supplier
+type ListHutangRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	IDPemasok  *uint  `form:"id_pemasok"`
+	Status     string `form:"status" binding:"omitempty,oneof=unpaid partially_paid paid"`
+	JatuhTempo bool   `form:"jatuh_tempo"` // true = hanya yang sudah lewat jatuh tempo
+}
+
+// CreatePembayaranHutangRequest adalah DTO untuk mencatat pembayaran hutang (cicilan / pelunasan).
+// Bisa dikirim sebagai JSON atau multipart/form-data (dengan file bukti_pembayaran).
+type CreatePembayaranHutangRequest struct {
+	Jumlah           float64    `json:"jumlah" form:"jumlah" binding:"required,gt=0"`
+	MetodePembayaran string     `json:"metode_pembayaran" form:"metode_pembayaran" binding:"required,oneof=cash transfer"`
+	TanggalBayar     *time.Time `json:"tanggal_bayar" form:"tanggal_bayar" time_format:"2006-01-02"` // Opsional, default sekarang
+	Keterangan       string     `json:"keterangan" form:"keterangan"`
+}
+
+// AgingReportRequest adalah DTO untuk filter laporan umur hutang/piutang
+type AgingReportRequest struct {
+	Tanggal   *time.Time `form:"tanggal" time_format:"2006-01-02"` // Tanggal acuan, default hari ini
+	IDPemasok *uint      `form:"id_pemasok"`
+}
+
+// ===========================
+// RESPONSE DTOs
+// ===========================
+
+// HutangResponse adalah DTO untuk response hutang supplier
+type HutangResponse struct {
+	ID              uint                       `json:"id"`
+	IDPemasok       uint                       `json:"id_pemasok"`
+	NamaPemasok     string                     `json:"nama_pemasok"`
+	IDPO            *uint                      `json:"id_po"`
+	IDBarangMasuk   *uint                      `json:"id_barang_masuk"`
+	Jumlah          float64                    `json:"jumlah"`
+	JumlahDibayar   float64                    `json:"jumlah_dibayar"`
+	SisaHutang      float64                    `json:"sisa_hutang"`
+	JatuhTempo      *time.Time                 `json:"jatuh_tempo"`
+	HariTerlambat   int                        `json:"hari_terlambat"` // 0 jika belum jatuh tempo
+	Status          string                     `json:"status"`
+	BuktiPembayaran string                     `json:"bukti_pembayaran,omitempty"`
+	DibayarPada     *time.Time                 `json:"dibayar_pada"`
+	DibuatPada      time.Time                  `json:"dibuat_pada"`
+	Pembayaran      []PembayaranHutangResponse `json:"pembayaran,omitempty"`
+}
+
+// PembayaranHutangResponse adalah DTO untuk riwayat pembayaran hutang
+type PembayaranHutangResponse struct {
+	ID               uint      `json:"id"`
+	Jumlah           float64   `json:"jumlah"`
+	MetodePembayaran string    `json:"metode_pembayaran"`
+	TanggalBayar     time.Time `json:"tanggal_bayar"`
+	BuktiPembayaran  string    `json:"bukti_pembayaran,omitempty"`
+	Keterangan       string    `json:"keterangan"`
+	NamaPembayar     string    `json:"nama_pembayar"`
+	DibuatPada       time.Time `json:"dibuat_pada"`
+}
+
+// AgingBucket adalah total sisa per kelompok umur (berdasarkan hari lewat jatuh tempo)
+type AgingBucket struct {
+	BelumJatuhTempo float64 `json:"belum_jatuh_tempo"`
+	Hari1Sampai30   float64 `json:"hari_1_30"`
+	Hari31Sampai60  float64 `json:"hari_31_60"`
+	Hari61Sampai90  float64 `json:"hari_61_90"`
+	LebihDari90     float64 `json:"lebih_90"`
+	Total           float64 `json:"total"`
+}
+
+// AgingPemasokRow adalah baris laporan umur hutang per supplier
+type AgingPemasokRow struct {
+	IDPemasok   uint        `json:"id_pemasok"`
+	NamaPemasok string      `json:"nama_pemasok"`
+	JumlahNota  int         `json:"jumlah_nota"`
+	Aging       AgingBucket `json:"aging"`
+}
+
+// AgingHutangResponse adalah DTO laporan umur hutang supplier
+type AgingHutangResponse struct {
+	Tanggal    time.Time         `json:"tanggal"`
+	Ringkasan  AgingBucket       `json:"ringkasan"`
+	PerPemasok []AgingPemasokRow `json:"per_pemasok"`
+}
@@ -0,0 +1,96 @@
+package dto
+
+import "time"
+
+// CreatePelangganRequest adalah DTO untuk membuat pelanggan baru
+type CreatePelangganRequest struct {
+	Nama    string `json:"nama" binding:"required"`
+	Telepon string `json:"telepon"`
+	Email   string `json:"email" binding:"omitempty,email"`
+	Alamat  string `json:"alamat"`
+	Catatan string `json:"catatan"`
+}
+
+// UpdatePelangganRequest adalah DTO untuk mengupdate pelanggan
+type UpdatePelangganRequest struct {
+	Nama    *string `json:"nama"`
+	Telepon *string `json:"telepon"`
+	Email   *string `json:"email" binding:"omitempty,email"`
+	Alamat  *string `json:"alamat"`
+	Catatan *string `json:"catatan"`
+	Aktif   *bool   `json:"aktif"`
+}
+
+// PelangganResponse adalah DTO untuk response data pelanggan
+type PelangganResponse struct {
+	ID             uint      `json:"id"`
+	Nama           string    `json:"nama"`
+	Telepon        string    `json:"telepon"`
+	Email          string    `json:"email"`
+	Alamat         string    `json:"alamat"`
+	Catatan        string    `json:"catatan"`
+	Aktif          bool      `json:"aktif"`
+	DibuatPada     time.Time `json:"dibuat_pada"`
+	DiperbaruiPada time.Time `json:"diperbarui_pada"`
+}
+
+// ListPelangganRequest adalah DTO untuk filter list pelanggan
+type ListPelangganRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search string `form:"search"` // Nama, Telepon, or Email
+	Aktif  *bool  `form:"aktif"`
+}
+
+// ListPelangganResponse adalah DTO untuk response list pelanggan
+type ListPelangganResponse struct {
+	Pelanggan  []PelangganResponse `json:"customers"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// PelangganDetailResponse adalah DTO untuk detail pelanggan beserta riwayat pembelian.
+// Hanya penjualan terjual (POS completed, pesanan delivered/paid) dan retur completed yang dihitung ke total.
+type PelangganDetailResponse struct {
+	PelangganResponse
+	TotalTransaksi    int64                    `json:"total_transaksi"`
+	TotalBelanja      float64                  `json:"total_belanja"`
+	TotalRetur        int64                    `json:"total_retur"`
+	NilaiRetur        float64                  `json:"nilai_retur"`
+	BelanjaBersih     float64                  `json:"belanja_bersih"` // TotalBelanja - NilaiRetur
+	PembelianPertama  *time.Time               `json:"pembelian_pertama"`
+	PembelianTerakhir *time.Time               `json:"pembelian_terakhir"`
+	PenjualanTerakhir []PelangganSaleHistory   `json:"penjualan_terakhir"`
+	ReturTerakhir     []PelangganReturnHistory `json:"retur_terakhir"`
+}
+
+// PelangganSaleHistory adalah ringkasan satu penjualan pada riwayat pelanggan
+type PelangganSaleHistory struct {
+	ID             uint      `json:"id"`
+	NomorTransaksi string    `json:"nomor_transaksi"`
+	NamaGudang     string    `json:"nama_gudang"`
+	Total          float64   `json:"total"`
+	Status         string    `json:"status"`
+	DibuatPada     time.Time `json:"dibuat_pada"`
+}
+
+// PelangganReturnHistory adalah ringkasan satu retur penjualan pada riwayat pelanggan
+type PelangganReturnHistory struct {
+	ID             uint      `json:"id"`
+	NomorRetur     string    `json:"nomor_retur"`
+	NomorTransaksi string    `json:"nomor_transaksi"`
+	Alasan         string    `json:"alasan"`
+	Total          float64   `json:"total"`
+	Status         string    `json:"status"`
+	DibuatPada     time.Time `json:"dibuat_pada"`
+}
+
+// BackfillPelangganResult adalah hasil migrasi nama pelanggan lama ke master pelanggan
+type BackfillPelangganResult struct {
+	PelangganDibuat int   `json:"pelanggan_dibuat"`
+	PenjualanDilink int64 `json:"penjualan_dilink"`
+	ReturDilink     int64 `json:"retur_dilink"`
+	NamaDilewati    int   `json:"nama_dilewati"` // Nama generik seperti "Umum" tanpa nomor telepon
+}
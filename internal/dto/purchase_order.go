@@ -0,0 +1,99 @@
+package dto
+
+import "time"
+
+// ===========================
+// REQUEST DTOs
+// ===========================
+
+// CreatePurchaseOrderRequest adalah DTO untuk membuat Purchase Order baru (status awal: draft)
+type CreatePurchaseOrderRequest struct {
+	IDPemasok         uint                       `json:"id_pemasok" binding:"required"`
+	TanggalPesan      *time.Time                 `json:"tanggal_pesan"`       // Opsional, default sekarang
+	TanggalJatuhTempo *time.Time                 `json:"tanggal_jatuh_tempo"` // Opsional, jatuh tempo pembayaran ke supplier
+	Items             []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// UpdatePurchaseOrderRequest adalah DTO untuk mengubah PO (hanya saat status draft).
+// Jika Items dikirim, seluruh item lama diganti dengan item baru.
+type UpdatePurchaseOrderRequest struct {
+	IDPemasok         *uint                      `json:"id_pemasok"`
+	TanggalPesan      *time.Time                 `json:"tanggal_pesan"`
+	TanggalJatuhTempo *time.Time                 `json:"tanggal_jatuh_tempo"`
+	Items             []PurchaseOrderItemRequest `json:"items" binding:"omitempty,min=1,dive"`
+}
+
+// PurchaseOrderItemRequest adalah DTO untuk setiap item dalam Purchase Order
+type PurchaseOrderItemRequest struct {
+	IDProduk    uint    `json:"id_produk" binding:"required"`
+	Jumlah      int     `json:"jumlah" binding:"required,min=1"`
+	HargaSatuan float64 `json:"harga_satuan" binding:"required,gt=0"` // Harga beli dari supplier
+}
+
+// ListPurchaseOrderRequest adalah DTO untuk filter list Purchase Order
+type ListPurchaseOrderRequest struct {
+	Page          int        `form:"page" binding:"omitempty,min=1"`
+	Limit         int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	IDPemasok     *uint      `form:"id_pemasok"`
+	Status        string     `form:"status" binding:"omitempty,oneof=draft sent approved partially_received completed cancelled"`
+	TanggalDari   *time.Time `form:"tanggal_dari" time_format:"2006-01-02"`
+	TanggalSampai *time.Time `form:"tanggal_sampai" time_format:"2006-01-02"`
+}
+
+// ReceivePurchaseOrderRequest adalah DTO untuk penerimaan barang berdasarkan PO.
+// Boleh menerima sebagian item / sebagian qty (partial receipt).
+type ReceivePurchaseOrderRequest struct {
+	IDGudang      uint                              `json:"id_gudang" binding:"required"`
+	TanggalTerima *time.Time                        `json:"tanggal_terima"` // Opsional, default sekarang
+	Keterangan    string                            `json:"keterangan"`
+	Items         []ReceivePurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// ReceivePurchaseOrderItemRequest adalah DTO untuk setiap item yang diterima
+type ReceivePurchaseOrderItemRequest struct {
+	IDItemPO uint   `json:"id_item_po" binding:"required"` // ID ItemPesananPembelian
+	Jumlah   int    `json:"jumlah" binding:"required,min=1"`
+	Lokasi   string `json:"lokasi"` // "Rak A, Slot B"
+}
+
+// ===========================
+// RESPONSE DTOs
+// ===========================
+
+// PurchaseOrderResponse adalah DTO untuk response Purchase Order
+type PurchaseOrderResponse struct {
+	ID                uint                        `json:"id"`
+	NomorPO           string                      `json:"nomor_po"`
+	IDPemasok         uint                        `json:"id_pemasok"`
+	NamaPemasok       string                      `json:"nama_pemasok"`
+	TanggalPesan      time.Time                   `json:"tanggal_pesan"`
+	TanggalJatuhTempo *time.Time                  `json:"tanggal_jatuh_tempo"`
+	Status            string                      `json:"status"`
+	Total             float64                     `json:"total"`
+	NamaPembuat       string                      `json:"nama_pembuat"`
+	NamaPenyetuju     string                      `json:"nama_penyetuju,omitempty"`
+	DisetujuiPada     *time.Time                  `json:"disetujui_pada,omitempty"`
+	DibuatPada        time.Time                   `json:"dibuat_pada"`
+	DiperbaruiPada    time.Time                   `json:"diperbarui_pada"`
+	Items             []PurchaseOrderItemResponse `json:"items,omitempty"`
+}
+
+// PurchaseOrderItemResponse adalah DTO untuk item Purchase Order
+type PurchaseOrderItemResponse struct {
+	ID             uint    `json:"id"`
+	IDProduk       uint    `json:"id_produk"`
+	SKUProduk      string  `json:"sku_produk"`
+	NamaProduk     string  `json:"nama_produk"`
+	Jumlah         int     `json:"jumlah"`
+	HargaSatuan    float64 `json:"harga_satuan"`
+	Subtotal       float64 `json:"subtotal"`
+	JumlahDiterima int     `json:"jumlah_diterima"`
+	SisaDiterima   int     `json:"sisa_diterima"` // Jumlah - JumlahDiterima
+}
+
+// ReceivePurchaseOrderResponse adalah DTO hasil penerimaan barang dari PO
+type ReceivePurchaseOrderResponse struct {
+	IDBarangMasuk  uint                   `json:"id_barang_masuk"`
+	NomorTransaksi string                 `json:"nomor_transaksi"`
+	PurchaseOrder  *PurchaseOrderResponse `json:"purchase_order"`
+}
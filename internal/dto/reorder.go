@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// ReorderSuggestionRequest adalah parameter perhitungan saran pemesanan ulang
+type ReorderSuggestionRequest struct {
+	IDGudang       *uint `form:"id_gudang" json:"id_gudang"`
+	IDPemasok      *uint `form:"id_pemasok" json:"id_pemasok"`
+	PeriodeHari    int   `form:"periode_hari" json:"periode_hari" binding:"omitempty,min=7,max=365"`     // Riwayat penjualan yang dihitung, default 30 hari
+	LeadTimeHari   int   `form:"lead_time_hari" json:"lead_time_hari" binding:"omitempty,min=1,max=180"` // Lama barang datang setelah dipesan, default 7 hari
+	TargetHari     int   `form:"target_hari" json:"target_hari" binding:"omitempty,min=1,max=180"`       // Stok cukup untuk berapa hari setelah barang datang, default 14 hari
+	TampilkanSemua bool  `form:"tampilkan_semua" json:"tampilkan_semua"`                                 // Tampilkan juga produk yang belum perlu dipesan
+}
+
+// GenerateReorderRequest adalah DTO untuk membuat draft PO (satu per pemasok) dari saran pemesanan ulang
+type GenerateReorderRequest struct {
+	ReorderSuggestionRequest
+	TanggalJatuhTempo *time.Time `json:"tanggal_jatuh_tempo"` // Opsional, dipakai di semua PO yang dibuat
+}
+
+// ReorderSuggestionItem adalah saran pemesanan untuk satu produk di satu gudang
+type ReorderSuggestionItem struct {
+	IDProduk        uint     `json:"id_produk"`
+	SKU             string   `json:"sku"`
+	NamaProduk      string   `json:"nama_produk"`
+	IDGudang        uint     `json:"id_gudang"`
+	NamaGudang      string   `json:"nama_gudang"`
+	StokSaatIni     int      `json:"stok_saat_ini"`
+	StokMinimum     int      `json:"stok_minimum"`
+	TerjualPeriode  int      `json:"terjual_periode"`
+	RataRataHarian  float64  `json:"rata_rata_harian"`
+	SisaHari        *float64 `json:"sisa_hari"`         // Days of cover; null jika tidak ada penjualan
+	DalamPesanan    int      `json:"dalam_pesanan"`     // Qty PO terbuka yang dialokasikan ke baris ini
+	TitikPesanUlang float64  `json:"titik_pesan_ulang"` // Rata-rata harian × lead time + stok minimum
+	SaranJumlah     int      `json:"saran_jumlah"`
+	HargaSatuan     float64  `json:"harga_satuan"` // Harga modal produk
+	Subtotal        float64  `json:"subtotal"`
+}
+
+// ReorderSupplierGroup adalah saran pemesanan yang dikelompokkan per pemasok
+type ReorderSupplierGroup struct {
+	IDPemasok   *uint                   `json:"id_pemasok"` // null = produk belum punya pemasok
+	NamaPemasok string                  `json:"nama_pemasok"`
+	TotalNilai  float64                 `json:"total_nilai"`
+	Items       []ReorderSuggestionItem `json:"items"`
+}
+
+// ReorderSuggestionResponse adalah DTO hasil perhitungan saran pemesanan ulang
+type ReorderSuggestionResponse struct {
+	PeriodeHari  int                    `json:"periode_hari"`
+	LeadTimeHari int                    `json:"lead_time_hari"`
+	TargetHari   int                    `json:"target_hari"`
+	DihitungPada time.Time              `json:"dihitung_pada"`
+	Pemasok      []ReorderSupplierGroup `json:"pemasok"`
+}
+
+// GenerateReorderResponse adalah DTO hasil pembuatan draft PO dari saran pemesanan ulang
+type GenerateReorderResponse struct {
+	PurchaseOrders []PurchaseOrderResponse `json:"purchase_orders"`
+	Dilewati       []string                `json:"dilewati,omitempty"` // Produk yang tidak bisa dipesan (tanpa pemasok / harga modal)
+}
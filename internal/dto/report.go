@@ -81,6 +81,7 @@ type SalesReportByCustomerResponse struct {
 
 // CustomerSalesSummary adalah ringkasan penjualan per pelanggan
 type CustomerSalesSummary struct {
+	IDPelanggan     *uint   `json:"id_pelanggan"` // Kosong untuk penjualan tanpa master pelanggan
 	NamaPelanggan   string  `json:"nama_pelanggan"`
 	KontakPelanggan string  `json:"kontak_pelanggan"`
 	TotalTransaksi  int64   `json:"total_transaksi"`
@@ -208,3 +209,87 @@ type StockReportItem struct {
 	TotalStok      int     `json:"total_stok"`
 	ValuasiModal   float64 `json:"valuasi_modal"` // Total nilai dari sisa stok batch (Stok * Modal)
 }
+
+// ===========================
+// RECEIVABLE (PIUTANG) REPORT DTOs
+// ===========================
+
+// PiutangReportRequest adalah query params untuk daftar & umur piutang pelanggan
+type PiutangReportRequest struct {
+	Tanggal     *time.Time `form:"tanggal" time_format:"2006-01-02"` // Tanggal acuan, default hari ini
+	IDPelanggan *uint      `form:"id_pelanggan"`
+	JatuhTempo  bool       `form:"jatuh_tempo"` // true = hanya yang sudah lewat jatuh tempo
+}
+
+// PiutangItem adalah satu pesanan yang masih memiliki sisa tagihan
+type PiutangItem struct {
+	IDPenjualan     uint       `json:"id_penjualan"`
+	NomorTransaksi  string     `json:"nomor_transaksi"`
+	IDPelanggan     *uint      `json:"id_pelanggan"`
+	NamaPelanggan   string     `json:"nama_pelanggan"`
+	KontakPelanggan string     `json:"kontak_pelanggan"`
+	Status          string     `json:"status"`
+	Total           float64    `json:"total"`
+	TotalDibayar    float64    `json:"total_dibayar"`
+	SisaTagihan     float64    `json:"sisa_tagihan"`
+	JatuhTempo      *time.Time `json:"jatuh_tempo"`
+	HariTerlambat   int        `json:"hari_terlambat"` // 0 jika belum jatuh tempo
+	DibuatPada      time.Time  `json:"dibuat_pada"`
+}
+
+// PiutangReportResponse adalah buku piutang pelanggan per tanggal acuan
+type PiutangReportResponse struct {
+	Tanggal      time.Time     `json:"tanggal"`
+	TotalPiutang float64       `json:"total_piutang"`
+	Items        []PiutangItem `json:"items"`
+}
+
+// AgingPelangganRow adalah baris laporan umur piutang per pelanggan
+type AgingPelangganRow struct {
+	IDPelanggan   *uint       `json:"id_pelanggan"` // nil untuk pesanan tanpa master pelanggan
+	NamaPelanggan string      `json:"nama_pelanggan"`
+	JumlahNota    int         `json:"jumlah_nota"`
+	Aging         AgingBucket `json:"aging"`
+}
+
+// AgingPiutangResponse adalah DTO laporan umur piutang pelanggan
+type AgingPiutangResponse struct {
+	Tanggal      time.Time           `json:"tanggal"`
+	Ringkasan    AgingBucket         `json:"ringkasan"`
+	PerPelanggan []AgingPelangganRow `json:"per_pelanggan"`
+}
+
+// CustomerStatementRequest adalah query params untuk rekening koran (statement of account) pelanggan
+type CustomerStatementRequest struct {
+	TanggalDari   *time.Time `form:"tanggal_dari" time_format:"2006-01-02"`   // Default awal bulan berjalan
+	TanggalSampai *time.Time `form:"tanggal_sampai" time_format:"2006-01-02"` // Default hari ini
+}
+
+// CustomerStatementLine adalah satu mutasi pada rekening koran pelanggan
+type CustomerStatementLine struct {
+	Tanggal        time.Time `json:"tanggal"`
+	Jenis          string    `json:"jenis"` // pesanan, dp, pembayaran
+	IDPenjualan    uint      `json:"id_penjualan"`
+	NomorTransaksi string    `json:"nomor_transaksi"`
+	Keterangan     string    `json:"keterangan"`
+	Debit          float64   `json:"debit"`  // Tagihan
+	Kredit         float64   `json:"kredit"` // Pembayaran
+	Saldo          float64   `json:"saldo"`  // Saldo berjalan
+}
+
+// CustomerStatementResponse adalah rekening koran piutang satu pelanggan
+type CustomerStatementResponse struct {
+	IDPelanggan    uint                    `json:"id_pelanggan"`
+	NamaPelanggan  string                  `json:"nama_pelanggan"`
+	Telepon        string                  `json:"telepon"`
+	Alamat         string                  `json:"alamat"`
+	TanggalDari    time.Time               `json:"tanggal_dari"`
+	TanggalSampai  time.Time               `json:"tanggal_sampai"`
+	SaldoAwal      float64                 `json:"saldo_awal"`
+	TotalDebit     float64                 `json:"total_debit"`
+	TotalKredit    float64                 `json:"total_kredit"`
+	SaldoAkhir     float64                 `json:"saldo_akhir"`
+	Aging          AgingBucket             `json:"aging"` // Umur sisa tagihan per tanggal_sampai
+	Mutasi         []CustomerStatementLine `json:"mutasi"`
+	PiutangTerbuka []PiutangItem           `json:"piutang_terbuka"`
+}
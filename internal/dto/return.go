@@ -28,6 +28,7 @@ type ReturPenjualanResponse struct {
 	NomorRetur         string                       `json:"nomor_retur"`
 	IDPenjualan        uint                         `json:"id_penjualan"`
 	NomorTransaksiAsal string                       `json:"nomor_transaksi_asal"`
+	IDPelanggan        *uint                        `json:"id_pelanggan"`
 	NamaPelanggan      string                       `json:"nama_pelanggan"`
 	KontakPelanggan    string                       `json:"kontak_pelanggan"`
 	Alasan             string                       `json:"alasan"`
@@ -38,6 +39,10 @@ type ReturPenjualanResponse struct {
 	Status             string                       `json:"status"`
 	Keterangan         string                       `json:"keterangan"`
 	NamaPetugas        string                       `json:"nama_petugas"`
+	DisetujuiPada      *time.Time                   `json:"disetujui_pada,omitempty"`
+	AlasanPenolakan    string                       `json:"alasan_penolakan,omitempty"`
+	DitolakPada        *time.Time                   `json:"ditolak_pada,omitempty"`
+	DiselesaikanPada   *time.Time                   `json:"diselesaikan_pada,omitempty"`
 	DibuatPada         time.Time                    `json:"dibuat_pada"`
 	Items              []ReturPenjualanItemResponse `json:"items,omitempty"`
 }
@@ -62,6 +67,11 @@ type ListReturPenjualanRequest struct {
 	Status        string     `form:"status" binding:"omitempty,oneof=pending approved completed rejected"`
 }
 
+// RejectReturRequest adalah DTO untuk menolak retur (penjualan maupun pembelian)
+type RejectReturRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
+
 // ===========================
 // RETUR PEMBELIAN (Toko → Vendor/Supplier)
 // ===========================
@@ -96,6 +106,10 @@ type ReturPembelianResponse struct {
 	Status             string                       `json:"status"`
 	Keterangan         string                       `json:"keterangan"`
 	NamaPembuat        string                       `json:"nama_pembuat"`
+	DisetujuiPada      *time.Time                   `json:"disetujui_pada,omitempty"`
+	AlasanPenolakan    string                       `json:"alasan_penolakan,omitempty"`
+	DitolakPada        *time.Time                   `json:"ditolak_pada,omitempty"`
+	DiselesaikanPada   *time.Time                   `json:"diselesaikan_pada,omitempty"`
 	DibuatPada         time.Time                    `json:"dibuat_pada"`
 	Items              []ReturPembelianItemResponse `json:"items,omitempty"`
 }
@@ -118,3 +132,64 @@ type ListReturPembelianRequest struct {
 	IDPemasok *uint  `form:"id_pemasok"`
 	Status    string `form:"status" binding:"omitempty,oneof=pending approved completed rejected"`
 }
+
+// ===========================
+// STOK KARANTINA (Disposisi barang retur penjualan)
+// ===========================
+
+// ListStokKarantinaRequest adalah DTO untuk filter list batch karantina
+type ListStokKarantinaRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	IDProduk *uint  `form:"id_produk"`
+	IDGudang *uint  `form:"id_gudang"`
+	Status   string `form:"status" binding:"omitempty,oneof=karantina perbaikan"`
+}
+
+// StokKarantinaBatchResponse adalah DTO untuk batch yang sedang dikarantina / diperbaiki
+type StokKarantinaBatchResponse struct {
+	IDBatch      uint      `json:"id_batch"`
+	IDProduk     uint      `json:"id_produk"`
+	SKUProduk    string    `json:"sku_produk"`
+	NamaProduk   string    `json:"nama_produk"`
+	IDGudang     uint      `json:"id_gudang"`
+	NamaGudang   string    `json:"nama_gudang"`
+	Status       string    `json:"status"` // karantina, perbaikan
+	JumlahUnit   int       `json:"jumlah_unit"`
+	HargaModal   float64   `json:"harga_modal"`
+	Keterangan   string    `json:"keterangan"`
+	TanggalMasuk time.Time `json:"tanggal_masuk"`
+}
+
+// DisposisiKarantinaRequest adalah DTO untuk release / write-off / repair stok karantina
+type DisposisiKarantinaRequest struct {
+	Jumlah     int    `json:"jumlah" binding:"required,min=1"`
+	Keterangan string `json:"keterangan"`
+}
+
+// ListDisposisiKarantinaRequest adalah DTO untuk filter audit trail disposisi karantina
+type ListDisposisiKarantinaRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	IDBatch  *uint  `form:"id_batch"`
+	IDProduk *uint  `form:"id_produk"`
+	Aksi     string `form:"aksi" binding:"omitempty,oneof=release write_off repair"`
+}
+
+// DisposisiKarantinaResponse adalah DTO untuk response disposisi karantina
+type DisposisiKarantinaResponse struct {
+	ID           uint      `json:"id"`
+	IDBatch      uint      `json:"id_batch"`
+	IDBatchHasil *uint     `json:"id_batch_hasil"`
+	IDProduk     uint      `json:"id_produk"`
+	SKUProduk    string    `json:"sku_produk"`
+	NamaProduk   string    `json:"nama_produk"`
+	IDGudang     uint      `json:"id_gudang"`
+	NamaGudang   string    `json:"nama_gudang"`
+	Aksi         string    `json:"aksi"`
+	Jumlah       int       `json:"jumlah"`
+	NilaiModal   float64   `json:"nilai_modal"`
+	Keterangan   string    `json:"keterangan"`
+	NamaPetugas  string    `json:"nama_petugas"`
+	DibuatPada   time.Time `json:"dibuat_pada"`
+}
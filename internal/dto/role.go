@@ -0,0 +1,39 @@
+package dto
+
+// RoleResponse adalah DTO untuk response role
+// @Description Role beserta daftar kode permission
+type RoleResponse struct {
+	ID        uint     `json:"id" example:"5"`
+	Kode      string   `json:"kode" example:"supervisor_toko"`
+	Nama      string   `json:"nama" example:"Supervisor Toko"`
+	Deskripsi string   `json:"deskripsi" example:"Supervisor cabang kedua"`
+	Sistem    bool     `json:"sistem" example:"false"`
+	Aktif     bool     `json:"aktif" example:"true"`
+	Izin      []string `json:"izin" example:"sales.read,sales.create,sales.void"`
+}
+
+// PermissionResponse adalah DTO untuk katalog permission
+// @Description Permission yang bisa diberikan ke role
+type PermissionResponse struct {
+	Kode      string `json:"kode" example:"sales.void"`
+	Modul     string `json:"modul" example:"sales"`
+	Deskripsi string `json:"deskripsi" example:"Void penjualan"`
+}
+
+// CreateRoleRequest adalah DTO untuk request create role
+// @Description Request untuk membuat role custom
+type CreateRoleRequest struct {
+	Kode      string   `json:"kode" binding:"required,max=50" example:"supervisor_toko"`
+	Nama      string   `json:"nama" binding:"required,max=100" example:"Supervisor Toko"`
+	Deskripsi string   `json:"deskripsi" example:"Supervisor cabang kedua"`
+	Izin      []string `json:"izin" binding:"required,min=1" example:"sales.read,sales.create,sales.void"`
+}
+
+// UpdateRoleRequest adalah DTO untuk request update role
+// @Description Request untuk mengupdate role (semua field optional, izin menggantikan seluruh permission)
+type UpdateRoleRequest struct {
+	Nama      *string  `json:"nama,omitempty" binding:"omitempty,max=100" example:"Supervisor Toko"`
+	Deskripsi *string  `json:"deskripsi,omitempty" example:"Supervisor cabang kedua"`
+	Aktif     *bool    `json:"aktif,omitempty" example:"true"`
+	Izin      []string `json:"izin,omitempty" binding:"omitempty,min=1" example:"sales.read,sales.create"`
+}
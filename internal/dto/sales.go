@@ -8,15 +8,30 @@ import "time"
 
 // CreateSalesRequest adalah DTO untuk membuat transaksi penjualan baru (Mode 1: POS)
 // Untuk pembayaran transfer dengan bukti foto, gunakan multipart/form-data.
-// Field JSON dikirim sebagai field "data" (string JSON), file sebagai "bukti_bayar".
+// Field JSON dikirim sebagai field "data" (string JSON), file sebagai "bukti_bayar"
+// atau "bukti_pembayaran_<index>" untuk bukti per baris pembayaran.
+//
+// Split tender: isi Pembayaran (beberapa metode). MetodePembayaran + JumlahPembayaran
+// tetap didukung untuk pembayaran satu metode.
 type CreateSalesRequest struct {
-	IDGudang         uint               `json:"id_gudang" binding:"required"`
-	NamaPelanggan    string             `json:"nama_pelanggan"`   // Opsional
-	KontakPelanggan  string             `json:"kontak_pelanggan"` // Opsional
-	MetodePembayaran string             `json:"metode_pembayaran" binding:"required,oneof=cash transfer"`
-	JumlahPembayaran float64            `json:"jumlah_pembayaran" binding:"required,gt=0"`
-	CatatanInternal  string             `json:"catatan_internal"`
-	Items            []SalesItemRequest `json:"items" binding:"required,min=1,dive"`
+	IDGudang         uint                      `json:"id_gudang" binding:"required"`
+	IDPelanggan      *uint                     `json:"id_pelanggan"`     // Opsional, nama & kontak diisi dari master jika kosong
+	NamaPelanggan    string                    `json:"nama_pelanggan"`   // Opsional
+	KontakPelanggan  string                    `json:"kontak_pelanggan"` // Opsional
+	MetodePembayaran string                    `json:"metode_pembayaran" binding:"omitempty,oneof=cash transfer qris debit"`
+	JumlahPembayaran float64                   `json:"jumlah_pembayaran" binding:"omitempty,gt=0"`
+	Pembayaran       []SalesPaymentLineRequest `json:"pembayaran" binding:"omitempty,dive"`
+	CatatanInternal  string                    `json:"catatan_internal"`
+	Items            []SalesItemRequest        `json:"items" binding:"required,min=1,dive"`
+}
+
+// SalesPaymentLineRequest adalah satu baris pembayaran POS (split tender).
+// Untuk cash, jumlah adalah uang yang diterima; kembalian dihitung dari porsi cash saja.
+type SalesPaymentLineRequest struct {
+	MetodePembayaran string  `json:"metode_pembayaran" binding:"required,oneof=cash transfer qris debit"`
+	Jumlah           float64 `json:"jumlah" binding:"required,gt=0"`
+	NomorReferensi   string  `json:"nomor_referensi"` // Opsional, no. referensi transfer / QRIS / EDC
+	BuktiPembayaran  string  `json:"-"`               // Diisi handler dari file bukti_pembayaran_<index>
 }
 
 // SalesItemRequest adalah DTO untuk setiap item dalam transaksi penjualan
@@ -35,7 +50,15 @@ type ListSalesRequest struct {
 	TanggalSampai    *time.Time `form:"tanggal_sampai" time_format:"2006-01-02"`
 	IDKasir          *uint      `form:"id_kasir"`
 	IDGudang         *uint      `form:"id_gudang"`
-	MetodePembayaran string     `form:"metode_pembayaran" binding:"omitempty,oneof=cash transfer"`
+	IDPelanggan      *uint      `form:"id_pelanggan"`
+	MetodePembayaran string     `form:"metode_pembayaran" binding:"omitempty,oneof=cash transfer qris debit"` // Transaksi yang memiliki baris pembayaran dengan metode ini
+	TipePenjualan    string     `form:"tipe_penjualan" binding:"omitempty,oneof=pos pesanan"`
+	Status           string     `form:"status"`
+}
+
+// VoidSalesRequest adalah DTO untuk membatalkan (void) transaksi penjualan
+type VoidSalesRequest struct {
+	Alasan string `json:"alasan" binding:"required"` // Wajib diisi untuk audit
 }
 
 // ===========================
@@ -46,12 +69,16 @@ type ListSalesRequest struct {
 type SalesResponse struct {
 	ID               uint      `json:"id"`
 	NomorTransaksi   string    `json:"nomor_transaksi"`
+	TipePenjualan    string    `json:"tipe_penjualan"`
 	NamaGudang       string    `json:"nama_gudang"`
+	IDPelanggan      *uint     `json:"id_pelanggan"`
 	NamaPelanggan    string    `json:"nama_pelanggan"`
 	KontakPelanggan  string    `json:"kontak_pelanggan"`
 	Total            float64   `json:"total"`
 	TotalHargaModal  float64   `json:"total_harga_modal"` // COGS total
 	Laba             float64   `json:"laba"`              // Total - TotalHargaModal
+	TotalDibayar     float64   `json:"total_dibayar"`
+	SisaTagihan      float64   `json:"sisa_tagihan"` // Pesanan: Total - TotalDibayar
 	MetodePembayaran string    `json:"metode_pembayaran"`
 	Status           string    `json:"status"`
 	NamaKasir        string    `json:"nama_kasir"`
@@ -87,8 +114,10 @@ type SalesBatchUsageResponse struct {
 type SalesDetailResponse struct {
 	ID               uint                `json:"id"`
 	NomorTransaksi   string              `json:"nomor_transaksi"`
+	TipePenjualan    string              `json:"tipe_penjualan"`
 	IDGudang         uint                `json:"id_gudang"`
 	NamaGudang       string              `json:"nama_gudang"`
+	IDPelanggan      *uint               `json:"id_pelanggan"`
 	NamaPelanggan    string              `json:"nama_pelanggan"`
 	KontakPelanggan  string              `json:"kontak_pelanggan"`
 	Subtotal         float64             `json:"subtotal"`
@@ -99,13 +128,40 @@ type SalesDetailResponse struct {
 	MetodePembayaran string              `json:"metode_pembayaran"`
 	JumlahPembayaran float64             `json:"jumlah_pembayaran"`
 	JumlahKembalian  float64             `json:"jumlah_kembalian"`
+	TotalDibayar     float64             `json:"total_dibayar"`
+	SisaTagihan      float64             `json:"sisa_tagihan"`
 	BuktiBayar       *string             `json:"bukti_bayar,omitempty"`
 	Status           string              `json:"status"`
 	CatatanInternal  string              `json:"catatan_internal,omitempty"`
 	IDKasir          uint                `json:"id_kasir"`
 	NamaKasir        string              `json:"nama_kasir"`
 	DibuatPada       time.Time           `json:"dibuat_pada"`
+	AlasanPembatalan string              `json:"alasan_pembatalan,omitempty"`
+	NamaPembatal     string              `json:"nama_pembatal,omitempty"`
+	DibatalkanPada   *time.Time          `json:"dibatalkan_pada,omitempty"`
 	Items            []SalesItemResponse `json:"items"`
+
+	// Pesanan (Mode 2)
+	AlamatPengiriman  string                 `json:"alamat_pengiriman,omitempty"`
+	TanggalPengiriman *time.Time             `json:"tanggal_pengiriman,omitempty"`
+	DisiapkanPada     *time.Time             `json:"disiapkan_pada,omitempty"`
+	DikirimPada       *time.Time             `json:"dikirim_pada,omitempty"`
+	LunasPada         *time.Time             `json:"lunas_pada,omitempty"`
+	JatuhTempo        *time.Time             `json:"jatuh_tempo,omitempty"`
+	Pembayaran        []SalesPaymentResponse `json:"pembayaran,omitempty"`
+}
+
+// SalesPaymentResponse adalah DTO untuk satu pembayaran pesanan (DP / cicilan / pelunasan)
+type SalesPaymentResponse struct {
+	ID               uint      `json:"id"`
+	Jenis            string    `json:"jenis"`
+	MetodePembayaran string    `json:"metode_pembayaran"`
+	Jumlah           float64   `json:"jumlah"`
+	NomorReferensi   string    `json:"nomor_referensi,omitempty"`
+	Keterangan       string    `json:"keterangan,omitempty"`
+	BuktiPembayaran  string    `json:"bukti_pembayaran,omitempty"`
+	NamaPenerima     string    `json:"nama_penerima"`
+	DibuatPada       time.Time `json:"dibuat_pada"`
 }
 
 // InvoiceResponse adalah DTO yang dioptimalkan untuk keperluan cetak / ekspor invoice
@@ -135,6 +191,9 @@ type InvoiceResponse struct {
 	JumlahPembayaran float64 `json:"jumlah_pembayaran"`
 	JumlahKembalian  float64 `json:"jumlah_kembalian"`
 
+	// Rincian pembayaran per metode (split tender)
+	Pembayaran []SalesPaymentResponse `json:"pembayaran,omitempty"`
+
 	// Status
 	Status string `json:"status"`
 }
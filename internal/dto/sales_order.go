@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateSalesOrderRequest adalah DTO untuk membuat pesanan penjualan (Mode 2: pesanan + DP).
+// Stok belum keluar saat pesanan dibuat; FIFO dijalankan saat pesanan ditandai siap.
+type CreateSalesOrderRequest struct {
+	IDGudang          uint               `json:"id_gudang" binding:"required"`
+	IDPelanggan       *uint              `json:"id_pelanggan"`     // Opsional, nama & kontak diisi dari master jika kosong
+	NamaPelanggan     string             `json:"nama_pelanggan"`   // Wajib jika id_pelanggan kosong
+	KontakPelanggan   string             `json:"kontak_pelanggan"` // Opsional
+	AlamatPengiriman  string             `json:"alamat_pengiriman"`
+	TanggalPengiriman *time.Time         `json:"tanggal_pengiriman"` // Opsional, rencana kirim
+	JatuhTempo        *time.Time         `json:"jatuh_tempo"`        // Opsional, batas pelunasan. Default: tanggal pesanan dikirim
+	JumlahDP          float64            `json:"jumlah_dp" binding:"omitempty,gte=0"`
+	MetodePembayaran  string             `json:"metode_pembayaran" binding:"omitempty,oneof=cash transfer qris debit"` // Metode DP, wajib jika jumlah_dp > 0
+	CatatanInternal   string             `json:"catatan_internal"`
+	Items             []SalesItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// SalesOrderPaymentRequest adalah DTO untuk mencatat pembayaran pesanan (cicilan / pelunasan).
+// Bisa dikirim sebagai JSON atau multipart/form-data (dengan file bukti_pembayaran).
+type SalesOrderPaymentRequest struct {
+	Jumlah           float64 `json:"jumlah" form:"jumlah" binding:"required,gt=0"`
+	MetodePembayaran string  `json:"metode_pembayaran" form:"metode_pembayaran" binding:"required,oneof=cash transfer qris debit"`
+	Keterangan       string  `json:"keterangan" form:"keterangan"`
+}
+
+// UpdateJatuhTempoRequest adalah DTO untuk mengubah batas pelunasan piutang pesanan
+type UpdateJatuhTempoRequest struct {
+	JatuhTempo time.Time `json:"jatuh_tempo" binding:"required"`
+}
+
+// CancelSalesOrderRequest adalah DTO untuk membatalkan pesanan penjualan
+type CancelSalesOrderRequest struct {
+	Alasan string `json:"alasan" binding:"required"`
+}
@@ -0,0 +1,112 @@
+package dto
+
+import "time"
+
+// OpenShiftRequest adalah DTO untuk membuka shift kasir dengan modal kas awal
+type OpenShiftRequest struct {
+	IDGudang uint    `json:"id_gudang" binding:"required"`
+	KasAwal  float64 `json:"kas_awal" binding:"gte=0"`
+	Catatan  string  `json:"catatan"`
+}
+
+// CloseShiftRequest adalah DTO untuk menutup shift.
+// kas_dihitung wajib (uang fisik di laci); metode non-tunai opsional, diisi dari settlement EDC / mutasi rekening.
+type CloseShiftRequest struct {
+	KasDihitung *float64                `json:"kas_dihitung" binding:"required,gte=0"`
+	Dihitung    []ShiftMethodCountInput `json:"dihitung" binding:"omitempty,dive"`
+	Catatan     string                  `json:"catatan"`
+}
+
+// ShiftMethodCountInput adalah jumlah yang dihitung untuk satu metode non-tunai
+type ShiftMethodCountInput struct {
+	MetodePembayaran string  `json:"metode_pembayaran" binding:"required,oneof=transfer qris debit"`
+	Jumlah           float64 `json:"jumlah" binding:"gte=0"`
+}
+
+// ListShiftRequest adalah DTO untuk filter list shift kasir
+type ListShiftRequest struct {
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	IDKasir       uint   `form:"id_kasir"`
+	IDGudang      uint   `form:"id_gudang"`
+	Status        string `form:"status" binding:"omitempty,oneof=open closed"`
+	TanggalDari   string `form:"tanggal_dari"`   // YYYY-MM-DD (tanggal buka)
+	TanggalSampai string `form:"tanggal_sampai"` // YYYY-MM-DD
+}
+
+// ShiftResponse adalah DTO untuk response data shift kasir
+type ShiftResponse struct {
+	ID            uint       `json:"id"`
+	NomorShift    string     `json:"nomor_shift"`
+	IDKasir       uint       `json:"id_kasir"`
+	NamaKasir     string     `json:"nama_kasir"`
+	IDGudang      uint       `json:"id_gudang"`
+	NamaGudang    string     `json:"nama_gudang"`
+	Status        string     `json:"status"`
+	KasAwal       float64    `json:"kas_awal"`
+	KasDiharapkan float64    `json:"kas_diharapkan"`
+	KasDihitung   *float64   `json:"kas_dihitung"`
+	SelisihKas    float64    `json:"selisih_kas"`
+	CatatanBuka   string     `json:"catatan_buka"`
+	CatatanTutup  string     `json:"catatan_tutup"`
+	DibukaPada    time.Time  `json:"dibuka_pada"`
+	DitutupPada   *time.Time `json:"ditutup_pada"`
+	DitutupOleh   *uint      `json:"ditutup_oleh"`
+}
+
+// ListShiftResponse adalah DTO untuk response list shift kasir
+type ListShiftResponse struct {
+	Shifts     []ShiftResponse `json:"shifts"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// ShiftRekapResponse adalah baris rekonsiliasi per metode pembayaran
+type ShiftRekapResponse struct {
+	MetodePembayaran string   `json:"metode_pembayaran"`
+	JumlahTransaksi  int64    `json:"jumlah_transaksi"`
+	Diharapkan       float64  `json:"diharapkan"`
+	Dihitung         *float64 `json:"dihitung"`
+	Selisih          float64  `json:"selisih"`
+}
+
+// ShiftReportResponse adalah DTO laporan shift siap cetak.
+// Shift closed = Z report (rekap tersimpan saat tutup); shift open = X report (rekap berjalan, belum ada hitungan).
+type ShiftReportResponse struct {
+	// Header
+	JenisLaporan string     `json:"jenis_laporan"` // X (shift berjalan), Z (shift ditutup)
+	NomorShift   string     `json:"nomor_shift"`
+	NamaGudang   string     `json:"nama_gudang"`
+	NamaKasir    string     `json:"nama_kasir"`
+	Status       string     `json:"status"`
+	DibukaPada   time.Time  `json:"dibuka_pada"`
+	DitutupPada  *time.Time `json:"ditutup_pada"`
+	DitutupOleh  string     `json:"ditutup_oleh,omitempty"`
+	DicetakPada  time.Time  `json:"dicetak_pada"`
+
+	// Penjualan POS dalam shift
+	JumlahTransaksi int64   `json:"jumlah_transaksi"`
+	TotalPenjualan  float64 `json:"total_penjualan"`
+	TotalDiskon     float64 `json:"total_diskon"`
+	JumlahVoid      int64   `json:"jumlah_void"`
+	NilaiVoid       float64 `json:"nilai_void"`
+
+	// Laci kas
+	KasAwal         float64  `json:"kas_awal"`
+	PenerimaanTunai float64  `json:"penerimaan_tunai"`
+	JumlahRefund    int64    `json:"jumlah_refund"`
+	RefundTunai     float64  `json:"refund_tunai"`
+	KasDiharapkan   float64  `json:"kas_diharapkan"`
+	KasDihitung     *float64 `json:"kas_dihitung"`
+	SelisihKas      float64  `json:"selisih_kas"`
+
+	// Rekonsiliasi per metode
+	Rekap         []ShiftRekapResponse `json:"rekap"`
+	TotalDiterima float64              `json:"total_diterima"` // Semua metode, tanpa kas awal & refund
+	TotalSelisih  float64              `json:"total_selisih"`
+
+	CatatanBuka  string `json:"catatan_buka,omitempty"`
+	CatatanTutup string `json:"catatan_tutup,omitempty"`
+}
@@ -57,12 +57,98 @@ type BatchResponse struct {
 	CreatedAt     time.Time  `json:"created_at"`
 }
 
-// CreateStockInRequest adalah request untuk barang masuk manual
+// CreateStockInRequest adalah request untuk barang masuk manual.
+// Dokumen dibuat pending — stok baru bertambah setelah di-approve owner.
 type CreateStockInRequest struct {
-	WarehouseID uint               `json:"warehouse_id" binding:"required"`
-	Date        time.Time          `json:"date"` // Optional, default now
-	Notes       string             `json:"notes"`
-	Items       []StockRequestItem `json:"items" binding:"required,dive"`
+	WarehouseID uint                 `json:"warehouse_id" binding:"required"`
+	SupplierID  *uint                `json:"supplier_id"` // Optional, jika diisi hutang supplier dibuat saat approve
+	Date        time.Time            `json:"date"`        // Optional, default now
+	Notes       string               `json:"notes"`
+	Items       []StockInRequestItem `json:"items" binding:"required,dive"`
+}
+
+// StockInRequestItem adalah item barang masuk manual
+type StockInRequestItem struct {
+	ProductID uint     `json:"product_id" binding:"required"`
+	Quantity  int      `json:"quantity" binding:"required,min=1"`
+	UnitPrice *float64 `json:"unit_price" binding:"omitempty,min=0"` // Optional, default harga modal produk
+	Location  string   `json:"location"`                             // "Rak A, Slot B"
+}
+
+// ListStockInRequest adalah filter untuk daftar dokumen barang masuk
+type ListStockInRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status     string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	SupplierID *uint  `form:"supplier_id"`
+}
+
+// RejectStockInRequest adalah request untuk menolak dokumen barang masuk
+type RejectStockInRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// StockInResponse adalah response untuk dokumen barang masuk
+type StockInResponse struct {
+	ID                uint                  `json:"id"`
+	TransactionNumber string                `json:"transaction_number"`
+	SupplierID        *uint                 `json:"supplier_id"`
+	SupplierName      string                `json:"supplier_name,omitempty"`
+	POID              *uint                 `json:"po_id"`
+	Status            string                `json:"status"`
+	Notes             string                `json:"notes"`
+	ReceivedBy        string                `json:"received_by"`
+	ReceivedAt        time.Time             `json:"received_at"`
+	ApprovedBy        string                `json:"approved_by,omitempty"`
+	ApprovedAt        *time.Time            `json:"approved_at"`
+	RejectedBy        string                `json:"rejected_by,omitempty"`
+	RejectedAt        *time.Time            `json:"rejected_at"`
+	RejectionReason   string                `json:"rejection_reason,omitempty"`
+	TotalValue        float64               `json:"total_value"`
+	CreatedAt         time.Time             `json:"created_at"`
+	Items             []StockInItemResponse `json:"items,omitempty"`
+}
+
+// StockInItemResponse adalah response untuk item barang masuk
+type StockInItemResponse struct {
+	ID            uint    `json:"id"`
+	ProductID     uint    `json:"product_id"`
+	ProductSKU    string  `json:"product_sku"`
+	ProductName   string  `json:"product_name"`
+	WarehouseID   uint    `json:"warehouse_id"`
+	WarehouseName string  `json:"warehouse_name"`
+	Quantity      int     `json:"quantity"`
+	UnitPrice     float64 `json:"unit_price"`
+	Subtotal      float64 `json:"subtotal"`
+	Location      string  `json:"location"`
+}
+
+// StockOutResponse adalah response untuk dokumen barang keluar
+type StockOutResponse struct {
+	ID                uint                   `json:"id"`
+	TransactionNumber string                 `json:"transaction_number"`
+	Reason            string                 `json:"reason"`
+	ReferenceType     string                 `json:"reference_type"`
+	ReferenceID       *uint                  `json:"reference_id"`
+	CreatedBy         string                 `json:"created_by"`
+	TotalCost         float64                `json:"total_cost"`
+	CreatedAt         time.Time              `json:"created_at"`
+	Items             []StockOutItemResponse `json:"items,omitempty"`
+}
+
+// StockOutItemResponse adalah response untuk item barang keluar (satu baris per batch FIFO)
+type StockOutItemResponse struct {
+	ID            uint    `json:"id"`
+	ProductID     uint    `json:"product_id"`
+	ProductSKU    string  `json:"product_sku"`
+	ProductName   string  `json:"product_name"`
+	WarehouseID   uint    `json:"warehouse_id"`
+	WarehouseName string  `json:"warehouse_name"`
+	Quantity      int     `json:"quantity"`
+	BatchID       *uint   `json:"batch_id"`
+	StockInItemID *uint   `json:"stock_in_item_id"`
+	CostPrice     float64 `json:"cost_price"`
+	Subtotal      float64 `json:"subtotal"`
 }
 
 // CreateStockOutRequest adalah request untuk barang keluar manual (usage/damaged etc, not sales)
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// ListStockAlertRequest adalah filter untuk daftar peringatan stok.
+// Status "active" berarti open + acknowledged (default).
+type ListStockAlertRequest struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status      string `form:"status" binding:"omitempty,oneof=active open acknowledged resolved all"`
+	WarehouseID *uint  `form:"warehouse_id"`
+	ProductID   *uint  `form:"product_id"`
+}
+
+// AcknowledgeStockAlertRequest adalah request untuk mengonfirmasi peringatan stok
+type AcknowledgeStockAlertRequest struct {
+	Notes string `json:"notes" binding:"omitempty,max=500"`
+}
+
+// StockAlertResponse adalah response untuk peringatan stok
+type StockAlertResponse struct {
+	ID               uint       `json:"id"`
+	ProductID        uint       `json:"product_id"`
+	ProductSKU       string     `json:"product_sku"`
+	ProductName      string     `json:"product_name"`
+	WarehouseID      uint       `json:"warehouse_id"`
+	WarehouseName    string     `json:"warehouse_name"`
+	Status           string     `json:"status"`
+	Minimum          int        `json:"minimum"`
+	QuantityAtOpen   int        `json:"quantity_at_open"`
+	CurrentQuantity  int        `json:"current_quantity"`
+	OpenedAt         time.Time  `json:"opened_at"`
+	AcknowledgedBy   string     `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt   *time.Time `json:"acknowledged_at"`
+	AcknowledgeNotes string     `json:"acknowledge_notes,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at"`
+}
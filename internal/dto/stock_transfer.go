@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// ListStockTransferRequest adalah DTO untuk filter list dokumen transfer
+type ListStockTransferRequest struct {
+	Page              int    `form:"page" binding:"omitempty,min=1"`
+	Limit             int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status            string `form:"status" binding:"omitempty,oneof=draft shipped received cancelled"` // shipped = in-transit
+	SourceWarehouseID *uint  `form:"source_warehouse_id"`
+	TargetWarehouseID *uint  `form:"target_warehouse_id"`
+}
+
+// StockTransferResponse adalah response untuk dokumen transfer stok
+type StockTransferResponse struct {
+	ID                  uint                        `json:"id"`
+	TransferNumber      string                      `json:"transfer_number"`
+	SourceWarehouseID   uint                        `json:"source_warehouse_id"`
+	SourceWarehouseName string                      `json:"source_warehouse_name"`
+	TargetWarehouseID   uint                        `json:"target_warehouse_id"`
+	TargetWarehouseName string                      `json:"target_warehouse_name"`
+	Status              string                      `json:"status"`
+	Notes               string                      `json:"notes"`
+	CreatedBy           string                      `json:"created_by"`
+	ShippedBy           string                      `json:"shipped_by,omitempty"`
+	ShippedAt           *time.Time                  `json:"shipped_at"`
+	ReceivedBy          string                      `json:"received_by,omitempty"`
+	ReceivedAt          *time.Time                  `json:"received_at"`
+	CancelledBy         string                      `json:"cancelled_by,omitempty"`
+	CancelledAt         *time.Time                  `json:"cancelled_at"`
+	CreatedAt           time.Time                   `json:"created_at"`
+	Items               []StockTransferItemResponse `json:"items,omitempty"`
+}
+
+// StockTransferItemResponse adalah response untuk item transfer
+type StockTransferItemResponse struct {
+	ID          uint                         `json:"id"`
+	ProductID   uint                         `json:"product_id"`
+	ProductSKU  string                       `json:"product_sku"`
+	ProductName string                       `json:"product_name"`
+	Quantity    int                          `json:"quantity"`
+	Batches     []StockTransferBatchResponse `json:"batches,omitempty"`
+}
+
+// StockTransferBatchResponse adalah breakdown batch FIFO yang dipindahkan
+type StockTransferBatchResponse struct {
+	SourceBatchID uint    `json:"source_batch_id"`
+	TargetBatchID *uint   `json:"target_batch_id"` // null selama in-transit
+	Quantity      int     `json:"quantity"`
+	CostPrice     float64 `json:"cost_price"`
+}
@@ -1,12 +1,14 @@
 package dto
 
+import "time"
+
 // UserResponse adalah DTO untuk response user
 // @Description User response dengan informasi lengkap
 type UserResponse struct {
 	ID    uint   `json:"id" example:"1"`
 	Email string `json:"email" example:"user@example.com"`
 	Nama  string `json:"nama" example:"John Doe"`
-	Peran string `json:"peran" example:"kasir" enums:"owner,kasir,admin_gudang,finance"`
+	Peran string `json:"peran" example:"kasir"` // Kode role (owner, kasir, admin_gudang, finance, atau role custom)
 	Aktif bool   `json:"aktif" example:"true"`
 }
 
@@ -16,14 +18,14 @@ type CreateUserRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
 	Password string `json:"password" binding:"required,min=6" example:"password123"`
 	Nama     string `json:"nama" binding:"required" example:"John Doe"`
-	Peran    string `json:"peran" binding:"required,oneof=owner kasir admin_gudang finance" example:"kasir" enums:"owner,kasir,admin_gudang,finance"`
+	Peran    string `json:"peran" binding:"required,max=50" example:"kasir"`
 }
 
 // UpdateUserRequest adalah DTO untuk request update user
 // @Description Request untuk mengupdate user (semua field optional)
 type UpdateUserRequest struct {
 	Nama  *string `json:"nama,omitempty" example:"John Doe Updated"`
-	Peran *string `json:"peran,omitempty" binding:"omitempty,oneof=owner kasir admin_gudang finance" example:"admin_gudang" enums:"owner,kasir,admin_gudang,finance"`
+	Peran *string `json:"peran,omitempty" binding:"omitempty,max=50" example:"admin_gudang"`
 	Aktif *bool   `json:"aktif,omitempty" example:"true"`
 }
 
@@ -40,7 +42,7 @@ type ListUsersRequest struct {
 	Page     int    `form:"page" binding:"omitempty,min=1" example:"1"`
 	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
 	Search   string `form:"search" example:"john"`
-	Peran    string `form:"peran" binding:"omitempty,oneof=owner kasir admin_gudang finance" example:"kasir" enums:"owner,kasir,admin_gudang,finance"`
+	Peran    string `form:"peran" binding:"omitempty,max=50" example:"kasir"`
 	Aktif    *bool  `form:"aktif" example:"true"`
 }
 
@@ -59,3 +61,39 @@ type Pagination struct {
 	Total      int64 `json:"total" example:"50"`
 	TotalPages int   `json:"total_pages" example:"5"`
 }
+
+// CreateInvitationRequest adalah DTO untuk request undangan staff
+// @Description Request untuk mengundang staff baru
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"staff@example.com"`
+	Peran string `json:"peran" binding:"required,max=50" example:"kasir"`
+}
+
+// InvitationResponse adalah DTO untuk response undangan
+// @Description Undangan staff. Token hanya dikembalikan sekali saat undangan dibuat.
+type InvitationResponse struct {
+	ID              uint       `json:"id" example:"1"`
+	Email           string     `json:"email" example:"staff@example.com"`
+	Peran           string     `json:"peran" example:"kasir"`
+	Status          string     `json:"status" example:"pending" enums:"pending,accepted,revoked,expired"`
+	Token           string     `json:"token,omitempty" example:"3f9a...e21c"`
+	KedaluwarsaPada time.Time  `json:"kedaluwarsa_pada"`
+	DipakaiPada     *time.Time `json:"dipakai_pada,omitempty"`
+	DibatalkanPada  *time.Time `json:"dibatalkan_pada,omitempty"`
+	DibuatOleh      string     `json:"dibuat_oleh" example:"Owner"`
+	DibuatPada      time.Time  `json:"dibuat_pada"`
+}
+
+// ListInvitationsRequest adalah DTO untuk request list undangan (query params)
+// @Description Query parameters untuk list undangan
+type ListInvitationsRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
+}
+
+// ListInvitationsResponse adalah DTO untuk response list undangan
+// @Description Response untuk list undangan dengan pagination
+type ListInvitationsResponse struct {
+	Invitations []InvitationResponse `json:"invitations"`
+	Pagination  Pagination           `json:"pagination"`
+}
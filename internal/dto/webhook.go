@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+// ===========================
+// REQUEST DTOs
+// ===========================
+
+// CreateWebhookRequest adalah DTO untuk mendaftarkan endpoint webhook.
+// Secret kosong = dibuat otomatis oleh server (hanya ditampilkan sekali di response).
+type CreateWebhookRequest struct {
+	Nama      string   `json:"nama" binding:"required,max=100"`
+	URL       string   `json:"url" binding:"required,url,max=500"`
+	TipeEvent []string `json:"tipe_event" binding:"required,min=1"` // sale.created, stock.low, return.approved, purchase_order.approved, ... atau "*"
+	Secret    string   `json:"secret" binding:"omitempty,min=16,max=128"`
+	Aktif     *bool    `json:"aktif"` // Default true
+}
+
+// UpdateWebhookRequest adalah DTO untuk mengubah webhook (field kosong tidak diubah)
+type UpdateWebhookRequest struct {
+	Nama      *string  `json:"nama" binding:"omitempty,max=100"`
+	URL       *string  `json:"url" binding:"omitempty,url,max=500"`
+	TipeEvent []string `json:"tipe_event" binding:"omitempty,min=1"`
+	Secret    *string  `json:"secret" binding:"omitempty,min=16,max=128"`
+	Aktif     *bool    `json:"aktif"`
+}
+
+// ListWebhookRequest adalah DTO untuk pagination daftar webhook
+type ListWebhookRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ListWebhookDeliveryRequest adalah DTO untuk filter log pengiriman webhook
+type ListWebhookDeliveryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=pending success failed"`
+}
+
+// ===========================
+// RESPONSE DTOs
+// ===========================
+
+// WebhookResponse adalah DTO untuk response webhook. Secret hanya terisi saat webhook dibuat.
+type WebhookResponse struct {
+	ID             uint      `json:"id"`
+	Nama           string    `json:"nama"`
+	URL            string    `json:"url"`
+	TipeEvent      []string  `json:"tipe_event"`
+	Aktif          bool      `json:"aktif"`
+	Secret         string    `json:"secret,omitempty"`
+	NamaPembuat    string    `json:"nama_pembuat"`
+	DibuatPada     time.Time `json:"dibuat_pada"`
+	DiperbaruiPada time.Time `json:"diperbarui_pada"`
+}
+
+// WebhookDeliveryResponse adalah DTO untuk satu baris log pengiriman webhook
+type WebhookDeliveryResponse struct {
+	ID                  uint       `json:"id"`
+	IDWebhook           uint       `json:"id_webhook"`
+	IDEvent             uint       `json:"id_event"`
+	TipeEvent           string     `json:"tipe_event"`
+	Status              string     `json:"status"` // pending, success, failed
+	Percobaan           int        `json:"percobaan"`
+	PercobaanBerikutnya *time.Time `json:"percobaan_berikutnya,omitempty"` // Hanya untuk status pending
+	StatusHTTP          int        `json:"status_http"`
+	ResponTerakhir      string     `json:"respon_terakhir"`
+	ErrorTerakhir       string     `json:"error_terakhir"`
+	DurasiMs            int64      `json:"durasi_ms"`
+	Payload             string     `json:"payload"`
+	TerkirimPada        *time.Time `json:"terkirim_pada"`
+	DibuatPada          time.Time  `json:"dibuat_pada"`
+}
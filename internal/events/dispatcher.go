@@ -0,0 +1,140 @@
+package events
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	dispatchInterval    = time.Second
+	dispatchBatchSize   = 100
+	maxDispatchAttempts = 10
+	maxRetryBackoff     = 10 * time.Minute
+
+	// Event terkirim disimpan selama ini untuk replay WebSocket
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Sink adalah tujuan pengiriman event outbox (WebSocket hub, webhook).
+// tx adalah transaksi dispatcher, sehingga sink bisa mencatat antrean lanjutan secara atomik.
+// Error membuat event dicoba ulang dengan backoff dan dikirim ulang ke semua sink,
+// jadi penerima harus siap menerima ID event yang sama lebih dari sekali.
+type Sink interface {
+	Name() string
+	Deliver(tx *gorm.DB, event models.AntreanEvent) error
+}
+
+// Dispatcher mengirim event pending dari outbox ke semua sink
+type Dispatcher struct {
+	repo  repositories.OutboxRepository
+	sinks []Sink
+}
+
+// NewDispatcher membuat dispatcher outbox
+func NewDispatcher(repo repositories.OutboxRepository, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{repo: repo, sinks: sinks}
+}
+
+// Run memproses outbox terus-menerus (jalankan sebagai goroutine, seperti Hub.Run)
+func (d *Dispatcher) Run() {
+	ticker := time.NewTicker(dispatchInterval)
+	cleanup := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Kuras antrean selama batch masih penuh
+			for d.dispatchBatch() == dispatchBatchSize {
+			}
+		case <-cleanup.C:
+			if n, err := d.repo.DeleteDispatchedBefore(time.Now().Add(-outboxRetention)); err != nil {
+				log.Printf("Failed to clean up outbox: %v", err)
+			} else if n > 0 {
+				log.Printf("Removed %d dispatched outbox events", n)
+			}
+		}
+	}
+}
+
+// dispatchBatch mengirim satu batch event jatuh tempo dan mengembalikan jumlah event yang diproses
+func (d *Dispatcher) dispatchBatch() int {
+	tx := d.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			log.Printf("Outbox dispatcher panic: %v", r)
+		}
+	}()
+
+	now := time.Now()
+	rows, err := d.repo.FindDueForUpdate(tx, now, dispatchBatchSize)
+	if err != nil {
+		tx.Rollback()
+		log.Printf("Failed to load outbox events: %v", err)
+		return 0
+	}
+
+	for _, row := range rows {
+		if err := d.deliver(tx, row); err != nil {
+			attempts := row.Percobaan + 1
+			failed := attempts >= maxDispatchAttempts
+			log.Printf("Failed to dispatch event #%d (%s), attempt %d: %v", row.ID, row.TipeEvent, attempts, err)
+			if err := d.repo.MarkRetry(tx, row.ID, attempts, now.Add(retryBackoff(attempts)), err.Error(), failed); err != nil {
+				tx.Rollback()
+				log.Printf("Failed to update outbox event #%d: %v", row.ID, err)
+				return 0
+			}
+			continue
+		}
+		if err := d.repo.MarkDispatched(tx, row.ID, now); err != nil {
+			tx.Rollback()
+			log.Printf("Failed to update outbox event #%d: %v", row.ID, err)
+			return 0
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Printf("Failed to commit outbox dispatch: %v", err)
+		return 0
+	}
+	return len(rows)
+}
+
+// deliver mengirim satu event ke semua sink. Perubahan sink yang gagal dibatalkan via savepoint
+// agar transaksi dispatcher tetap bisa dipakai untuk event berikutnya.
+func (d *Dispatcher) deliver(tx *gorm.DB, row models.AntreanEvent) error {
+	const savepoint = "outbox_event"
+	if err := tx.SavePoint(savepoint).Error; err != nil {
+		return err
+	}
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(tx, row); err != nil {
+			tx.RollbackTo(savepoint)
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// retryBackoff menghitung jeda sebelum percobaan berikutnya: 2^attempt detik, maksimal 10 menit
+func retryBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 10 {
+		return maxRetryBackoff
+	}
+	backoff := time.Duration(1<<uint(attempt)) * time.Second
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return backoff
+}
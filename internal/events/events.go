@@ -0,0 +1,135 @@
+package events
+
+// Tipe event domain. Event dicatat di outbox bersama transaksinya lalu dikirim setelah commit.
+const (
+	TypeSaleCreated            = "sale.created"
+	TypeStockChanged           = "stock.changed"
+	TypeStockLow               = "stock.low"
+	TypeStockInPendingApproval = "stock_in.pending_approval"
+	TypeReturnPendingApproval  = "return.pending_approval"
+	TypeReturnApproved         = "return.approved"
+	TypePurchaseOrderApproved  = "purchase_order.approved"
+	TypeStockAlertAcknowledged = "stock_alert.acknowledged"
+	TypeStockAlertResolved     = "stock_alert.resolved"
+	TypeSalesOrderStatus       = "sales_order.status_changed"
+	TypeShiftClosed            = "shift.closed"
+)
+
+// Types adalah semua tipe event yang bisa dipilih saat mendaftarkan webhook
+var Types = []string{
+	TypeSaleCreated,
+	TypeStockChanged,
+	TypeStockLow,
+	TypeStockInPendingApproval,
+	TypeReturnPendingApproval,
+	TypeReturnApproved,
+	TypePurchaseOrderApproved,
+	TypeStockAlertAcknowledged,
+	TypeStockAlertResolved,
+	TypeSalesOrderStatus,
+	TypeShiftClosed,
+}
+
+// Jenis retur pada payload event retur
+const (
+	ReturnKindSales    = "sales_return"
+	ReturnKindPurchase = "purchase_return"
+)
+
+// Event adalah satu event domain. Topics kosong = dikirim ke semua client.
+type Event struct {
+	Type   string
+	Topics []string
+	Data   interface{}
+}
+
+// New membuat event untuk topic-topic tertentu
+func New(eventType string, data interface{}, topics ...string) Event {
+	return Event{Type: eventType, Topics: topics, Data: data}
+}
+
+// SaleCreated adalah payload event sale.created
+type SaleCreated struct {
+	ID             uint    `json:"id"`
+	NomorTransaksi string  `json:"nomor_transaksi"`
+	WarehouseID    uint    `json:"warehouse_id"`
+	CashierID      uint    `json:"cashier_id"`
+	PaymentMethod  string  `json:"payment_method"`
+	Total          float64 `json:"total"`
+	ItemCount      int     `json:"item_count"`
+}
+
+// SalesOrderStatus adalah payload event sales_order.status_changed
+// (pesanan dibuat, siap, dikirim, lunas atau dibatalkan)
+type SalesOrderStatus struct {
+	ID             uint    `json:"id"`
+	NomorTransaksi string  `json:"nomor_transaksi"`
+	WarehouseID    uint    `json:"warehouse_id"`
+	CustomerID     *uint   `json:"customer_id,omitempty"`
+	Status         string  `json:"status"`
+	Total          float64 `json:"total"`
+	TotalPaid      float64 `json:"total_paid"`
+	Balance        float64 `json:"balance"`
+}
+
+// ShiftClosed adalah payload event shift.closed. Variance = kas dihitung - kas diharapkan (minus = kurang).
+type ShiftClosed struct {
+	ID           uint    `json:"id"`
+	NomorShift   string  `json:"nomor_shift"`
+	WarehouseID  uint    `json:"warehouse_id"`
+	CashierID    uint    `json:"cashier_id"`
+	ExpectedCash float64 `json:"expected_cash"`
+	CountedCash  float64 `json:"counted_cash"`
+	Variance     float64 `json:"variance"`
+}
+
+// StockChanged adalah payload event stock.changed. Quantity adalah saldo stok_inventori setelah perubahan.
+type StockChanged struct {
+	ProductID     uint   `json:"product_id"`
+	WarehouseID   uint   `json:"warehouse_id"`
+	Quantity      int    `json:"quantity"`
+	Delta         int    `json:"delta"`
+	ReferenceType string `json:"ref_type"`
+	ReferenceID   uint   `json:"ref_id"`
+}
+
+// StockAlert adalah payload event peringatan stok: stock.low (peringatan dibuka karena saldo
+// turun sampai <= stok minimum produk), stock_alert.acknowledged dan stock_alert.resolved
+type StockAlert struct {
+	AlertID     uint   `json:"alert_id"`
+	Status      string `json:"status"`
+	ProductID   uint   `json:"product_id"`
+	ProductSKU  string `json:"product_sku"`
+	ProductName string `json:"product_name"`
+	WarehouseID uint   `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+	Minimum     int    `json:"minimum"`
+}
+
+// StockInPending adalah payload event stock_in.pending_approval
+type StockInPending struct {
+	ID                uint   `json:"id"`
+	TransactionNumber string `json:"transaction_number"`
+	WarehouseID       uint   `json:"warehouse_id"`
+	SupplierID        *uint  `json:"supplier_id"`
+	ItemCount         int    `json:"item_count"`
+}
+
+// ReturnStatus adalah payload event return.pending_approval dan return.approved
+type ReturnStatus struct {
+	ID          uint    `json:"id"`
+	NomorRetur  string  `json:"nomor_retur"`
+	Kind        string  `json:"kind"` // sales_return, purchase_return
+	Status      string  `json:"status"`
+	WarehouseID uint    `json:"warehouse_id"`
+	Total       float64 `json:"total"`
+}
+
+// PurchaseOrderApproved adalah payload event purchase_order.approved
+type PurchaseOrderApproved struct {
+	ID         uint    `json:"id"`
+	NomorPO    string  `json:"nomor_po"`
+	SupplierID uint    `json:"supplier_id"`
+	Total      float64 `json:"total"`
+	ApprovedBy uint    `json:"approved_by"`
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"encoding/json"
+
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
+
+	"gorm.io/gorm"
+)
+
+type hubSink struct {
+	hub *websocket.Hub
+}
+
+// NewHubSink membuat Sink yang meneruskan event outbox ke client WebSocket
+func NewHubSink(hub *websocket.Hub) Sink {
+	return &hubSink{hub: hub}
+}
+
+func (s *hubSink) Name() string {
+	return "websocket"
+}
+
+func (s *hubSink) Deliver(_ *gorm.DB, event models.AntreanEvent) error {
+	return s.hub.PublishEvent(toEventMessage(event))
+}
+
+// NewReplaySource membuat sumber replay WebSocket dari tabel outbox
+func NewReplaySource(repo repositories.OutboxRepository) websocket.ReplaySource {
+	return func(sinceID uint, limit int) ([]websocket.EventMessage, error) {
+		rows, err := repo.FindSince(sinceID, limit)
+		if err != nil {
+			return nil, err
+		}
+		messages := make([]websocket.EventMessage, 0, len(rows))
+		for _, row := range rows {
+			messages = append(messages, toEventMessage(row))
+		}
+		return messages, nil
+	}
+}
+
+func toEventMessage(row models.AntreanEvent) websocket.EventMessage {
+	return websocket.EventMessage{
+		ID:         row.ID,
+		Type:       row.TipeEvent,
+		Topics:     outboxTopics(row),
+		Data:       json.RawMessage(row.Payload),
+		OccurredAt: row.DibuatPada,
+	}
+}
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  2 * time.Second,
+		1:  2 * time.Second,
+		3:  8 * time.Second,
+		9:  512 * time.Second,
+		10: maxRetryBackoff,
+		50: maxRetryBackoff,
+	}
+	for attempt, want := range cases {
+		if got := retryBackoff(attempt); got != want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestToOutboxRow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	row, err := toOutboxRow(New(TypeStockChanged, StockChanged{ProductID: 7, WarehouseID: 3, Quantity: 4, Delta: -2},
+		"warehouse:3", "permission:stock.read"), now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if row.Status != "pending" || !row.PercobaanBerikutnya.Equal(now) {
+		t.Errorf("Expected pending row due now, got %+v", row)
+	}
+	if row.Payload != `{"product_id":7,"warehouse_id":3,"quantity":4,"delta":-2,"ref_type":"","ref_id":0}` {
+		t.Errorf("Unexpected payload: %s", row.Payload)
+	}
+
+	msg := toEventMessage(row)
+	if len(msg.Topics) != 2 || msg.Topics[0] != "warehouse:3" || msg.Topics[1] != "permission:stock.read" {
+		t.Errorf("Unexpected topics: %v", msg.Topics)
+	}
+
+	broadcast, _ := toOutboxRow(New(TypeSaleCreated, SaleCreated{ID: 1}), now)
+	if topics := toEventMessage(broadcast).Topics; topics != nil {
+		t.Errorf("Expected no topics for broadcast event, got %v", topics)
+	}
+}
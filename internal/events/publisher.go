@@ -0,0 +1,74 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Publisher menyimpan event domain ke outbox di dalam transaksi bisnis (sebelum commit).
+// Event ikut di-rollback bersama datanya, dan baru dikirim ke client oleh Dispatcher.
+type Publisher interface {
+	Publish(tx *gorm.DB, events ...Event) error
+}
+
+// NoopPublisher membuang semua event (dipakai di test)
+type NoopPublisher struct{}
+
+func (NoopPublisher) Publish(*gorm.DB, ...Event) error { return nil }
+
+type outboxPublisher struct {
+	repo repositories.OutboxRepository
+}
+
+// NewOutboxPublisher membuat Publisher yang menulis event ke tabel antrean_event
+func NewOutboxPublisher(repo repositories.OutboxRepository) Publisher {
+	return &outboxPublisher{repo: repo}
+}
+
+func (p *outboxPublisher) Publish(tx *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]models.AntreanEvent, 0, len(events))
+	for _, e := range events {
+		row, err := toOutboxRow(e, now)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	return p.repo.Create(tx, rows)
+}
+
+// toOutboxRow mengubah event menjadi baris outbox pending yang siap dikirim
+func toOutboxRow(e Event, now time.Time) (models.AntreanEvent, error) {
+	payload, err := json.Marshal(e.Data)
+	if err != nil {
+		return models.AntreanEvent{}, fmt.Errorf("gagal encode payload event %s: %w", e.Type, err)
+	}
+	return models.AntreanEvent{
+		TipeEvent:           e.Type,
+		Topik:               strings.Join(e.Topics, ","),
+		Payload:             string(payload),
+		Status:              "pending",
+		PercobaanBerikutnya: now,
+		DibuatPada:          now,
+	}, nil
+}
+
+// outboxTopics memecah kolom topik (dipisah koma) menjadi daftar topic
+func outboxTopics(row models.AntreanEvent) []string {
+	if row.Topik == "" {
+		return nil
+	}
+	return strings.Split(row.Topik, ",")
+}
@@ -0,0 +1,212 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookPollInterval   = 2 * time.Second
+	webhookBatchSize      = 20
+	webhookTimeout        = 10 * time.Second
+	maxWebhookAttempts    = 8
+	maxWebhookResponseLog = 1024
+
+	// Lease klaim pengiriman; harus lebih lama dari timeout request HTTP
+	webhookLease = time.Minute
+)
+
+// WebhookEventAll berarti webhook menerima semua tipe event
+const WebhookEventAll = "*"
+
+// webhookPayload adalah body JSON yang dikirim ke endpoint webhook
+type webhookPayload struct {
+	ID         uint            `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type webhookSink struct {
+	repo repositories.WebhookRepository
+}
+
+// NewWebhookSink membuat Sink yang mengantrekan pengiriman ke setiap webhook aktif yang berlangganan tipe event.
+// Pengiriman HTTP-nya dilakukan WebhookWorker dengan retry per webhook.
+func NewWebhookSink(repo repositories.WebhookRepository) Sink {
+	return &webhookSink{repo: repo}
+}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Deliver(tx *gorm.DB, event models.AntreanEvent) error {
+	webhooks, err := s.repo.FindActive(tx)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	var deliveries []models.PengirimanWebhook
+	for _, webhook := range webhooks {
+		if !WebhookSubscribes(webhook.TipeEvent, event.TipeEvent) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(webhookPayload{
+				ID:         event.ID,
+				Type:       event.TipeEvent,
+				OccurredAt: event.DibuatPada,
+				Data:       json.RawMessage(event.Payload),
+			}); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		deliveries = append(deliveries, models.PengirimanWebhook{
+			IDWebhook:           webhook.ID,
+			IDEvent:             event.ID,
+			TipeEvent:           event.TipeEvent,
+			Payload:             string(body),
+			Status:              "pending",
+			PercobaanBerikutnya: now,
+			DibuatPada:          now,
+			DiperbaruiPada:      now,
+		})
+	}
+	return s.repo.CreateDeliveries(tx, deliveries)
+}
+
+// WebhookSubscribes mengecek apakah daftar tipe event webhook (dipisah koma) mencakup eventType
+func WebhookSubscribes(subscribed, eventType string) bool {
+	for _, t := range strings.Split(subscribed, ",") {
+		t = strings.TrimSpace(t)
+		if t == WebhookEventAll || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// SignWebhook menghitung signature HMAC-SHA256 atas "<timestamp>.<body>".
+// Penerima menghitung ulang dengan secret yang sama dan membandingkan dengan header X-Webhook-Signature.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookWorker mengirim antrean pengiriman webhook via HTTP POST dengan retry + backoff
+type WebhookWorker struct {
+	repo   repositories.WebhookRepository
+	client *http.Client
+}
+
+// NewWebhookWorker membuat worker pengiriman webhook
+func NewWebhookWorker(repo repositories.WebhookRepository) *WebhookWorker {
+	return &WebhookWorker{
+		repo:   repo,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Run memproses antrean pengiriman terus-menerus (jalankan sebagai goroutine)
+func (w *WebhookWorker) Run() {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := time.Now()
+		deliveries, err := w.repo.ClaimDueDeliveries(now, now.Add(webhookLease), webhookBatchSize)
+		if err != nil {
+			log.Printf("Failed to claim webhook deliveries: %v", err)
+			continue
+		}
+		for _, delivery := range deliveries {
+			w.send(delivery)
+		}
+	}
+}
+
+// send mengirim satu pengiriman lalu mencatat hasilnya (sukses, dijadwalkan ulang, atau gagal permanen)
+func (w *WebhookWorker) send(delivery models.PengirimanWebhook) {
+	attempts := delivery.Percobaan + 1
+	started := time.Now()
+	statusCode, response, err := w.post(delivery)
+
+	updates := map[string]interface{}{
+		"percobaan":       attempts,
+		"status_http":     statusCode,
+		"respon_terakhir": response,
+		"durasi_ms":       time.Since(started).Milliseconds(),
+		"diperbarui_pada": time.Now(),
+	}
+	switch {
+	case err == nil:
+		updates["status"] = "success"
+		updates["error_terakhir"] = ""
+		updates["terkirim_pada"] = time.Now()
+	case attempts >= maxWebhookAttempts || !delivery.Webhook.Aktif:
+		updates["status"] = "failed"
+		updates["error_terakhir"] = err.Error()
+	default:
+		updates["error_terakhir"] = err.Error()
+		updates["percobaan_berikutnya"] = time.Now().Add(retryBackoff(attempts))
+	}
+
+	if err := w.repo.UpdateDelivery(delivery.ID, updates); err != nil {
+		log.Printf("Failed to update webhook delivery #%d: %v", delivery.ID, err)
+	}
+}
+
+// post mengirim payload ke URL webhook. Response selain 2xx dianggap gagal.
+func (w *WebhookWorker) post(delivery models.PengirimanWebhook) (int, string, error) {
+	if !delivery.Webhook.Aktif {
+		return 0, "", fmt.Errorf("webhook #%d tidak aktif", delivery.IDWebhook)
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ERP-Meble-Webhook/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.TipeEvent)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhook(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseLog))
+	snippet := strings.ToValidUTF8(string(raw), "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, snippet, fmt.Errorf("endpoint membalas HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, snippet, nil
+}
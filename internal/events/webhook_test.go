@@ -0,0 +1,42 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":1,"type":"sale.created"}`)
+
+	mac := hmac.New(sha256.New, []byte("rahasia"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhook("rahasia", 1700000000, body); got != want {
+		t.Errorf("SignWebhook = %s, want %s", got, want)
+	}
+	if SignWebhook("lain", 1700000000, body) == want {
+		t.Error("Expected different signature for different secret")
+	}
+}
+
+func TestWebhookSubscribes(t *testing.T) {
+	cases := []struct {
+		subscribed string
+		eventType  string
+		want       bool
+	}{
+		{"*", TypeSaleCreated, true},
+		{"sale.created,stock.low", TypeStockLow, true},
+		{"sale.created, stock.low", TypeStockLow, true},
+		{"sale.created", TypeStockChanged, false},
+		{"", TypeSaleCreated, false},
+	}
+	for _, tc := range cases {
+		if got := WebhookSubscribes(tc.subscribed, tc.eventType); got != tc.want {
+			t.Errorf("WebhookSubscribes(%q, %q) = %v, want %v", tc.subscribed, tc.eventType, got, tc.want)
+		}
+	}
+}
@@ -40,7 +40,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(req, clientInfo(c))
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -49,15 +49,69 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	utils.OK(c, "Login successful", response)
 }
 
+// Refresh godoc
+// @Summary      Refresh access token
+// @Description  Menukar refresh token dengan access token baru. Refresh token lama dicabut dan diganti (rotasi).
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request   body      dto.RefreshTokenRequest  true  "Refresh token request"
+// @Success      200       {object}  utils.Response{data=dto.LoginResponse}
+// @Failure      400       {object}  utils.Response
+// @Failure      401       {object}  utils.Response
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", nil)
+		return
+	}
+
+	response, err := h.authService.Refresh(req, clientInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Token refreshed", response)
+}
+
+// Logout godoc
+// @Summary      Logout
+// @Description  Mencabut refresh token sesi ini, atau semua sesi (termasuk access token aktif) jika all_devices=true
+// @Tags         auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request   body      dto.LogoutRequest  true  "Logout request"
+// @Success      200       {object}  utils.Response
+// @Failure      401       {object}  utils.Response
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", nil)
+		return
+	}
+
+	if err := h.authService.Logout(utils.GetUserIDValidity(c), req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Logout successful", nil)
+}
+
 // Register godoc
 // @Summary      Register new user
-// @Description  Mendaftarkan user baru dengan email, password, dan nama
+// @Description  Mendaftarkan user baru (role kasir). Nonaktif kecuali AUTH_ALLOW_REGISTRATION=true.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request   body      dto.RegisterRequest  true  "Register request"
 // @Success      201       {object}  utils.Response{data=dto.UserResponse}
 // @Failure      400       {object}  utils.Response
+// @Failure      403       {object}  utils.Response
 // @Failure      409       {object}  utils.Response
 // @Router       /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -76,6 +130,85 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	utils.Created(c, "User registered successfully", user)
 }
 
+// BootstrapStatus godoc
+// @Summary      Bootstrap status
+// @Description  Mengecek apakah server masih menunggu owner pertama dan apakah self-registration aktif
+// @Tags         auth
+// @Produce      json
+// @Success      200       {object}  utils.Response{data=dto.BootstrapStatusResponse}
+// @Router       /auth/bootstrap [get]
+func (h *AuthHandler) BootstrapStatus(c *gin.Context) {
+	status, err := h.authService.BootstrapStatus()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Bootstrap status retrieved", status)
+}
+
+// Bootstrap godoc
+// @Summary      Bootstrap first owner
+// @Description  Membuat akun owner pertama. Hanya bisa dipakai saat tabel pengguna masih kosong.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request   body      dto.BootstrapRequest  true  "Bootstrap request"
+// @Success      201       {object}  utils.Response{data=dto.UserResponse}
+// @Failure      400       {object}  utils.Response
+// @Failure      403       {object}  utils.Response
+// @Router       /auth/bootstrap [post]
+func (h *AuthHandler) Bootstrap(c *gin.Context) {
+	var req dto.BootstrapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	user, err := h.authService.Bootstrap(req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.Created(c, "Owner created successfully", user)
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept invitation
+// @Description  Membuat akun staff dari token undangan (email dan role mengikuti undangan)
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request   body      dto.AcceptInvitationRequest  true  "Accept invitation request"
+// @Success      201       {object}  utils.Response{data=dto.UserResponse}
+// @Failure      400       {object}  utils.Response
+// @Failure      409       {object}  utils.Response
+// @Router       /auth/invitations/accept [post]
+func (h *AuthHandler) AcceptInvitation(c *gin.Context) {
+	var req dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", nil)
+		return
+	}
+
+	user, err := h.authService.AcceptInvitation(req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.Created(c, "Invitation accepted", user)
+}
+
+// clientInfo mengambil user agent & IP untuk dicatat bersama refresh token
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
+
 func (h *AuthHandler) handleError(c *gin.Context, err error) {
 	appErr := utils.GetAppError(err)
 
@@ -88,6 +221,8 @@ func (h *AuthHandler) handleError(c *gin.Context, err error) {
 		utils.BadRequest(c, appErr.Message, appErr.Err)
 	case utils.ErrCodeConflict:
 		utils.Conflict(c, appErr.Message)
+	case utils.ErrCodeForbidden:
+		utils.Forbidden(c, appErr.Message)
 	default:
 		h.logger.Error("Internal server error",
 			zap.String("code", appErr.Code),
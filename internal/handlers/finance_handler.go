@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FinanceHandler struct {
+	service services.FinanceService
+}
+
+func NewFinanceHandler(service services.FinanceService) *FinanceHandler {
+	return &FinanceHandler{service: service}
+}
+
+// ===========================
+// HUTANG SUPPLIER
+// ===========================
+
+// ListHutang godoc
+// @Summary      List hutang supplier
+// @Description  Daftar hutang supplier dengan filter supplier, status, dan yang sudah jatuh tempo
+// @Tags         finance
+// @Produce      json
+// @Param        page         query  int     false  "Halaman"
+// @Param        limit        query  int     false  "Jumlah per halaman"
+// @Param        id_pemasok   query  uint    false  "Filter ID supplier"
+// @Param        status       query  string  false  "unpaid, partially_paid, paid"
+// @Param        jatuh_tempo  query  bool    false  "true = hanya yang sudah lewat jatuh tempo"
+// @Success      200  {object}  utils.Response{data=[]dto.HutangResponse}
+// @Router       /supplier-debts [get]
+func (h *FinanceHandler) ListHutang(c *gin.Context) {
+	var req dto.ListHutangRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	results, total, err := h.service.ListHutang(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil data", err.Error())
+		return
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+	utils.OKWithMeta(c, "Daftar hutang supplier", results, utils.Meta{
+		Page: page, Limit: limit, Total: int(total), TotalPage: totalPages,
+	})
+}
+
+// GetHutang godoc
+// @Summary      Detail hutang supplier
+// @Description  Detail hutang beserta riwayat pembayaran
+// @Tags         finance
+// @Produce      json
+// @Param        id   path      int  true  "ID Hutang"
+// @Success      200  {object}  utils.Response{data=dto.HutangResponse}
+// @Router       /supplier-debts/{id} [get]
+func (h *FinanceHandler) GetHutang(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	result, err := h.service.GetHutangByID(uint(id))
+	if err != nil {
+		if err.Error() == "hutang tidak ditemukan" {
+			utils.NotFound(c, "Hutang tidak ditemukan")
+			return
+		}
+		utils.InternalServerError(c, "Gagal mengambil data hutang", err.Error())
+		return
+	}
+	utils.OK(c, "Detail hutang supplier", result)
+}
+
+// CreatePembayaranHutang godoc
+// @Summary      Catat pembayaran hutang supplier
+// @Description  Mencatat pembayaran sebagian/pelunasan. Mendukung application/json atau
+// @Description  multipart/form-data dengan file opsional di field "bukti_pembayaran".
+// @Tags         finance
+// @Accept       json,multipart/form-data
+// @Produce      json
+// @Param        id                 path      int                                true   "ID Hutang"
+// @Param        body               body      dto.CreatePembayaranHutangRequest  false  "JSON body"
+// @Param        bukti_pembayaran   formData  file                               false  "Foto/scan bukti pembayaran"
+// @Success      201  {object}  utils.Response{data=dto.HutangResponse}
+// @Router       /supplier-debts/{id}/payments [post]
+func (h *FinanceHandler) CreatePembayaranHutang(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	var req dto.CreatePembayaranHutangRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+
+	// Upload bukti pembayaran (opsional, hanya multipart)
+	var buktiPath string
+	if fileHeader, fileErr := c.FormFile("bukti_pembayaran"); fileErr == nil {
+		ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+		allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".pdf": true}
+		if !allowedExts[ext] {
+			utils.BadRequest(c, "Format file tidak didukung. Gunakan JPG, PNG, WEBP, atau PDF", nil)
+			return
+		}
+
+		uploadDir := "uploads/bukti_hutang"
+		if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+			utils.InternalServerError(c, "Gagal membuat direktori upload", err.Error())
+			return
+		}
+
+		fileName := fmt.Sprintf("hutang_%d_%d%s", id, time.Now().UnixNano(), ext)
+		buktiPath = filepath.Join(uploadDir, fileName)
+		if err := c.SaveUploadedFile(fileHeader, buktiPath); err != nil {
+			utils.InternalServerError(c, "Gagal menyimpan file", err.Error())
+			return
+		}
+	}
+
+	result, err := h.service.CreatePembayaranHutang(uint(id), userID, &req, buktiPath)
+	if err != nil {
+		if err.Error() == "hutang tidak ditemukan" {
+			utils.NotFound(c, "Hutang tidak ditemukan")
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.Created(c, "Pembayaran hutang berhasil dicatat", result)
+}
+
+// GetAgingHutang godoc
+// @Summary      Laporan umur hutang supplier
+// @Description  Sisa hutang dikelompokkan berdasarkan hari lewat jatuh tempo: belum jatuh tempo, 1-30, 31-60, 61-90, >90
+// @Tags         finance
+// @Produce      json
+// @Param        tanggal     query  string  false  "Tanggal acuan (YYYY-MM-DD), default hari ini"
+// @Param        id_pemasok  query  uint    false  "Filter ID supplier"
+// @Success      200  {object}  utils.Response{data=dto.AgingHutangResponse}
+// @Router       /supplier-debts/aging [get]
+func (h *FinanceHandler) GetAgingHutang(c *gin.Context) {
+	var req dto.AgingReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.GetAgingHutang(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal membuat laporan umur hutang", err.Error())
+		return
+	}
+	utils.OK(c, "Laporan umur hutang supplier", result)
+}
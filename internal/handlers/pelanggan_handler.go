@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PelangganHandler struct {
+	service services.PelangganService
+}
+
+func NewPelangganHandler(service services.PelangganService) *PelangganHandler {
+	return &PelangganHandler{service: service}
+}
+
+// CreatePelanggan godoc
+// @Summary      Create new customer
+// @Description  Create a new customer (phone number must be unique)
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        req  body      dto.CreatePelangganRequest  true  "Request Body"
+// @Success      201  {object}  utils.Response{data=dto.PelangganResponse}
+// @Router       /customers [post]
+func (h *PelangganHandler) CreatePelanggan(c *gin.Context) {
+	var req dto.CreatePelangganRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request", err)
+		return
+	}
+
+	pelanggan, err := h.service.CreatePelanggan(&req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create customer")
+		return
+	}
+
+	utils.Created(c, "Customer created successfully", pelanggan)
+}
+
+// GetPelanggan godoc
+// @Summary      Get customer by ID
+// @Description  Get customer details with lifetime spend, last purchase and recent sales/returns
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  utils.Response{data=dto.PelangganDetailResponse}
+// @Router       /customers/{id} [get]
+func (h *PelangganHandler) GetPelanggan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	pelanggan, err := h.service.GetPelangganByID(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch customer")
+		return
+	}
+
+	utils.OK(c, "Customer fetched successfully", pelanggan)
+}
+
+// ListPelanggan godoc
+// @Summary      List customers
+// @Description  Get list of customers with filter and pagination
+// @Tags         customers
+// @Produce      json
+// @Param        page    query     int     false  "Page number"
+// @Param        limit   query     int     false  "Items per page"
+// @Param        search  query     string  false  "Search by name/phone/email"
+// @Param        aktif   query     bool    false  "Filter by active status"
+// @Success      200     {object}  utils.Response{data=dto.ListPelangganResponse}
+// @Router       /customers [get]
+func (h *PelangganHandler) ListPelanggan(c *gin.Context) {
+	var req dto.ListPelangganRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.service.ListPelanggan(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to list customers", err.Error())
+		return
+	}
+
+	utils.OK(c, "Customers listed successfully", response)
+}
+
+// UpdatePelanggan godoc
+// @Summary      Update customer
+// @Description  Update customer details
+// @Tags         customers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int                         true  "Customer ID"
+// @Param        req  body      dto.UpdatePelangganRequest  true  "Request Body"
+// @Success      200  {object}  utils.Response{data=dto.PelangganResponse}
+// @Router       /customers/{id} [put]
+func (h *PelangganHandler) UpdatePelanggan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	var req dto.UpdatePelangganRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request", err)
+		return
+	}
+
+	pelanggan, err := h.service.UpdatePelanggan(uint(id), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update customer")
+		return
+	}
+
+	utils.OK(c, "Customer updated successfully", pelanggan)
+}
+
+// DeletePelanggan godoc
+// @Summary      Delete customer
+// @Description  Soft delete customer (past sales keep their customer snapshot)
+// @Tags         customers
+// @Produce      json
+// @Param        id   path      int  true  "Customer ID"
+// @Success      200  {object}  utils.Response
+// @Router       /customers/{id} [delete]
+func (h *PelangganHandler) DeletePelanggan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	if err := h.service.DeletePelanggan(uint(id)); err != nil {
+		h.handleError(c, err, "Failed to delete customer")
+		return
+	}
+
+	utils.OK(c, "Customer deleted successfully", nil)
+}
+
+func (h *PelangganHandler) handleError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "pelanggan tidak ditemukan":
+		utils.NotFound(c, "Customer not found")
+	case "nomor telepon sudah dipakai pelanggan lain":
+		utils.Conflict(c, "Phone number is already used by another customer")
+	default:
+		utils.InternalServerError(c, message, err.Error())
+	}
+}
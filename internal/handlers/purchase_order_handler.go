@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseOrderHandler struct {
+	service services.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service services.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// CreatePurchaseOrder godoc
+// @Summary      Buat Purchase Order baru
+// @Description  Membuat PO ke supplier dengan status awal draft
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.CreatePurchaseOrderRequest  true  "Data PO"
+// @Success      201   {object}  utils.Response{data=dto.PurchaseOrderResponse}
+// @Router       /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var req dto.CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.CreatePurchaseOrder(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.Created(c, "Purchase order berhasil dibuat (status: draft)", result)
+}
+
+// ListPurchaseOrders godoc
+// @Summary      List Purchase Order
+// @Description  Daftar PO dengan filter supplier, status dan tanggal pesan
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        page            query  int     false  "Halaman"
+// @Param        limit           query  int     false  "Jumlah per halaman"
+// @Param        id_pemasok      query  uint    false  "Filter ID supplier"
+// @Param        status          query  string  false  "draft, sent, approved, partially_received, completed, cancelled"
+// @Param        tanggal_dari    query  string  false  "Filter dari tanggal (YYYY-MM-DD)"
+// @Param        tanggal_sampai  query  string  false  "Filter sampai tanggal (YYYY-MM-DD)"
+// @Success      200  {object}  utils.Response{data=[]dto.PurchaseOrderResponse}
+// @Router       /purchase-orders [get]
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *gin.Context) {
+	var req dto.ListPurchaseOrderRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	results, total, err := h.service.ListPurchaseOrders(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil data", err.Error())
+		return
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+	utils.OKWithMeta(c, "Daftar purchase order", results, utils.Meta{
+		Page: page, Limit: limit, Total: int(total), TotalPage: totalPages,
+	})
+}
+
+// GetPurchaseOrder godoc
+// @Summary      Detail Purchase Order
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID PO"
+// @Success      200  {object}  utils.Response{data=dto.PurchaseOrderResponse}
+// @Router       /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	result, err := h.service.GetPurchaseOrderByID(uint(id))
+	if err != nil {
+		if err.Error() == "purchase order tidak ditemukan" {
+			utils.NotFound(c, "Purchase order tidak ditemukan")
+			return
+		}
+		utils.InternalServerError(c, "Gagal mengambil data purchase order", err.Error())
+		return
+	}
+	utils.OK(c, "Detail purchase order", result)
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary      Update Purchase Order
+// @Description  Mengubah header/item PO. Hanya PO berstatus draft yang dapat diubah.
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                             true  "ID PO"
+// @Param        body  body      dto.UpdatePurchaseOrderRequest  true  "Data PO"
+// @Success      200   {object}  utils.Response{data=dto.PurchaseOrderResponse}
+// @Router       /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.UpdatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.UpdatePurchaseOrder(uint(id), &req)
+	if err != nil {
+		if err.Error() == "purchase order tidak ditemukan" {
+			utils.NotFound(c, "Purchase order tidak ditemukan")
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, "Purchase order berhasil diupdate", result)
+}
+
+// SendPurchaseOrder godoc
+// @Summary      Kirim Purchase Order ke supplier
+// @Description  Mengubah status PO dari draft menjadi sent
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID PO"
+// @Success      200  {object}  utils.Response
+// @Router       /purchase-orders/{id}/send [patch]
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	if err := h.service.SendPurchaseOrder(uint(id)); err != nil {
+		h.handleStatusError(c, err)
+		return
+	}
+	utils.OK(c, "Purchase order ditandai terkirim ke supplier", nil)
+}
+
+// ApprovePurchaseOrder godoc
+// @Summary      Approve Purchase Order
+// @Description  Mengubah status PO dari sent menjadi approved dan mencatat penyetuju
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID PO"
+// @Success      200  {object}  utils.Response
+// @Router       /purchase-orders/{id}/approve [patch]
+func (h *PurchaseOrderHandler) ApprovePurchaseOrder(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	if err := h.service.ApprovePurchaseOrder(uint(id), userID); err != nil {
+		h.handleStatusError(c, err)
+		return
+	}
+	utils.OK(c, "Purchase order diapprove", nil)
+}
+
+// CancelPurchaseOrder godoc
+// @Summary      Batalkan Purchase Order
+// @Description  Membatalkan PO yang belum ada penerimaan barang
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID PO"
+// @Success      200  {object}  utils.Response
+// @Router       /purchase-orders/{id}/cancel [patch]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	if err := h.service.CancelPurchaseOrder(uint(id)); err != nil {
+		h.handleStatusError(c, err)
+		return
+	}
+	utils.OK(c, "Purchase order dibatalkan", nil)
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary      Terima barang dari Purchase Order
+// @Description  Mencatat penerimaan sebagian/seluruh item PO. Membuat barang masuk, batch FIFO di harga PO,
+// @Description  menambah jumlah diterima dan mengubah status PO ke partially_received/completed secara otomatis.
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                              true  "ID PO"
+// @Param        body  body      dto.ReceivePurchaseOrderRequest  true  "Item yang diterima"
+// @Success      201   {object}  utils.Response{data=dto.ReceivePurchaseOrderResponse}
+// @Router       /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.ReceivePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.ReceivePurchaseOrder(uint(id), userID, &req)
+	if err != nil {
+		h.handleStatusError(c, err)
+		return
+	}
+	utils.Created(c, "Penerimaan barang berhasil dicatat", result)
+}
+
+// handleStatusError memetakan error perubahan status PO ke HTTP response
+func (h *PurchaseOrderHandler) handleStatusError(c *gin.Context, err error) {
+	if err.Error() == "purchase order tidak ditemukan" {
+		utils.NotFound(c, "Purchase order tidak ditemukan")
+		return
+	}
+	utils.BadRequest(c, err.Error(), nil)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReorderHandler struct {
+	service services.ReorderService
+}
+
+func NewReorderHandler(service services.ReorderService) *ReorderHandler {
+	return &ReorderHandler{service: service}
+}
+
+// GetReorderSuggestions godoc
+// @Summary      Saran pemesanan ulang
+// @Description  Menghitung rata-rata permintaan harian, sisa hari stok dan saran qty per produk/gudang dari riwayat penjualan, dikelompokkan per pemasok
+// @Tags         purchase-orders
+// @Produce      json
+// @Param        id_gudang        query  uint  false  "Filter gudang"
+// @Param        id_pemasok       query  uint  false  "Filter pemasok"
+// @Param        periode_hari     query  int   false  "Riwayat penjualan (hari), default 30"
+// @Param        lead_time_hari   query  int   false  "Lead time pemasok (hari), default 7"
+// @Param        target_hari      query  int   false  "Target cakupan stok setelah barang datang (hari), default 14"
+// @Param        tampilkan_semua  query  bool  false  "Tampilkan juga produk yang belum perlu dipesan"
+// @Success      200  {object}  utils.Response{data=dto.ReorderSuggestionResponse}
+// @Router       /purchase-orders/reorder-suggestions [get]
+func (h *ReorderHandler) GetReorderSuggestions(c *gin.Context) {
+	var req dto.ReorderSuggestionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.GetSuggestions(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal menghitung saran pemesanan", err.Error())
+		return
+	}
+	utils.OK(c, "Saran pemesanan ulang berhasil dihitung", result)
+}
+
+// GenerateReorderPurchaseOrders godoc
+// @Summary      Buat draft PO dari saran pemesanan ulang
+// @Description  Menghitung ulang saran pemesanan lalu membuat satu draft PO per pemasok (harga = harga modal produk)
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.GenerateReorderRequest  true  "Parameter saran pemesanan"
+// @Success      201   {object}  utils.Response{data=dto.GenerateReorderResponse}
+// @Router       /purchase-orders/reorder-suggestions/generate [post]
+func (h *ReorderHandler) GenerateReorderPurchaseOrders(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var req dto.GenerateReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.GenerateDraftPurchaseOrders(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.Created(c, "Draft purchase order berhasil dibuat", result)
+}
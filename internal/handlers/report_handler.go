@@ -4,6 +4,7 @@ import (
 	"real-erp-mebel/be/internal/dto"
 	"real-erp-mebel/be/internal/services"
 	"real-erp-mebel/be/internal/utils"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -131,3 +132,83 @@ func (h *ReportHandler) GetStockReport(c *gin.Context) {
 	}
 	utils.OK(c, "Laporan stok berhasil diambil", result)
 }
+
+// GetPiutang godoc
+// @Summary      Buku piutang pelanggan
+// @Description  Pesanan yang masih memiliki sisa tagihan beserta jatuh tempo dan hari keterlambatan
+// @Tags         reports
+// @Produce      json
+// @Param        tanggal       query  string  false "Tanggal acuan (YYYY-MM-DD), default hari ini"
+// @Param        id_pelanggan  query  uint    false "Filter pelanggan"
+// @Param        jatuh_tempo   query  bool    false "true = hanya yang sudah lewat jatuh tempo"
+// @Success      200  {object}  utils.Response{data=dto.PiutangReportResponse}
+// @Router       /reports/receivables [get]
+func (h *ReportHandler) GetPiutang(c *gin.Context) {
+	var req dto.PiutangReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.GetPiutang(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil data piutang", err.Error())
+		return
+	}
+	utils.OK(c, "Daftar piutang pelanggan", result)
+}
+
+// GetAgingPiutang godoc
+// @Summary      Laporan umur piutang pelanggan
+// @Description  Sisa tagihan dikelompokkan berdasarkan hari lewat jatuh tempo: belum jatuh tempo, 1-30, 31-60, 61-90, >90
+// @Tags         reports
+// @Produce      json
+// @Param        tanggal       query  string  false "Tanggal acuan (YYYY-MM-DD), default hari ini"
+// @Param        id_pelanggan  query  uint    false "Filter pelanggan"
+// @Success      200  {object}  utils.Response{data=dto.AgingPiutangResponse}
+// @Router       /reports/receivables/aging [get]
+func (h *ReportHandler) GetAgingPiutang(c *gin.Context) {
+	var req dto.PiutangReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.GetAgingPiutang(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal membuat laporan umur piutang", err.Error())
+		return
+	}
+	utils.OK(c, "Laporan umur piutang pelanggan", result)
+}
+
+// GetCustomerStatement godoc
+// @Summary      Rekening koran piutang pelanggan
+// @Description  Saldo awal, mutasi pesanan & pembayaran dalam periode, saldo akhir, dan umur sisa tagihan
+// @Tags         reports
+// @Produce      json
+// @Param        id              path   int     true  "ID Pelanggan"
+// @Param        tanggal_dari    query  string  false "Dari tanggal (YYYY-MM-DD), default awal bulan"
+// @Param        tanggal_sampai  query  string  false "Sampai tanggal (YYYY-MM-DD), default hari ini"
+// @Success      200  {object}  utils.Response{data=dto.CustomerStatementResponse}
+// @Router       /reports/receivables/customers/{id}/statement [get]
+func (h *ReportHandler) GetCustomerStatement(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.CustomerStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.GetCustomerStatement(uint(id), &req)
+	if err != nil {
+		if err.Error() == "pelanggan tidak ditemukan" {
+			utils.NotFound(c, "Pelanggan tidak ditemukan")
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, "Rekening koran piutang pelanggan", result)
+}
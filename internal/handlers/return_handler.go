@@ -97,7 +97,48 @@ func (h *ReturnHandler) ApproveReturPenjualan(c *gin.Context) {
 		utils.BadRequest(c, err.Error(), nil)
 		return
 	}
-	utils.OK(c, "Retur penjualan diapprove — stok sudah dikembalikan ke gudang", nil)
+	utils.OK(c, "Retur penjualan diapprove — barang dicatat di stok karantina", nil)
+}
+
+func (h *ReturnHandler) RejectReturPenjualan(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.RejectReturRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Alasan penolakan wajib diisi", err.Error())
+		return
+	}
+	if err := h.service.RejectReturPenjualan(uint(id), userID, req.Alasan); err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, "Retur penjualan ditolak", nil)
+}
+
+func (h *ReturnHandler) CompleteReturPenjualan(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	if err := h.service.CompleteReturPenjualan(uint(id), userID); err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, "Retur penjualan selesai", nil)
 }
 
 // ===========================
@@ -182,3 +223,149 @@ func (h *ReturnHandler) ApproveReturPembelian(c *gin.Context) {
 	}
 	utils.OK(c, "Retur pembelian diapprove — stok sudah dikurangi dari gudang", nil)
 }
+
+func (h *ReturnHandler) RejectReturPembelian(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.RejectReturRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Alasan penolakan wajib diisi", err.Error())
+		return
+	}
+	if err := h.service.RejectReturPembelian(uint(id), userID, req.Alasan); err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, "Retur pembelian ditolak", nil)
+}
+
+func (h *ReturnHandler) CompleteReturPembelian(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	if err := h.service.CompleteReturPembelian(uint(id), userID); err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, "Retur pembelian selesai", nil)
+}
+
+// ===========================
+// STOK KARANTINA
+// ===========================
+
+func (h *ReturnHandler) ListStokKarantina(c *gin.Context) {
+	var req dto.ListStokKarantinaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	results, total, err := h.service.ListStokKarantina(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil data", err.Error())
+		return
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+	utils.OKWithMeta(c, "Daftar stok karantina", results, utils.Meta{
+		Page: page, Limit: limit, Total: int(total), TotalPage: totalPages,
+	})
+}
+
+func (h *ReturnHandler) ListDisposisiKarantina(c *gin.Context) {
+	var req dto.ListDisposisiKarantinaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	results, total, err := h.service.ListDisposisiKarantina(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil data", err.Error())
+		return
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+	utils.OKWithMeta(c, "Riwayat disposisi karantina", results, utils.Meta{
+		Page: page, Limit: limit, Total: int(total), TotalPage: totalPages,
+	})
+}
+
+func (h *ReturnHandler) ReleaseKarantina(c *gin.Context) {
+	h.disposeKarantina(c, h.service.ReleaseKarantina, "Stok karantina dikembalikan ke stok jual")
+}
+
+func (h *ReturnHandler) WriteOffKarantina(c *gin.Context) {
+	h.disposeKarantina(c, h.service.WriteOffKarantina, "Stok karantina di-write-off")
+}
+
+func (h *ReturnHandler) RepairKarantina(c *gin.Context) {
+	h.disposeKarantina(c, h.service.RepairKarantina, "Stok karantina dikirim ke perbaikan")
+}
+
+// disposeKarantina menjalankan satu aksi disposisi atas batch karantina
+func (h *ReturnHandler) disposeKarantina(
+	c *gin.Context,
+	action func(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error),
+	successMsg string,
+) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.DisposisiKarantinaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := action(uint(id), userID, &req)
+	if err != nil {
+		if err.Error() == "batch karantina tidak ditemukan" {
+			utils.NotFound(c, err.Error())
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.OK(c, successMsg, result)
+}
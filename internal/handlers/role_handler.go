@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"strconv"
+
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RoleHandler struct {
+	roleService services.RoleService
+	logger      *zap.Logger
+}
+
+// NewRoleHandler membuat instance RoleHandler baru
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{
+		roleService: services.NewRoleService(),
+		logger:      utils.GetLogger(),
+	}
+}
+
+// ListRoles mendapatkan semua role
+// @Summary List roles
+// @Description Mendapatkan semua role (bawaan dan custom) beserta permission-nya
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]dto.RoleResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Roles retrieved successfully", roles)
+}
+
+// ListPermissions mendapatkan katalog permission
+// @Summary List permissions
+// @Description Mendapatkan semua permission yang bisa diberikan ke role
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]dto.PermissionResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /roles/permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.roleService.ListPermissions()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Permissions retrieved successfully", permissions)
+}
+
+// GetRole mendapatkan role berdasarkan ID
+// @Summary Get role by ID
+// @Description Mendapatkan detail role berdasarkan ID
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 200 {object} utils.Response{data=dto.RoleResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /roles/{id} [get]
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid role ID", err)
+		return
+	}
+
+	role, err := h.roleService.GetRoleByID(uint(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Role retrieved successfully", role)
+}
+
+// CreateRole membuat role custom
+// @Summary Create role
+// @Description Membuat role custom (misalnya supervisor_toko) dengan daftar permission
+// @Tags roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateRoleRequest true "Create role request"
+// @Success 201 {object} utils.Response{data=dto.RoleResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /roles [post]
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	middleware.InvalidateRolePermissions(role.Kode)
+	utils.Created(c, "Role created successfully", role)
+}
+
+// UpdateRole mengupdate role
+// @Summary Update role
+// @Description Mengupdate nama, deskripsi, status aktif, atau mengganti seluruh permission role
+// @Tags roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Param request body dto.UpdateRoleRequest true "Update role request"
+// @Success 200 {object} utils.Response{data=dto.RoleResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid role ID", err)
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(uint(id), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	middleware.InvalidateRolePermissions(role.Kode)
+	utils.OK(c, "Role updated successfully", role)
+}
+
+// DeleteRole menghapus role custom
+// @Summary Delete role
+// @Description Menghapus role custom (role bawaan dan role yang masih dipakai user tidak bisa dihapus)
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid role ID", err)
+		return
+	}
+
+	if err := h.roleService.DeleteRole(uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	middleware.InvalidateRolePermissions("")
+	utils.OK(c, "Role deleted successfully", nil)
+}
+
+// handleError memetakan AppError ke HTTP response
+func (h *RoleHandler) handleError(c *gin.Context, err error) {
+	appErr := utils.GetAppError(err)
+
+	switch appErr.Code {
+	case utils.ErrCodeNotFound:
+		utils.NotFound(c, appErr.Message)
+	case utils.ErrCodeValidationError, utils.ErrCodeInvalidInput:
+		utils.BadRequest(c, appErr.Message, nil)
+	case utils.ErrCodeConflict:
+		utils.Conflict(c, appErr.Message)
+	default:
+		h.logger.Error("Internal server error",
+			zap.String("code", appErr.Code),
+			zap.String("message", appErr.Message),
+			zap.Error(appErr.Err),
+		)
+		utils.InternalServerError(c, "Internal server error", nil)
+	}
+}
@@ -25,11 +25,14 @@ func NewSalesHandler(service services.SalesService) *SalesHandler {
 
 // CreateSale godoc
 // @Summary      Buat transaksi penjualan baru (POS)
-// @Description  Membuat transaksi penjualan dengan FIFO auto-deduct stok.
+// @Description  Membuat transaksi penjualan dengan FIFO auto-deduct stok. Kasir wajib punya shift open di gudang yang sama.
 //
 //	Mendukung dua mode request:
 //	1. application/json  — body JSON langsung (tanpa upload file)
 //	2. multipart/form-data — JSON di field "data", file opsional di "bukti_bayar"
+//	   dan "bukti_pembayaran_<index>" untuk bukti per baris pembayaran
+//
+//	Split tender: isi "pembayaran" (cash/transfer/qris/debit); kembalian hanya dari porsi cash.
 //
 // @Tags         sales
 // @Accept       json,multipart/form-data
@@ -96,6 +99,34 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 			}
 			buktiBayarPath = &filePath
 		}
+
+		// Bukti per baris pembayaran (split tender): bukti_pembayaran_0, bukti_pembayaran_1, ...
+		for i := range req.Pembayaran {
+			fileHeader, fileErr := c.FormFile(fmt.Sprintf("bukti_pembayaran_%d", i))
+			if fileErr != nil {
+				continue
+			}
+
+			ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+			allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".pdf": true}
+			if !allowedExts[ext] {
+				utils.BadRequest(c, "Format file tidak didukung. Gunakan JPG, PNG, WEBP, atau PDF", nil)
+				return
+			}
+
+			uploadDir := "uploads/bukti_bayar"
+			if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+				utils.InternalServerError(c, "Gagal membuat direktori upload", err.Error())
+				return
+			}
+
+			filePath := filepath.Join(uploadDir, fmt.Sprintf("bukti_%d_%d%s", i, time.Now().UnixNano(), ext))
+			if err := c.SaveUploadedFile(fileHeader, filePath); err != nil {
+				utils.InternalServerError(c, "Gagal menyimpan file", err.Error())
+				return
+			}
+			req.Pembayaran[i].BuktiPembayaran = filePath
+		}
 	}
 
 	// ── Validasi umum ────────────────────────────────────────────────────────
@@ -103,13 +134,22 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 		utils.BadRequest(c, "id_gudang wajib diisi", nil)
 		return
 	}
-	if req.MetodePembayaran != "cash" && req.MetodePembayaran != "transfer" {
-		utils.BadRequest(c, "metode_pembayaran harus 'cash' atau 'transfer'", nil)
-		return
+	validMetode := map[string]bool{"cash": true, "transfer": true, "qris": true, "debit": true}
+	if len(req.Pembayaran) == 0 {
+		if !validMetode[req.MetodePembayaran] {
+			utils.BadRequest(c, "metode_pembayaran harus 'cash', 'transfer', 'qris', atau 'debit'", nil)
+			return
+		}
+		if req.JumlahPembayaran <= 0 {
+			utils.BadRequest(c, "jumlah_pembayaran harus lebih dari 0", nil)
+			return
+		}
 	}
-	if req.JumlahPembayaran <= 0 {
-		utils.BadRequest(c, "jumlah_pembayaran harus lebih dari 0", nil)
-		return
+	for _, p := range req.Pembayaran {
+		if !validMetode[p.MetodePembayaran] {
+			utils.BadRequest(c, "metode_pembayaran harus 'cash', 'transfer', 'qris', atau 'debit'", nil)
+			return
+		}
 	}
 	if len(req.Items) == 0 {
 		utils.BadRequest(c, "Items tidak boleh kosong", nil)
@@ -282,3 +322,46 @@ func (h *SalesHandler) UploadBuktiBayar(c *gin.Context) {
 
 	utils.OK(c, "Bukti bayar berhasil diupload", gin.H{"path": filePath})
 }
+
+// VoidSale godoc
+// @Summary      Void / batalkan transaksi penjualan
+// @Description  Membatalkan transaksi completed: stok dikembalikan ke batch FIFO asal, saldo stok dikoreksi,
+// @Description  dan pergerakan stok kompensasi dicatat. Hanya untuk role owner / finance.
+// @Tags         sales
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                   true  "ID Penjualan"
+// @Param        body  body      dto.VoidSalesRequest  true  "Alasan void"
+// @Success      200   {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales/{id}/void [patch]
+func (h *SalesHandler) VoidSale(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	var req dto.VoidSalesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Alasan void wajib diisi", err.Error())
+		return
+	}
+
+	result, err := h.service.VoidSale(uint(id), userID, req.Alasan)
+	if err != nil {
+		if err.Error() == "transaksi penjualan tidak ditemukan" {
+			utils.NotFound(c, "Transaksi tidak ditemukan")
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	utils.OK(c, "Transaksi berhasil di-void, stok sudah dikembalikan", result)
+}
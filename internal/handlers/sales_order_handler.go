@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SalesOrderHandler struct {
+	service services.SalesOrderService
+}
+
+func NewSalesOrderHandler(service services.SalesOrderService) *SalesOrderHandler {
+	return &SalesOrderHandler{service: service}
+}
+
+// CreateOrder godoc
+// @Summary      Buat pesanan penjualan (Mode 2)
+// @Description  Mencatat pesanan beserta DP. Stok belum dikurangi sampai pesanan ditandai siap.
+// @Tags         sales-orders
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.CreateSalesOrderRequest  true  "Data pesanan"
+// @Success      201  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales-orders [post]
+func (h *SalesOrderHandler) CreateOrder(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.CreateSalesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Format JSON tidak valid: "+err.Error(), nil)
+		return
+	}
+
+	result, err := h.service.CreateOrder(userID, &req)
+	if err != nil {
+		if err.Error() == "pelanggan tidak ditemukan" {
+			utils.NotFound(c, "Pelanggan tidak ditemukan")
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	utils.Created(c, "Pesanan penjualan berhasil dibuat", result)
+}
+
+// GetOrder godoc
+// @Summary      Detail pesanan penjualan
+// @Description  Detail pesanan termasuk riwayat pembayaran dan sisa tagihan
+// @Tags         sales-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID Pesanan"
+// @Success      200  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales-orders/{id} [get]
+func (h *SalesOrderHandler) GetOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	result, err := h.service.GetOrder(uint(id))
+	if err != nil {
+		if err.Error() == "pesanan penjualan tidak ditemukan" {
+			utils.NotFound(c, "Pesanan tidak ditemukan")
+			return
+		}
+		utils.InternalServerError(c, "Gagal mengambil data pesanan", err.Error())
+		return
+	}
+
+	utils.OK(c, "Detail pesanan penjualan", result)
+}
+
+// ListOrders godoc
+// @Summary      List pesanan penjualan
+// @Description  Daftar pesanan penjualan dengan filter tanggal, gudang, pelanggan dan status
+// @Tags         sales-orders
+// @Produce      json
+// @Param        page            query  int     false  "Halaman"
+// @Param        limit           query  int     false  "Jumlah per halaman"
+// @Param        tanggal_dari    query  string  false  "Filter dari tanggal (YYYY-MM-DD)"
+// @Param        tanggal_sampai  query  string  false  "Filter sampai tanggal (YYYY-MM-DD)"
+// @Param        id_gudang       query  uint    false  "Filter ID gudang"
+// @Param        id_pelanggan    query  uint    false  "Filter ID pelanggan"
+// @Param        status          query  string  false  "ordered, ready, delivered, paid, cancelled"
+// @Success      200  {object}  utils.Response{data=dto.ListSalesResponse}
+// @Router       /sales-orders [get]
+func (h *SalesOrderHandler) ListOrders(c *gin.Context) {
+	var req dto.ListSalesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+
+	result, err := h.service.ListOrders(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil daftar pesanan", err.Error())
+		return
+	}
+
+	utils.OKWithMeta(c, "Daftar pesanan penjualan", result.Sales, utils.Meta{
+		Page:      result.Page,
+		Limit:     result.Limit,
+		Total:     int(result.Total),
+		TotalPage: result.TotalPages,
+	})
+}
+
+// MarkReady godoc
+// @Summary      Tandai pesanan siap
+// @Description  Stok keluar dengan FIFO dan HPP item pesanan tercatat
+// @Tags         sales-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID Pesanan"
+// @Success      200  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales-orders/{id}/ready [patch]
+func (h *SalesOrderHandler) MarkReady(c *gin.Context) {
+	h.changeStatus(c, h.service.MarkReady, "Pesanan siap, stok sudah dikurangi")
+}
+
+// MarkDelivered godoc
+// @Summary      Tandai pesanan terkirim
+// @Description  Pesanan otomatis menjadi paid jika sisa tagihan sudah 0
+// @Tags         sales-orders
+// @Produce      json
+// @Param        id   path      int  true  "ID Pesanan"
+// @Success      200  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales-orders/{id}/deliver [patch]
+func (h *SalesOrderHandler) MarkDelivered(c *gin.Context) {
+	h.changeStatus(c, h.service.MarkDelivered, "Pesanan berhasil ditandai terkirim")
+}
+
+// AddPayment godoc
+// @Summary      Catat pembayaran pesanan / piutang
+// @Description  Cicilan atau pelunasan; tidak boleh melebihi sisa tagihan. Mendukung application/json atau
+// @Description  multipart/form-data dengan file opsional di field "bukti_pembayaran".
+// @Tags         sales-orders
+// @Accept       json,multipart/form-data
+// @Produce      json
+// @Param        id                path      int                           true   "ID Pesanan"
+// @Param        body              body      dto.SalesOrderPaymentRequest  false  "JSON body"
+// @Param        bukti_pembayaran  formData  file                          false  "Foto/scan bukti pembayaran"
+// @Success      200  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales-orders/{id}/payments [post]
+// @Router       /receivables/{id}/payments [post]
+func (h *SalesOrderHandler) AddPayment(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	var req dto.SalesOrderPaymentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+
+	// Upload bukti pembayaran (opsional, hanya multipart)
+	var buktiPath string
+	if fileHeader, fileErr := c.FormFile("bukti_pembayaran"); fileErr == nil {
+		ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+		allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".pdf": true}
+		if !allowedExts[ext] {
+			utils.BadRequest(c, "Format file tidak didukung. Gunakan JPG, PNG, WEBP, atau PDF", nil)
+			return
+		}
+
+		uploadDir := "uploads/bukti_piutang"
+		if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
+			utils.InternalServerError(c, "Gagal membuat direktori upload", err.Error())
+			return
+		}
+
+		fileName := fmt.Sprintf("piutang_%d_%d%s", id, time.Now().UnixNano(), ext)
+		buktiPath = filepath.Join(uploadDir, fileName)
+		if err := c.SaveUploadedFile(fileHeader, buktiPath); err != nil {
+			utils.InternalServerError(c, "Gagal menyimpan file", err.Error())
+			return
+		}
+	}
+
+	result, err := h.service.AddPayment(uint(id), userID, &req, buktiPath)
+	if err != nil {
+		// Pembayaran ditolak, bukti yang sudah terlanjur diupload tidak dipakai
+		if buktiPath != "" {
+			os.Remove(buktiPath)
+		}
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Pembayaran pesanan berhasil dicatat", result)
+}
+
+// UpdateJatuhTempo godoc
+// @Summary      Ubah jatuh tempo piutang pesanan
+// @Description  Mengubah batas pelunasan pesanan yang belum lunas
+// @Tags         sales-orders
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                          true  "ID Pesanan"
+// @Param        body  body      dto.UpdateJatuhTempoRequest  true  "Jatuh tempo baru"
+// @Success      200  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /receivables/{id}/due-date [patch]
+func (h *SalesOrderHandler) UpdateJatuhTempo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	var req dto.UpdateJatuhTempoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "jatuh_tempo wajib diisi", err.Error())
+		return
+	}
+
+	result, err := h.service.UpdateJatuhTempo(uint(id), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Jatuh tempo piutang berhasil diubah", result)
+}
+
+// CancelOrder godoc
+// @Summary      Batalkan pesanan
+// @Description  Hanya pesanan yang belum dikirim. Stok yang sudah keluar dikembalikan ke batch FIFO asal.
+// @Tags         sales-orders
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                          true  "ID Pesanan"
+// @Param        body  body      dto.CancelSalesOrderRequest  true  "Alasan pembatalan"
+// @Success      200  {object}  utils.Response{data=dto.SalesDetailResponse}
+// @Router       /sales-orders/{id}/cancel [patch]
+func (h *SalesOrderHandler) CancelOrder(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	var req dto.CancelSalesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Alasan pembatalan wajib diisi", err.Error())
+		return
+	}
+
+	result, err := h.service.CancelOrder(uint(id), userID, req.Alasan)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Pesanan berhasil dibatalkan", result)
+}
+
+func (h *SalesOrderHandler) changeStatus(c *gin.Context, fn func(id, userID uint) (*dto.SalesDetailResponse, error), message string) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+
+	result, err := fn(uint(id), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, message, result)
+}
+
+func (h *SalesOrderHandler) handleError(c *gin.Context, err error) {
+	if err.Error() == "pesanan penjualan tidak ditemukan" {
+		utils.NotFound(c, "Pesanan tidak ditemukan")
+		return
+	}
+	utils.BadRequest(c, err.Error(), nil)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShiftHandler struct {
+	service services.ShiftService
+}
+
+func NewShiftHandler(service services.ShiftService) *ShiftHandler {
+	return &ShiftHandler{service: service}
+}
+
+// OpenShift godoc
+// @Summary      Buka shift kasir
+// @Description  Membuka shift dengan modal kas awal. Penjualan POS dan refund tunai wajib dilakukan dalam shift yang open.
+// @Tags         shifts
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.OpenShiftRequest  true  "Gudang & kas awal"
+// @Success      201  {object}  utils.Response{data=dto.ShiftResponse}
+// @Router       /shifts/open [post]
+func (h *ShiftHandler) OpenShift(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.OpenShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Format JSON tidak valid: "+err.Error(), nil)
+		return
+	}
+
+	result, err := h.service.OpenShift(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	utils.Created(c, "Shift berhasil dibuka", result)
+}
+
+// GetCurrentShift godoc
+// @Summary      Shift aktif kasir
+// @Description  Shift yang sedang open milik user yang login
+// @Tags         shifts
+// @Produce      json
+// @Success      200  {object}  utils.Response{data=dto.ShiftResponse}
+// @Router       /shifts/current [get]
+func (h *ShiftHandler) GetCurrentShift(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	result, err := h.service.GetCurrentShift(userID)
+	if err != nil {
+		if err.Error() == "shift kasir belum dibuka" {
+			utils.NotFound(c, "Shift kasir belum dibuka")
+			return
+		}
+		utils.InternalServerError(c, "Gagal mengambil shift aktif", err.Error())
+		return
+	}
+
+	utils.OK(c, "Shift aktif", result)
+}
+
+// ListShifts godoc
+// @Summary      List shift kasir
+// @Description  Tanpa permission shift.manage hanya shift milik sendiri yang ditampilkan
+// @Tags         shifts
+// @Produce      json
+// @Param        page            query  int     false  "Halaman"
+// @Param        limit           query  int     false  "Jumlah per halaman"
+// @Param        id_kasir        query  uint    false  "Filter ID kasir"
+// @Param        id_gudang       query  uint    false  "Filter ID gudang"
+// @Param        status          query  string  false  "open, closed"
+// @Param        tanggal_dari    query  string  false  "Filter tanggal buka dari (YYYY-MM-DD)"
+// @Param        tanggal_sampai  query  string  false  "Filter tanggal buka sampai (YYYY-MM-DD)"
+// @Success      200  {object}  utils.Response{data=dto.ListShiftResponse}
+// @Router       /shifts [get]
+func (h *ShiftHandler) ListShifts(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req dto.ListShiftRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+
+	result, err := h.service.ListShifts(userID, middleware.HasPermission(c, middleware.PermShiftManage), &req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil daftar shift", err.Error())
+		return
+	}
+
+	utils.OKWithMeta(c, "Daftar shift kasir", result.Shifts, utils.Meta{
+		Page:      result.Page,
+		Limit:     result.Limit,
+		Total:     int(result.Total),
+		TotalPage: result.TotalPages,
+	})
+}
+
+// GetShift godoc
+// @Summary      Detail shift kasir
+// @Tags         shifts
+// @Produce      json
+// @Param        id   path      int  true  "ID Shift"
+// @Success      200  {object}  utils.Response{data=dto.ShiftResponse}
+// @Router       /shifts/{id} [get]
+func (h *ShiftHandler) GetShift(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetShift(id, userID, middleware.HasPermission(c, middleware.PermShiftManage))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Detail shift kasir", result)
+}
+
+// CloseShift godoc
+// @Summary      Tutup shift kasir
+// @Description  Kas fisik dibandingkan dengan kas awal + penerimaan tunai - refund tunai. Hitungan metode non-tunai
+// @Description  (settlement EDC / mutasi rekening) opsional. Response berisi Z report.
+// @Tags         shifts
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                    true  "ID Shift"
+// @Param        body  body      dto.CloseShiftRequest  true  "Hasil hitung kas"
+// @Success      200  {object}  utils.Response{data=dto.ShiftReportResponse}
+// @Router       /shifts/{id}/close [patch]
+func (h *ShiftHandler) CloseShift(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CloseShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "kas_dihitung wajib diisi", err.Error())
+		return
+	}
+
+	result, err := h.service.CloseShift(id, userID, middleware.HasPermission(c, middleware.PermShiftManage), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Shift berhasil ditutup", result)
+}
+
+// GetShiftReport godoc
+// @Summary      Laporan shift (X / Z report)
+// @Description  Laporan siap cetak: Z report untuk shift yang sudah ditutup, X report (rekap berjalan) untuk shift open
+// @Tags         shifts
+// @Produce      json
+// @Param        id   path      int  true  "ID Shift"
+// @Success      200  {object}  utils.Response{data=dto.ShiftReportResponse}
+// @Router       /shifts/{id}/report [get]
+func (h *ShiftHandler) GetShiftReport(c *gin.Context) {
+	userID, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetShiftReport(id, userID, middleware.HasPermission(c, middleware.PermShiftManage))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Laporan shift kasir", result)
+}
+
+func (h *ShiftHandler) parseRequest(c *gin.Context) (uint, uint, bool) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+func (h *ShiftHandler) handleError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "shift kasir tidak ditemukan":
+		utils.NotFound(c, "Shift tidak ditemukan")
+	case "tidak berhak mengakses shift kasir lain":
+		utils.Forbidden(c, "Tidak berhak mengakses shift kasir lain")
+	default:
+		utils.BadRequest(c, err.Error(), nil)
+	}
+}
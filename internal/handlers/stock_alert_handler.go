@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockAlertHandler struct {
+	service services.StockAlertService
+}
+
+func NewStockAlertHandler(service services.StockAlertService) *StockAlertHandler {
+	return &StockAlertHandler{service: service}
+}
+
+// ListAlerts godoc
+// @Summary      List stock alerts
+// @Description  Low-stock alerts per product and warehouse. Defaults to active alerts (open + acknowledged).
+// @Tags         stocks
+// @Produce      json
+// @Param        status        query  string  false  "active (default), open, acknowledged, resolved, all"
+// @Param        warehouse_id  query  int     false  "Warehouse ID"
+// @Param        product_id    query  int     false  "Product ID"
+// @Param        page          query  int     false  "Page"
+// @Param        limit         query  int     false  "Limit"
+// @Success      200  {object}  utils.Response{data=[]dto.StockAlertResponse}
+// @Router       /stock-alerts [get]
+func (h *StockAlertHandler) ListAlerts(c *gin.Context) {
+	var req dto.ListStockAlertRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query", err.Error())
+		return
+	}
+
+	results, total, err := h.service.ListAlerts(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch stock alerts", err.Error())
+		return
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	utils.OKWithMeta(c, "Stock alerts fetched successfully", results, utils.Meta{
+		Page:      page,
+		Limit:     limit,
+		Total:     int(total),
+		TotalPage: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetAlert godoc
+// @Summary      Get stock alert
+// @Tags         stocks
+// @Produce      json
+// @Param        id   path      int  true  "Stock Alert ID"
+// @Success      200  {object}  utils.Response{data=dto.StockAlertResponse}
+// @Router       /stock-alerts/{id} [get]
+func (h *StockAlertHandler) GetAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	result, err := h.service.GetAlertByID(uint(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Stock alert fetched successfully", result)
+}
+
+// AcknowledgeAlert godoc
+// @Summary      Acknowledge stock alert
+// @Description  Marks an open alert as handled. The alert stays active until stock is back above the product minimum.
+// @Tags         stocks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int                               true   "Stock Alert ID"
+// @Param        req  body      dto.AcknowledgeStockAlertRequest  false  "Notes"
+// @Success      200  {object}  utils.Response{data=dto.StockAlertResponse}
+// @Router       /stock-alerts/{id}/acknowledge [patch]
+func (h *StockAlertHandler) AcknowledgeAlert(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	var req dto.AcknowledgeStockAlertRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.BadRequest(c, "Invalid request", err.Error())
+			return
+		}
+	}
+
+	userID := utils.GetUserIDValidity(c)
+	result, err := h.service.AcknowledgeAlert(uint(id), userID, req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Stock alert acknowledged", result)
+}
+
+// handleError memetakan error peringatan stok ke HTTP response
+func (h *StockAlertHandler) handleError(c *gin.Context, err error) {
+	if err.Error() == "stock alert not found" {
+		utils.NotFound(c, "Stock alert not found")
+		return
+	}
+	utils.BadRequest(c, err.Error(), nil)
+}
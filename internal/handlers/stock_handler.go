@@ -82,12 +82,12 @@ func (h *StockHandler) GetStockHistory(c *gin.Context) {
 
 // CreateStockIn godoc
 // @Summary      Input Stock (Manual)
-// @Description  Manually add stock (e.g. from supplier without PO, or found items)
+// @Description  Submit a manual stock in (e.g. from supplier without PO, or found items). The document is pending until an owner approves it.
 // @Tags         stocks
 // @Accept       json
 // @Produce      json
 // @Param        req  body      dto.CreateStockInRequest  true  "Request Body"
-// @Success      201  {object}  utils.Response
+// @Success      201  {object}  utils.Response{data=dto.StockInResponse}
 // @Router       /stocks/in [post]
 func (h *StockHandler) CreateStockIn(c *gin.Context) {
 	var req dto.CreateStockInRequest
@@ -97,12 +97,142 @@ func (h *StockHandler) CreateStockIn(c *gin.Context) {
 	}
 
 	userID := utils.GetUserIDValidity(c)
-	if err := h.service.CreateStockIn(userID, req); err != nil {
+	result, err := h.service.CreateStockIn(userID, req)
+	if err != nil {
 		utils.InternalServerError(c, "Failed to create stock in", err.Error())
 		return
 	}
 
-	utils.Created(c, "Stock in recorded successfully", nil)
+	utils.Created(c, "Stock in submitted, waiting for approval", result)
+}
+
+// ListStockIn godoc
+// @Summary      List stock in documents
+// @Description  List manual and PO stock in documents filtered by status and supplier
+// @Tags         stocks
+// @Produce      json
+// @Param        page         query  int     false  "Page number"
+// @Param        limit        query  int     false  "Items per page"
+// @Param        status       query  string  false  "pending, approved, rejected"
+// @Param        supplier_id  query  int     false  "Supplier ID"
+// @Success      200  {object}  utils.Response{data=[]dto.StockInResponse}
+// @Router       /stocks/in [get]
+func (h *StockHandler) ListStockIn(c *gin.Context) {
+	var req dto.ListStockInRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query", err.Error())
+		return
+	}
+
+	results, total, err := h.service.ListStockIn(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch stock in", err.Error())
+		return
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	utils.OKWithMeta(c, "Stock in fetched successfully", results, utils.Meta{
+		Page:      page,
+		Limit:     limit,
+		Total:     int(total),
+		TotalPage: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetStockIn godoc
+// @Summary      Get stock in document
+// @Description  Get a stock in document with its lines (price, warehouse, location)
+// @Tags         stocks
+// @Produce      json
+// @Param        id   path      int  true  "Stock In ID"
+// @Success      200  {object}  utils.Response{data=dto.StockInResponse}
+// @Router       /stocks/in/{id} [get]
+func (h *StockHandler) GetStockIn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	result, err := h.service.GetStockInByID(uint(id))
+	if err != nil {
+		h.handleStockInError(c, err)
+		return
+	}
+
+	utils.OK(c, "Stock in fetched successfully", result)
+}
+
+// ApproveStockIn godoc
+// @Summary      Approve stock in
+// @Description  Owner approves a pending stock in. Batches, stock balance and supplier debt are booked at this point.
+// @Tags         stocks
+// @Produce      json
+// @Param        id   path      int  true  "Stock In ID"
+// @Success      200  {object}  utils.Response
+// @Router       /stocks/in/{id}/approve [patch]
+func (h *StockHandler) ApproveStockIn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	userID := utils.GetUserIDValidity(c)
+	if err := h.service.ApproveStockIn(uint(id), userID); err != nil {
+		h.handleStockInError(c, err)
+		return
+	}
+
+	utils.OK(c, "Stock in approved", nil)
+}
+
+// RejectStockIn godoc
+// @Summary      Reject stock in
+// @Description  Owner rejects a pending stock in with a reason. Stock is not changed.
+// @Tags         stocks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int                        true  "Stock In ID"
+// @Param        req  body      dto.RejectStockInRequest  true  "Request Body"
+// @Success      200  {object}  utils.Response
+// @Router       /stocks/in/{id}/reject [patch]
+func (h *StockHandler) RejectStockIn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	var req dto.RejectStockInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request", err.Error())
+		return
+	}
+
+	userID := utils.GetUserIDValidity(c)
+	if err := h.service.RejectStockIn(uint(id), userID, req.Reason); err != nil {
+		h.handleStockInError(c, err)
+		return
+	}
+
+	utils.OK(c, "Stock in rejected", nil)
+}
+
+// handleStockInError memetakan error approval barang masuk ke HTTP response
+func (h *StockHandler) handleStockInError(c *gin.Context, err error) {
+	if err.Error() == "stock in not found" {
+		utils.NotFound(c, "Stock in not found")
+		return
+	}
+	utils.BadRequest(c, err.Error(), nil)
 }
 
 // CreateStockOut godoc
@@ -112,7 +242,7 @@ func (h *StockHandler) CreateStockIn(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        req  body      dto.CreateStockOutRequest  true  "Request Body"
-// @Success      201  {object}  utils.Response
+// @Success      201  {object}  utils.Response{data=dto.StockOutResponse}
 // @Router       /stocks/out [post]
 func (h *StockHandler) CreateStockOut(c *gin.Context) {
 	var req dto.CreateStockOutRequest
@@ -122,62 +252,66 @@ func (h *StockHandler) CreateStockOut(c *gin.Context) {
 	}
 
 	userID := utils.GetUserIDValidity(c)
-	if err := h.service.CreateStockOut(userID, req); err != nil {
+	result, err := h.service.CreateStockOut(userID, req)
+	if err != nil {
 		utils.InternalServerError(c, "Failed to create stock out", err.Error())
 		return
 	}
 
-	utils.Created(c, "Stock out recorded successfully", nil)
+	utils.Created(c, "Stock out recorded successfully", result)
 }
 
-// CreateStockOpname godoc
-// @Summary      Stock Adjustment (Opname)
-// @Description  Adjust stock to match physical count
+// GetStockOut godoc
+// @Summary      Get stock out document
+// @Description  Get a stock out document with its lines (one line per FIFO batch)
 // @Tags         stocks
-// @Accept       json
 // @Produce      json
-// @Param        req  body      dto.CreateStockOpnameRequest  true  "Request Body"
-// @Success      201  {object}  utils.Response
-// @Router       /stocks/adjustment [post]
-func (h *StockHandler) CreateStockOpname(c *gin.Context) {
-	var req dto.CreateStockOpnameRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "Invalid request", err.Error())
+// @Param        id   path      int  true  "Stock Out ID"
+// @Success      200  {object}  utils.Response{data=dto.StockOutResponse}
+// @Router       /stocks/out/{id} [get]
+func (h *StockHandler) GetStockOut(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
 		return
 	}
 
-	userID := utils.GetUserIDValidity(c)
-	if err := h.service.CreateStockOpname(userID, req); err != nil {
-		utils.InternalServerError(c, "Failed to create stock adjustment", err.Error())
+	result, err := h.service.GetStockOutByID(uint(id))
+	if err != nil {
+		if err.Error() == "stock out not found" {
+			utils.NotFound(c, "Stock out not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to fetch stock out", err.Error())
 		return
 	}
 
-	utils.Created(c, "Stock adjustment recorded successfully", nil)
+	utils.OK(c, "Stock out fetched successfully", result)
 }
 
-// CreateStockTransfer godoc
-// @Summary      Transfer Stock
-// @Description  Transfer stock between warehouses
+// CreateStockOpname godoc
+// @Summary      Stock Adjustment (Opname)
+// @Description  Adjust stock to match physical count
 // @Tags         stocks
 // @Accept       json
 // @Produce      json
-// @Param        req  body      dto.CreateStockTransferRequest  true  "Request Body"
+// @Param        req  body      dto.CreateStockOpnameRequest  true  "Request Body"
 // @Success      201  {object}  utils.Response
-// @Router       /stocks/transfer [post]
-func (h *StockHandler) CreateStockTransfer(c *gin.Context) {
-	var req dto.CreateStockTransferRequest
+// @Router       /stocks/adjustment [post]
+func (h *StockHandler) CreateStockOpname(c *gin.Context) {
+	var req dto.CreateStockOpnameRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "Invalid request", err.Error())
 		return
 	}
 
 	userID := utils.GetUserIDValidity(c)
-	if err := h.service.CreateStockTransfer(userID, req); err != nil {
-		utils.InternalServerError(c, "Failed to transfer stock", err.Error())
+	if err := h.service.CreateStockOpname(userID, req); err != nil {
+		utils.InternalServerError(c, "Failed to create stock adjustment", err.Error())
 		return
 	}
 
-	utils.Created(c, "Stock transfer recorded successfully", nil)
+	utils.Created(c, "Stock adjustment recorded successfully", nil)
 }
 
 // GetStockBatches godoc
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockTransferHandler struct {
+	service services.StockTransferService
+}
+
+func NewStockTransferHandler(service services.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{service: service}
+}
+
+// CreateStockTransfer godoc
+// @Summary      Transfer Stock (direct)
+// @Description  Transfer stock between warehouses in one step. A transfer document is created, shipped and received immediately.
+// @Tags         stocks
+// @Accept       json
+// @Produce      json
+// @Param        req  body      dto.CreateStockTransferRequest  true  "Request Body"
+// @Success      201  {object}  utils.Response{data=dto.StockTransferResponse}
+// @Router       /stocks/transfer [post]
+func (h *StockTransferHandler) CreateStockTransfer(c *gin.Context) {
+	var req dto.CreateStockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request", err.Error())
+		return
+	}
+
+	userID := utils.GetUserIDValidity(c)
+	result, err := h.service.CreateDirectTransfer(userID, req)
+	if err != nil {
+		utils.BadRequest(c, "Failed to transfer stock", err.Error())
+		return
+	}
+
+	utils.Created(c, "Stock transfer recorded successfully", result)
+}
+
+// CreateTransferDocument godoc
+// @Summary      Create transfer document
+// @Description  Create a draft stock transfer. Stock does not move until the transfer is shipped.
+// @Tags         stock-transfers
+// @Accept       json
+// @Produce      json
+// @Param        req  body      dto.CreateStockTransferRequest  true  "Request Body"
+// @Success      201  {object}  utils.Response{data=dto.StockTransferResponse}
+// @Router       /stock-transfers [post]
+func (h *StockTransferHandler) CreateTransferDocument(c *gin.Context) {
+	var req dto.CreateStockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request", err.Error())
+		return
+	}
+
+	userID := utils.GetUserIDValidity(c)
+	result, err := h.service.CreateTransfer(userID, req)
+	if err != nil {
+		utils.BadRequest(c, "Failed to create transfer", err.Error())
+		return
+	}
+
+	utils.Created(c, "Stock transfer created (status: draft)", result)
+}
+
+// ListTransfers godoc
+// @Summary      List stock transfers
+// @Description  List transfer documents filtered by status and warehouse
+// @Tags         stock-transfers
+// @Produce      json
+// @Param        page                 query  int     false  "Page number"
+// @Param        limit                query  int     false  "Items per page"
+// @Param        status               query  string  false  "draft, shipped, received, cancelled"
+// @Param        source_warehouse_id  query  int     false  "Source warehouse ID"
+// @Param        target_warehouse_id  query  int     false  "Target warehouse ID"
+// @Success      200  {object}  utils.Response{data=[]dto.StockTransferResponse}
+// @Router       /stock-transfers [get]
+func (h *StockTransferHandler) ListTransfers(c *gin.Context) {
+	var req dto.ListStockTransferRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query", err.Error())
+		return
+	}
+
+	results, total, err := h.service.ListTransfers(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Failed to fetch transfers", err.Error())
+		return
+	}
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	utils.OKWithMeta(c, "Transfers fetched successfully", results, utils.Meta{
+		Page:      page,
+		Limit:     limit,
+		Total:     int(total),
+		TotalPage: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetTransfer godoc
+// @Summary      Get stock transfer detail
+// @Description  Get a transfer document with its items and FIFO batch breakdown
+// @Tags         stock-transfers
+// @Produce      json
+// @Param        id   path      int  true  "Transfer ID"
+// @Success      200  {object}  utils.Response{data=dto.StockTransferResponse}
+// @Router       /stock-transfers/{id} [get]
+func (h *StockTransferHandler) GetTransfer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	result, err := h.service.GetTransferByID(uint(id))
+	if err != nil {
+		if err.Error() == "transfer stok tidak ditemukan" {
+			utils.NotFound(c, "Stock transfer not found")
+			return
+		}
+		utils.InternalServerError(c, "Failed to fetch transfer", err.Error())
+		return
+	}
+
+	utils.OK(c, "Transfer fetched successfully", result)
+}
+
+// ShipTransfer godoc
+// @Summary      Ship stock transfer
+// @Description  Deduct stock (FIFO) from the source warehouse. Stock stays in-transit until received.
+// @Tags         stock-transfers
+// @Produce      json
+// @Param        id   path      int  true  "Transfer ID"
+// @Success      200  {object}  utils.Response
+// @Router       /stock-transfers/{id}/ship [patch]
+func (h *StockTransferHandler) ShipTransfer(c *gin.Context) {
+	h.changeStatus(c, h.service.ShipTransfer, "Stock transfer shipped (in-transit)")
+}
+
+// ReceiveTransfer godoc
+// @Summary      Receive stock transfer
+// @Description  Book in-transit stock into the target warehouse, keeping the original batch date and cost
+// @Tags         stock-transfers
+// @Produce      json
+// @Param        id   path      int  true  "Transfer ID"
+// @Success      200  {object}  utils.Response
+// @Router       /stock-transfers/{id}/receive [patch]
+func (h *StockTransferHandler) ReceiveTransfer(c *gin.Context) {
+	h.changeStatus(c, h.service.ReceiveTransfer, "Stock transfer received")
+}
+
+// CancelTransfer godoc
+// @Summary      Cancel stock transfer
+// @Description  Cancel a draft or shipped transfer. In-transit stock is returned to its source batches.
+// @Tags         stock-transfers
+// @Produce      json
+// @Param        id   path      int  true  "Transfer ID"
+// @Success      200  {object}  utils.Response
+// @Router       /stock-transfers/{id}/cancel [patch]
+func (h *StockTransferHandler) CancelTransfer(c *gin.Context) {
+	h.changeStatus(c, h.service.CancelTransfer, "Stock transfer cancelled")
+}
+
+// changeStatus menjalankan aksi status transfer dan memetakan error ke HTTP response
+func (h *StockTransferHandler) changeStatus(c *gin.Context, action func(id, userID uint) error, successMsg string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid ID", nil)
+		return
+	}
+
+	userID := utils.GetUserIDValidity(c)
+	if err := action(uint(id), userID); err != nil {
+		if err.Error() == "transfer stok tidak ditemukan" {
+			utils.NotFound(c, "Stock transfer not found")
+			return
+		}
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+
+	utils.OK(c, successMsg, nil)
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/services"
 	"real-erp-mebel/be/internal/utils"
 
@@ -79,12 +80,6 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
-	// Check permission (only owner and admin can list all users)
-	if !h.hasPermission(c, []string{"owner", "admin_gudang"}) {
-		utils.Forbidden(c, "You don't have permission to list users")
-		return
-	}
-
 	response, err := h.userService.ListUsers(req)
 	if err != nil {
 		h.handleError(c, err)
@@ -116,7 +111,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 	// Check permission (users can only view their own profile, owner/admin can view all)
 	userIDValue, _ := c.Get("user_id")
 	userID := h.getUserIDFromContext(userIDValue)
-	if userID != uint(id) && !h.hasPermission(c, []string{"owner", "admin_gudang"}) {
+	if userID != uint(id) && !middleware.HasPermission(c, middleware.PermUserManage) {
 		utils.Forbidden(c, "You don't have permission to view this user")
 		return
 	}
@@ -144,19 +139,13 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Failure 403 {object} utils.Response
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	// Check permission (only owner and admin can create users)
-	if !h.hasPermission(c, []string{"owner", "admin_gudang"}) {
-		utils.Forbidden(c, "You don't have permission to create users")
-		return
-	}
-
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.BadRequest(c, "Invalid request body", err)
 		return
 	}
 
-	user, err := h.userService.CreateUser(req)
+	user, err := h.userService.CreateUser(utils.GetUserRole(c), req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -190,7 +179,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	// Check permission
 	userIDValue, _ := c.Get("user_id")
 	userID := h.getUserIDFromContext(userIDValue)
-	isOwnerOrAdmin := h.hasPermission(c, []string{"owner", "admin_gudang"})
+	isOwnerOrAdmin := middleware.HasPermission(c, middleware.PermUserManage)
 
 	if userID != uint(id) && !isOwnerOrAdmin {
 		utils.Forbidden(c, "You don't have permission to update this user")
@@ -209,7 +198,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		req.Peran = nil
 		req.Aktif = nil
 
-		user, err := h.userService.UpdateUser(uint(id), req)
+		user, err := h.userService.UpdateUser(userID, utils.GetUserRole(c), uint(id), req)
 		if err != nil {
 			h.handleError(c, err)
 			return
@@ -225,7 +214,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.UpdateUser(uint(id), req)
+	user, err := h.userService.UpdateUser(userID, utils.GetUserRole(c), uint(id), req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -248,12 +237,6 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 // @Failure 404 {object} utils.Response
 // @Router /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
-	// Check permission (only owner and admin can delete users)
-	if !h.hasPermission(c, []string{"owner", "admin_gudang"}) {
-		utils.Forbidden(c, "You don't have permission to delete users")
-		return
-	}
-
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		utils.BadRequest(c, "Invalid user ID", err)
@@ -303,6 +286,92 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	utils.OK(c, "Password changed successfully", nil)
 }
 
+// CreateInvitation mengundang staff baru
+// @Summary Create invitation
+// @Description Membuat undangan staff dengan role tertentu. Token hanya ditampilkan sekali di response ini.
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateInvitationRequest true "Create invitation request"
+// @Success 201 {object} utils.Response{data=dto.InvitationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /users/invitations [post]
+func (h *UserHandler) CreateInvitation(c *gin.Context) {
+	var req dto.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	userIDValue, _ := c.Get("user_id")
+	userID := h.getUserIDFromContext(userIDValue)
+
+	invitation, err := h.userService.CreateInvitation(userID, utils.GetUserRole(c), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.Created(c, "Invitation created successfully", invitation)
+}
+
+// ListInvitations mendapatkan daftar undangan
+// @Summary List invitations
+// @Description Mendapatkan daftar undangan staff beserta statusnya
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} utils.Response{data=dto.ListInvitationsResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /users/invitations [get]
+func (h *UserHandler) ListInvitations(c *gin.Context) {
+	var req dto.ListInvitationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := h.userService.ListInvitations(req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Invitations retrieved successfully", response)
+}
+
+// RevokeInvitation membatalkan undangan yang belum dipakai
+// @Summary Revoke invitation
+// @Description Membatalkan undangan yang masih pending
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/invitations/{id} [delete]
+func (h *UserHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "Invalid invitation ID", err)
+		return
+	}
+
+	if err := h.userService.RevokeInvitation(uint(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.OK(c, "Invitation revoked successfully", nil)
+}
+
 // Helper functions
 
 // getUserIDFromContext mengkonversi user_id dari context ke uint
@@ -328,27 +397,6 @@ func (h *UserHandler) getUserIDFromContext(userID interface{}) uint {
 	}
 }
 
-// hasPermission mengecek apakah user memiliki permission berdasarkan role
-func (h *UserHandler) hasPermission(c *gin.Context, allowedRoles []string) bool {
-	role, exists := c.Get("role")
-	if !exists {
-		return false
-	}
-
-	roleStr, ok := role.(string)
-	if !ok {
-		return false
-	}
-
-	for _, allowedRole := range allowedRoles {
-		if roleStr == allowedRole {
-			return true
-		}
-	}
-
-	return false
-}
-
 // handleError menangani error dan mengembalikan response yang sesuai
 func (h *UserHandler) handleError(c *gin.Context, err error) {
 	appErr := utils.GetAppError(err)
@@ -362,6 +410,8 @@ func (h *UserHandler) handleError(c *gin.Context, err error) {
 		utils.BadRequest(c, appErr.Message, appErr.Err)
 	case utils.ErrCodeForbidden:
 		utils.Forbidden(c, appErr.Message)
+	case utils.ErrCodeConflict:
+		utils.Conflict(c, appErr.Message)
 	default:
 		h.logger.Error("Internal server error",
 			zap.String("code", appErr.Code),
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/services"
+	"real-erp-mebel/be/internal/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	service services.WebhookService
+}
+
+func NewWebhookHandler(service services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateWebhook godoc
+// @Summary      Daftarkan webhook
+// @Description  Mendaftarkan endpoint HTTP yang menerima event (POST JSON bertanda tangan HMAC-SHA256). Secret hanya ditampilkan sekali di response ini.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.CreateWebhookRequest  true  "Data webhook"
+// @Success      201   {object}  utils.Response{data=dto.WebhookResponse}
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID := utils.GetUserIDValidity(c)
+	if userID == 0 {
+		utils.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.CreateWebhook(userID, &req)
+	if err != nil {
+		utils.BadRequest(c, err.Error(), nil)
+		return
+	}
+	utils.Created(c, "Webhook berhasil didaftarkan", result)
+}
+
+// ListWebhooks godoc
+// @Summary      List webhook
+// @Tags         webhooks
+// @Produce      json
+// @Param        page   query  int  false  "Halaman"
+// @Param        limit  query  int  false  "Jumlah per halaman"
+// @Success      200  {object}  utils.Response{data=[]dto.WebhookResponse}
+// @Router       /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	var req dto.ListWebhookRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	results, total, err := h.service.ListWebhooks(&req)
+	if err != nil {
+		utils.InternalServerError(c, "Gagal mengambil data webhook", err.Error())
+		return
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	utils.OKWithMeta(c, "Daftar webhook", results, utils.Meta{
+		Page: page, Limit: limit, Total: int(total), TotalPage: (int(total) + limit - 1) / limit,
+	})
+}
+
+// GetWebhook godoc
+// @Summary      Detail webhook
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "ID webhook"
+// @Success      200  {object}  utils.Response{data=dto.WebhookResponse}
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	result, err := h.service.GetWebhookByID(uint(id))
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil data webhook")
+		return
+	}
+	utils.OK(c, "Detail webhook", result)
+}
+
+// UpdateWebhook godoc
+// @Summary      Update webhook
+// @Description  Mengubah nama, URL, tipe event, secret atau status aktif webhook
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                       true  "ID webhook"
+// @Param        body  body      dto.UpdateWebhookRequest  true  "Data webhook"
+// @Success      200   {object}  utils.Response{data=dto.WebhookResponse}
+// @Router       /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "Request tidak valid", err.Error())
+		return
+	}
+	result, err := h.service.UpdateWebhook(uint(id), &req)
+	if err != nil {
+		h.handleError(c, err, "")
+		return
+	}
+	utils.OK(c, "Webhook berhasil diubah", result)
+}
+
+// DeleteWebhook godoc
+// @Summary      Hapus webhook
+// @Description  Menghapus webhook beserta log pengirimannya
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "ID webhook"
+// @Success      200  {object}  utils.Response
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	if err := h.service.DeleteWebhook(uint(id)); err != nil {
+		h.handleError(c, err, "Gagal menghapus webhook")
+		return
+	}
+	utils.OK(c, "Webhook berhasil dihapus", nil)
+}
+
+// ListDeliveries godoc
+// @Summary      Log pengiriman webhook
+// @Description  Riwayat pengiriman event ke webhook (status HTTP, respon, error, jumlah percobaan), terbaru dulu
+// @Tags         webhooks
+// @Produce      json
+// @Param        id      path   int     true   "ID webhook"
+// @Param        page    query  int     false  "Halaman"
+// @Param        limit   query  int     false  "Jumlah per halaman"
+// @Param        status  query  string  false  "pending, success, failed"
+// @Success      200  {object}  utils.Response{data=[]dto.WebhookDeliveryResponse}
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	var req dto.ListWebhookDeliveryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "Parameter tidak valid", err.Error())
+		return
+	}
+	results, total, err := h.service.ListDeliveries(uint(id), &req)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil log pengiriman webhook")
+		return
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	utils.OKWithMeta(c, "Log pengiriman webhook", results, utils.Meta{
+		Page: page, Limit: limit, Total: int(total), TotalPage: (int(total) + limit - 1) / limit,
+	})
+}
+
+// RetryDelivery godoc
+// @Summary      Kirim ulang webhook
+// @Description  Menjadwalkan ulang pengiriman yang gagal / masih pending agar segera dikirim
+// @Tags         webhooks
+// @Produce      json
+// @Param        id           path      int  true  "ID webhook"
+// @Param        delivery_id  path      int  true  "ID pengiriman"
+// @Success      200  {object}  utils.Response{data=dto.WebhookDeliveryResponse}
+// @Router       /webhooks/{id}/deliveries/{delivery_id}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID tidak valid", nil)
+		return
+	}
+	deliveryID, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "ID pengiriman tidak valid", nil)
+		return
+	}
+	result, err := h.service.RetryDelivery(uint(id), uint(deliveryID))
+	if err != nil {
+		h.handleError(c, err, "")
+		return
+	}
+	utils.OK(c, "Pengiriman webhook dijadwalkan ulang", result)
+}
+
+// handleError memetakan error service: not found → 404, internalMessage kosong → 400, selain itu 500
+func (h *WebhookHandler) handleError(c *gin.Context, err error, internalMessage string) {
+	switch err.Error() {
+	case "webhook tidak ditemukan":
+		utils.NotFound(c, "Webhook tidak ditemukan")
+	case "pengiriman webhook tidak ditemukan":
+		utils.NotFound(c, "Pengiriman webhook tidak ditemukan")
+	default:
+		if internalMessage == "" {
+			utils.BadRequest(c, err.Error(), nil)
+			return
+		}
+		utils.InternalServerError(c, internalMessage, err.Error())
+	}
+}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
 	"real-erp-mebel/be/internal/config"
+	"real-erp-mebel/be/internal/models"
 	"real-erp-mebel/be/internal/utils"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +15,106 @@ import (
 
 // Note: Import gin.H untuk rate limiter
 
+// AuthUserLoader memuat user dari database berdasarkan ID (user yang di-soft delete dianggap tidak ada)
+type AuthUserLoader func(id uint) (*models.User, error)
+
+var authUserLoader AuthUserLoader
+
+// SetAuthUserLoader memasang loader user untuk AuthMiddleware (dipanggil sekali di routes.SetupRoutes)
+func SetAuthUserLoader(loader AuthUserLoader) {
+	authUserLoader = loader
+}
+
+// AuthClaims adalah identitas user hasil validasi access token
+type AuthClaims struct {
+	UserID uint
+	Email  string
+	Role   string
+}
+
+// BearerToken mengambil token dari header Authorization ("Bearer <token>" atau token saja).
+// Error yang dikembalikan aman ditampilkan ke client.
+func BearerToken(authHeader string) (string, error) {
+	if authHeader == "" {
+		return "", errors.New("Authorization header required")
+	}
+
+	var tokenString string
+	parts := strings.Split(authHeader, " ")
+	if len(parts) == 2 && parts[0] == "Bearer" {
+		// Format: "Bearer <token>"
+		tokenString = parts[1]
+	} else if len(parts) == 1 {
+		// Format: just token (for backward compatibility)
+		tokenString = parts[0]
+	} else {
+		return "", errors.New("Invalid authorization header format. Use 'Bearer <token>' or just '<token>'")
+	}
+
+	if tokenString == "" {
+		return "", errors.New("Token is required")
+	}
+	return tokenString, nil
+}
+
+// ValidateAccessToken memvalidasi JWT (signature & expiry) lalu status user di database.
+// Dipakai bersama oleh AuthMiddleware dan WebSocket. Error yang dikembalikan aman ditampilkan ke client.
+func ValidateAccessToken(tokenString string) (*AuthClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, utils.ErrTokenInvalid
+		}
+		return []byte(config.AppConfig.JWT.Secret), nil
+	})
+
+	if err != nil {
+		// Provide more specific error message
+		if strings.Contains(err.Error(), "expired") {
+			return nil, errors.New("Token has expired. Please login again.")
+		} else if strings.Contains(err.Error(), "signature") {
+			return nil, errors.New("Invalid token signature")
+		}
+		return nil, errors.New("Invalid or expired token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !token.Valid || !ok {
+		return nil, errors.New("Invalid or expired token")
+	}
+
+	result := &AuthClaims{}
+	if userID, ok := claims["user_id"].(float64); ok {
+		result.UserID = uint(userID)
+	}
+	if email, ok := claims["email"].(string); ok {
+		result.Email = email
+	}
+	if role, ok := claims["role"].(string); ok {
+		result.Role = role
+	}
+
+	// Tolak token milik user yang sudah dinonaktifkan/dihapus atau yang sudah dicabut (logout semua device)
+	if authUserLoader != nil {
+		user, err := authUserLoader(result.UserID)
+		if err != nil {
+			return nil, errors.New("User no longer exists")
+		}
+		if !user.Aktif {
+			return nil, errors.New("User is inactive")
+		}
+		version, _ := claims["ver"].(float64)
+		if int(version) != user.VersiToken {
+			return nil, errors.New("Token has been revoked. Please login again.")
+		}
+
+		// Role selalu diambil dari database agar perubahan role langsung berlaku
+		result.Role = user.Peran
+	}
+
+	return result, nil
+}
+
 // AuthMiddleware adalah middleware untuk autentikasi JWT
 func AuthMiddleware() gin.HandlerFunc {
 	logger := utils.GetLogger()
@@ -24,92 +126,32 @@ func AuthMiddleware() gin.HandlerFunc {
 			zap.String("auth_header", authHeader),
 		)
 
-		if authHeader == "" {
-			logger.Warn("Missing authorization header",
-				zap.String("path", c.Request.URL.Path),
-			)
-			utils.Unauthorized(c, "Authorization header required")
-			c.Abort()
-			return
-		}
-
-		// Extract token from "Bearer <token>" or just token
-		var tokenString string
-		parts := strings.Split(authHeader, " ")
-		if len(parts) == 2 && parts[0] == "Bearer" {
-			// Format: "Bearer <token>"
-			tokenString = parts[1]
-		} else if len(parts) == 1 {
-			// Format: just token (for backward compatibility)
-			tokenString = parts[0]
-		} else {
-			logger.Warn("Invalid authorization header format",
-				zap.String("path", c.Request.URL.Path),
-				zap.String("header", authHeader),
-			)
-			utils.Unauthorized(c, "Invalid authorization header format. Use 'Bearer <token>' or just '<token>'")
-			c.Abort()
-			return
-		}
-
-		if tokenString == "" {
-			logger.Warn("Empty token",
-				zap.String("path", c.Request.URL.Path),
-			)
-			utils.Unauthorized(c, "Token is required")
-			c.Abort()
-			return
-		}
-
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, utils.ErrTokenInvalid
-			}
-			return []byte(config.AppConfig.JWT.Secret), nil
-		})
-
+		tokenString, err := BearerToken(authHeader)
 		if err != nil {
-			logger.Warn("Token parsing error",
+			logger.Warn("Invalid authorization header",
 				zap.String("path", c.Request.URL.Path),
 				zap.Error(err),
-				zap.String("error_type", err.Error()),
 			)
-			// Provide more specific error message
-			errMsg := "Invalid or expired token"
-			if strings.Contains(err.Error(), "expired") {
-				errMsg = "Token has expired. Please login again."
-			} else if strings.Contains(err.Error(), "signature") {
-				errMsg = "Invalid token signature"
-			}
-			utils.Unauthorized(c, errMsg)
+			utils.Unauthorized(c, err.Error())
 			c.Abort()
 			return
 		}
 
-		if !token.Valid {
-			logger.Warn("Invalid token",
+		claims, err := ValidateAccessToken(tokenString)
+		if err != nil {
+			logger.Warn("Token validation failed",
 				zap.String("path", c.Request.URL.Path),
+				zap.Error(err),
 			)
-			utils.Unauthorized(c, "Invalid or expired token")
+			utils.Unauthorized(c, err.Error())
 			c.Abort()
 			return
 		}
 
-		// Extract claims
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			// Set user context
-			if userID, ok := claims["user_id"].(float64); ok {
-				c.Set("user_id", uint(userID))
-			}
-			if email, ok := claims["email"].(string); ok {
-				c.Set("email", email)
-			}
-			if role, ok := claims["role"].(string); ok {
-				c.Set("role", role)
-			}
-		}
+		// Set user context
+		c.Set("user_id", claims.UserID)
+		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
 
 		c.Next()
 	}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"real-erp-mebel/be/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// RolePermissionLoader memuat kode permission sebuah role dari database.
+// found=false berarti role belum terdaftar di tabel peran.
+type RolePermissionLoader func(role string) (permissions []string, found bool, err error)
+
+// rolePermissionCacheTTL membatasi umur cache agar perubahan role di instance lain ikut terbaca
+const rolePermissionCacheTTL = time.Minute
+
+type rolePermissionEntry struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+var (
+	rolePermissionMu     sync.RWMutex
+	rolePermissionLoader RolePermissionLoader
+	rolePermissionCache  = make(map[string]rolePermissionEntry)
+)
+
+// SetRolePermissionLoader memasang loader permission (dipanggil sekali di routes.SetupRoutes)
+func SetRolePermissionLoader(loader RolePermissionLoader) {
+	rolePermissionMu.Lock()
+	defer rolePermissionMu.Unlock()
+
+	rolePermissionLoader = loader
+	rolePermissionCache = make(map[string]rolePermissionEntry)
+}
+
+// InvalidateRolePermissions menghapus cache permission sebuah role.
+// Role kosong menghapus seluruh cache.
+func InvalidateRolePermissions(role string) {
+	rolePermissionMu.Lock()
+	defer rolePermissionMu.Unlock()
+
+	if role == "" {
+		rolePermissionCache = make(map[string]rolePermissionEntry)
+		return
+	}
+	delete(rolePermissionCache, role)
+}
+
+// LoadRolePermissions mengembalikan permission sebuah role, dicache per role selama rolePermissionCacheTTL.
+// Role yang belum ada di database memakai DefaultRolePermissions.
+func LoadRolePermissions(role string) []string {
+	if role == "" {
+		return nil
+	}
+
+	rolePermissionMu.RLock()
+	entry, cached := rolePermissionCache[role]
+	loader := rolePermissionLoader
+	rolePermissionMu.RUnlock()
+
+	if cached && time.Now().Before(entry.expiresAt) {
+		return entry.permissions
+	}
+	if loader == nil {
+		return DefaultRolePermissions[role]
+	}
+
+	permissions, found, err := loader(role)
+	if err != nil {
+		utils.GetLogger().Error("Failed to load role permissions",
+			zap.String("role", role),
+			zap.Error(err),
+		)
+		// Pakai cache lama kalau ada, selain itu tolak akses
+		if cached {
+			return entry.permissions
+		}
+		return nil
+	}
+	if !found {
+		permissions = DefaultRolePermissions[role]
+	}
+
+	rolePermissionMu.Lock()
+	rolePermissionCache[role] = rolePermissionEntry{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(rolePermissionCacheTTL),
+	}
+	rolePermissionMu.Unlock()
+
+	return permissions
+}
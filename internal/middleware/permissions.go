@@ -0,0 +1,171 @@
+package middleware
+
+import "real-erp-mebel/be/internal/models"
+
+// Daftar permission yang dipakai di routes. Format: <modul>.<aksi>
+const (
+	PermProductRead  = "product.read"
+	PermProductWrite = "product.write"
+
+	PermSupplierRead  = "supplier.read"
+	PermSupplierWrite = "supplier.write"
+
+	PermCustomerRead  = "customer.read"
+	PermCustomerWrite = "customer.write"
+
+	PermWarehouseRead  = "warehouse.read"
+	PermWarehouseWrite = "warehouse.write"
+
+	PermStockRead      = "stock.read"
+	PermStockIn        = "stock.in"
+	PermStockInApprove = "stock.in.approve"
+	PermStockOut       = "stock.out"
+	PermStockOpname    = "stock.opname"
+	PermStockTransfer  = "stock.transfer"
+	PermStockAlertAck  = "stock.alert.acknowledge"
+
+	PermSalesRead   = "sales.read"
+	PermSalesCreate = "sales.create"
+	PermSalesVoid   = "sales.void"
+
+	PermSalesOrderFulfill = "sales_order.fulfill" // tandai pesanan siap (stok keluar) & terkirim
+
+	PermSalesReturnRead     = "sales_return.read"
+	PermSalesReturnCreate   = "sales_return.create"
+	PermSalesReturnApprove  = "sales_return.approve" // approve & reject
+	PermSalesReturnComplete = "sales_return.complete"
+
+	PermPurchaseReturnRead     = "purchase_return.read"
+	PermPurchaseReturnCreate   = "purchase_return.create"
+	PermPurchaseReturnApprove  = "purchase_return.approve" // approve & reject
+	PermPurchaseReturnComplete = "purchase_return.complete"
+
+	PermQuarantineRead   = "quarantine.read"
+	PermQuarantineManage = "quarantine.manage"
+
+	PermPurchaseOrderRead    = "purchase_order.read"
+	PermPurchaseOrderWrite   = "purchase_order.write" // create, update, send, cancel
+	PermPurchaseOrderApprove = "purchase_order.approve"
+	PermPurchaseOrderReceive = "purchase_order.receive"
+
+	PermShiftOperate = "shift.operate" // buka & tutup shift sendiri
+	PermShiftManage  = "shift.manage"  // lihat & tutup shift semua kasir
+
+	PermFinanceRead    = "finance.read"
+	PermFinancePayment = "finance.payment"
+
+	PermReportRead = "report.read"
+
+	PermUserManage    = "user.manage"
+	PermRoleManage    = "role.manage"
+	PermWebhookManage = "webhook.manage"
+)
+
+// PermissionAll memberi akses ke semua permission (dipakai owner)
+const PermissionAll = "*"
+
+// PermissionCatalog adalah daftar semua permission yang di-seed ke tabel izin.
+// Tambah permission baru di sini agar bisa dipilih saat membuat role custom.
+var PermissionCatalog = []models.Izin{
+	{Kode: PermissionAll, Modul: "*", Deskripsi: "Semua akses"},
+	{Kode: PermProductRead, Modul: "product", Deskripsi: "Melihat produk"},
+	{Kode: PermProductWrite, Modul: "product", Deskripsi: "Membuat, mengubah dan menghapus produk"},
+	{Kode: PermSupplierRead, Modul: "supplier", Deskripsi: "Melihat pemasok"},
+	{Kode: PermSupplierWrite, Modul: "supplier", Deskripsi: "Membuat, mengubah dan menghapus pemasok"},
+	{Kode: PermCustomerRead, Modul: "customer", Deskripsi: "Melihat pelanggan dan riwayat pembelian"},
+	{Kode: PermCustomerWrite, Modul: "customer", Deskripsi: "Membuat, mengubah dan menghapus pelanggan"},
+	{Kode: PermWarehouseRead, Modul: "warehouse", Deskripsi: "Melihat gudang"},
+	{Kode: PermWarehouseWrite, Modul: "warehouse", Deskripsi: "Membuat, mengubah dan menghapus gudang"},
+	{Kode: PermStockRead, Modul: "stock", Deskripsi: "Melihat stok, batch, histori dan dokumen stok"},
+	{Kode: PermStockIn, Modul: "stock", Deskripsi: "Membuat barang masuk manual"},
+	{Kode: PermStockInApprove, Modul: "stock", Deskripsi: "Menyetujui / menolak barang masuk manual"},
+	{Kode: PermStockOut, Modul: "stock", Deskripsi: "Membuat barang keluar manual"},
+	{Kode: PermStockOpname, Modul: "stock", Deskripsi: "Stock opname / penyesuaian stok"},
+	{Kode: PermStockTransfer, Modul: "stock", Deskripsi: "Transfer stok antar gudang"},
+	{Kode: PermStockAlertAck, Modul: "stock", Deskripsi: "Mengonfirmasi peringatan stok menipis"},
+	{Kode: PermSalesRead, Modul: "sales", Deskripsi: "Melihat penjualan dan invoice"},
+	{Kode: PermSalesCreate, Modul: "sales", Deskripsi: "Membuat penjualan (POS)"},
+	{Kode: PermSalesVoid, Modul: "sales", Deskripsi: "Void penjualan dan membatalkan pesanan"},
+	{Kode: PermSalesOrderFulfill, Modul: "sales_order", Deskripsi: "Menyiapkan (stok keluar) dan mengirim pesanan penjualan"},
+	{Kode: PermSalesReturnRead, Modul: "sales_return", Deskripsi: "Melihat retur penjualan"},
+	{Kode: PermSalesReturnCreate, Modul: "sales_return", Deskripsi: "Membuat retur penjualan"},
+	{Kode: PermSalesReturnApprove, Modul: "sales_return", Deskripsi: "Menyetujui / menolak retur penjualan"},
+	{Kode: PermSalesReturnComplete, Modul: "sales_return", Deskripsi: "Menyelesaikan retur penjualan"},
+	{Kode: PermPurchaseReturnRead, Modul: "purchase_return", Deskripsi: "Melihat retur pembelian"},
+	{Kode: PermPurchaseReturnCreate, Modul: "purchase_return", Deskripsi: "Membuat retur pembelian"},
+	{Kode: PermPurchaseReturnApprove, Modul: "purchase_return", Deskripsi: "Menyetujui / menolak retur pembelian"},
+	{Kode: PermPurchaseReturnComplete, Modul: "purchase_return", Deskripsi: "Menyelesaikan retur pembelian"},
+	{Kode: PermQuarantineRead, Modul: "quarantine", Deskripsi: "Melihat stok karantina"},
+	{Kode: PermQuarantineManage, Modul: "quarantine", Deskripsi: "Release, write-off dan repair stok karantina"},
+	{Kode: PermPurchaseOrderRead, Modul: "purchase_order", Deskripsi: "Melihat purchase order"},
+	{Kode: PermPurchaseOrderWrite, Modul: "purchase_order", Deskripsi: "Membuat, mengubah, mengirim dan membatalkan PO"},
+	{Kode: PermPurchaseOrderApprove, Modul: "purchase_order", Deskripsi: "Menyetujui PO"},
+	{Kode: PermPurchaseOrderReceive, Modul: "purchase_order", Deskripsi: "Menerima barang dari PO"},
+	{Kode: PermShiftOperate, Modul: "shift", Deskripsi: "Membuka dan menutup shift kasir sendiri"},
+	{Kode: PermShiftManage, Modul: "shift", Deskripsi: "Melihat dan menutup shift semua kasir"},
+	{Kode: PermFinanceRead, Modul: "finance", Deskripsi: "Melihat hutang pemasok, piutang pelanggan dan aging"},
+	{Kode: PermFinancePayment, Modul: "finance", Deskripsi: "Mencatat pembayaran hutang dan piutang"},
+	{Kode: PermReportRead, Modul: "report", Deskripsi: "Melihat laporan"},
+	{Kode: PermUserManage, Modul: "user", Deskripsi: "Mengelola pengguna"},
+	{Kode: PermRoleManage, Modul: "role", Deskripsi: "Mengelola role dan permission"},
+	{Kode: PermWebhookManage, Modul: "webhook", Deskripsi: "Mengelola webhook dan melihat log pengiriman"},
+}
+
+// DefaultRolePermissions adalah matriks permission untuk role bawaan.
+// Di-seed ke tabel peran/peran_izin saat migrate, dan dipakai sebagai fallback
+// kalau role belum ada di database.
+var DefaultRolePermissions = map[string][]string{
+	"owner": {PermissionAll},
+	"admin_gudang": {
+		PermProductRead, PermProductWrite,
+		PermSupplierRead, PermSupplierWrite,
+		PermCustomerRead,
+		PermWarehouseRead, PermWarehouseWrite,
+		PermStockRead, PermStockIn, PermStockOut, PermStockOpname, PermStockTransfer, PermStockAlertAck,
+		PermSalesRead, PermSalesOrderFulfill,
+		PermSalesReturnRead, PermSalesReturnApprove,
+		PermPurchaseReturnRead, PermPurchaseReturnCreate, PermPurchaseReturnApprove,
+		PermQuarantineRead, PermQuarantineManage,
+		PermPurchaseOrderRead, PermPurchaseOrderWrite, PermPurchaseOrderReceive,
+		PermReportRead,
+		PermUserManage,
+	},
+	"kasir": {
+		PermProductRead,
+		PermCustomerRead, PermCustomerWrite,
+		PermWarehouseRead,
+		PermStockRead,
+		PermSalesRead, PermSalesCreate,
+		PermSalesReturnRead, PermSalesReturnCreate,
+		PermShiftOperate,
+	},
+	"finance": {
+		PermProductRead,
+		PermSupplierRead,
+		PermCustomerRead,
+		PermWarehouseRead,
+		PermStockRead,
+		PermSalesRead, PermSalesVoid,
+		PermSalesReturnRead, PermSalesReturnComplete,
+		PermPurchaseReturnRead, PermPurchaseReturnComplete,
+		PermPurchaseOrderRead,
+		PermShiftOperate, PermShiftManage, // refund tunai retur dibayar dari shift
+		PermFinanceRead, PermFinancePayment,
+		PermReportRead,
+	},
+}
+
+// RoleHasPermission mengecek apakah role memiliki permission (dari database via cache)
+func RoleHasPermission(role, permission string) bool {
+	return containsPermission(LoadRolePermissions(role), permission)
+}
+
+// containsPermission mengecek permission pada daftar permission sebuah role
+func containsPermission(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == PermissionAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
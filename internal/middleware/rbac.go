@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"real-erp-mebel/be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequirePermission adalah middleware RBAC berbasis matriks permission.
+// Wajib dipasang setelah AuthMiddleware (butuh "role" di context).
+func RequirePermission(permission string) gin.HandlerFunc {
+	logger := utils.GetLogger()
+
+	return func(c *gin.Context) {
+		role := utils.GetUserRole(c)
+		if !RoleHasPermission(role, permission) {
+			logger.Warn("Permission denied",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("role", role),
+				zap.String("permission", permission),
+			)
+			utils.Forbidden(c, "You don't have permission to access this resource")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRoles membatasi akses ke role tertentu saja (untuk kasus yang tidak cocok dengan matriks)
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := utils.GetUserRole(c)
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+		utils.Forbidden(c, "You don't have permission to access this resource")
+		c.Abort()
+	}
+}
+
+// HasPermission mengecek permission user saat ini di dalam handler
+// (untuk aturan kondisional, misalnya user boleh melihat profilnya sendiri).
+func HasPermission(c *gin.Context, permission string) bool {
+	return RoleHasPermission(utils.GetUserRole(c), permission)
+}
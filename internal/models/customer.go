@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pelanggan adalah model untuk master data pelanggan.
+// Penjualan dan retur penjualan tetap menyimpan snapshot nama & kontak saat transaksi.
+type Pelanggan struct {
+	ID             uint           `gorm:"primaryKey;column:id" json:"id"`
+	Nama           string         `gorm:"not null;index;column:nama" json:"nama"`
+	Telepon        string         `gorm:"type:varchar(50);index;column:telepon" json:"telepon"`
+	Email          string         `gorm:"type:varchar(100);column:email" json:"email"`
+	Alamat         string         `gorm:"type:text;column:alamat" json:"alamat"`
+	Catatan        string         `gorm:"type:text;column:catatan" json:"catatan"`
+	Aktif          bool           `gorm:"default:true;column:aktif" json:"aktif"`
+	DibuatPada     time.Time      `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+	DiperbaruiPada time.Time      `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+	DihapusPada    gorm.DeletedAt `gorm:"index;column:dihapus_pada" json:"-"`
+}
+
+// TableName mengembalikan nama tabel untuk model Pelanggan
+func (Pelanggan) TableName() string {
+	return "pelanggan"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AntreanEvent adalah model outbox untuk event domain. Baris ditulis di dalam transaksi
+// yang sama dengan perubahan datanya, lalu dikirim dispatcher ke WebSocket hub / webhook.
+// ID dipakai client WebSocket sebagai posisi replay (since_id).
+type AntreanEvent struct {
+	ID                  uint       `json:"id" gorm:"primaryKey;column:id"`
+	TipeEvent           string     `json:"tipe_event" gorm:"type:varchar(50);not null;index;column:tipe_event"`
+	Topik               string     `json:"topik" gorm:"type:text;column:topik"` // dipisah koma, kosong = semua client
+	Payload             string     `json:"payload" gorm:"type:jsonb;not null;column:payload"`
+	Status              string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index:idx_antrean_event_status;column:status"` // pending, dispatched, failed
+	Percobaan           int        `json:"percobaan" gorm:"not null;default:0;column:percobaan"`
+	PercobaanBerikutnya time.Time  `json:"percobaan_berikutnya" gorm:"not null;index:idx_antrean_event_status;column:percobaan_berikutnya"`
+	ErrorTerakhir       string     `json:"error_terakhir" gorm:"type:text;column:error_terakhir"`
+	TerkirimPada        *time.Time `json:"terkirim_pada" gorm:"column:terkirim_pada"`
+	DibuatPada          time.Time  `json:"dibuat_pada" gorm:"not null;index;column:dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model AntreanEvent
+func (AntreanEvent) TableName() string {
+	return "antrean_event"
+}
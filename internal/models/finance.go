@@ -22,6 +22,9 @@ type HutangPemasok struct {
 	DibayarOlehPengguna *Pengguna  `gorm:"foreignKey:DibayarOleh" json:"dibayar_oleh_pengguna,omitempty"`
 	DibuatPada          time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
 	DiperbaruiPada      time.Time  `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+
+	// Relationship
+	Pembayaran []PembayaranHutang `gorm:"foreignKey:IDHutang;constraint:OnDelete:CASCADE" json:"pembayaran,omitempty"`
 }
 
 // TableName mengembalikan nama tabel untuk model HutangPemasok
@@ -31,3 +34,23 @@ func (HutangPemasok) TableName() string {
 
 // SupplierDebt adalah alias untuk backward compatibility (akan dihapus nanti)
 type SupplierDebt = HutangPemasok
+
+// PembayaranHutang adalah model untuk riwayat pembayaran hutang supplier (cicilan / pelunasan)
+type PembayaranHutang struct {
+	ID                  uint          `gorm:"primaryKey;column:id" json:"id"`
+	IDHutang            uint          `gorm:"index;not null;column:id_hutang" json:"id_hutang"`
+	HutangPemasok       HutangPemasok `gorm:"foreignKey:IDHutang" json:"hutang_pemasok,omitempty"`
+	Jumlah              float64       `gorm:"type:decimal(15,2);not null;column:jumlah" json:"jumlah"`
+	MetodePembayaran    string        `gorm:"type:varchar(20);not null;column:metode_pembayaran" json:"metode_pembayaran"` // cash, transfer
+	TanggalBayar        time.Time     `gorm:"not null;column:tanggal_bayar" json:"tanggal_bayar"`
+	BuktiPembayaran     string        `gorm:"type:text;column:bukti_pembayaran" json:"bukti_pembayaran"` // Path ke file bukti bayar
+	Keterangan          string        `gorm:"type:text;column:keterangan" json:"keterangan"`
+	DibayarOleh         uint          `gorm:"index;not null;column:dibayar_oleh" json:"dibayar_oleh"`
+	DibayarOlehPengguna Pengguna      `gorm:"foreignKey:DibayarOleh" json:"dibayar_oleh_pengguna,omitempty"`
+	DibuatPada          time.Time     `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model PembayaranHutang
+func (PembayaranHutang) TableName() string {
+	return "pembayaran_hutang"
+}
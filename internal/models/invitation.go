@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UndanganPengguna adalah model untuk undangan staff (pengganti self-registration)
+// Token mentah hanya dikirim sekali ke pengundang; yang disimpan hanya hash SHA-256.
+// Status (pending, accepted, revoked, expired) diturunkan dari kolom waktu.
+type UndanganPengguna struct {
+	ID              uint       `json:"id" gorm:"primaryKey;column:id"`
+	Email           string     `json:"email" gorm:"type:varchar(255);index;not null;column:email"`
+	Peran           string     `json:"peran" gorm:"type:varchar(50);not null;column:peran"`
+	TokenHash       string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null;column:token_hash"`
+	KedaluwarsaPada time.Time  `json:"kedaluwarsa_pada" gorm:"not null;column:kedaluwarsa_pada"`
+	DipakaiPada     *time.Time `json:"dipakai_pada" gorm:"column:dipakai_pada"`
+	DibatalkanPada  *time.Time `json:"dibatalkan_pada" gorm:"column:dibatalkan_pada"`
+	IDPengguna      *uint      `json:"id_pengguna" gorm:"column:id_pengguna"` // User yang dibuat dari undangan ini
+	DibuatOleh      uint       `json:"dibuat_oleh" gorm:"not null;column:dibuat_oleh"`
+	Pembuat         Pengguna   `json:"pembuat,omitempty" gorm:"foreignKey:DibuatOleh"`
+	DibuatPada      time.Time  `json:"dibuat_pada" gorm:"column:dibuat_pada"`
+	DiperbaruiPada  time.Time  `json:"diperbarui_pada" gorm:"column:diperbarui_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model UndanganPengguna
+func (UndanganPengguna) TableName() string {
+	return "undangan_pengguna"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TokenRefresh adalah model untuk refresh token (disimpan sebagai hash SHA-256).
+// Setiap refresh merotasi token: token lama dicabut dan DigantiOleh menunjuk token baru,
+// sehingga pemakaian ulang token lama bisa dideteksi.
+type TokenRefresh struct {
+	ID              uint       `json:"id" gorm:"primaryKey;column:id"`
+	IDPengguna      uint       `json:"id_pengguna" gorm:"not null;index;column:id_pengguna"`
+	Pengguna        Pengguna   `json:"pengguna,omitempty" gorm:"foreignKey:IDPengguna"`
+	TokenHash       string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null;column:token_hash"`
+	KedaluwarsaPada time.Time  `json:"kedaluwarsa_pada" gorm:"not null;column:kedaluwarsa_pada"`
+	DicabutPada     *time.Time `json:"dicabut_pada" gorm:"column:dicabut_pada"`
+	DigantiOleh     *uint      `json:"diganti_oleh" gorm:"column:diganti_oleh"` // ID token hasil rotasi
+	UserAgent       string     `json:"user_agent" gorm:"type:varchar(255);column:user_agent"`
+	AlamatIP        string     `json:"alamat_ip" gorm:"type:varchar(64);column:alamat_ip"`
+	DibuatPada      time.Time  `json:"dibuat_pada" gorm:"column:dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model TokenRefresh
+func (TokenRefresh) TableName() string {
+	return "token_refresh"
+}
@@ -10,6 +10,8 @@ type ReturPenjualan struct {
 	NomorRetur            string     `gorm:"uniqueIndex;not null;column:nomor_retur" json:"nomor_retur"`
 	IDPenjualan           uint       `gorm:"index;not null;column:id_penjualan" json:"id_penjualan"`
 	Penjualan             Penjualan  `gorm:"foreignKey:IDPenjualan" json:"penjualan,omitempty"`
+	IDPelanggan           *uint      `gorm:"index;column:id_pelanggan" json:"id_pelanggan"` // Ikut penjualan asal
+	Pelanggan             *Pelanggan `gorm:"foreignKey:IDPelanggan" json:"pelanggan,omitempty"`
 	NamaPelanggan         string     `gorm:"type:varchar(100);column:nama_pelanggan" json:"nama_pelanggan"`
 	KontakPelanggan       string     `gorm:"type:varchar(50);column:kontak_pelanggan" json:"kontak_pelanggan"`
 	Alasan                string     `gorm:"type:varchar(100);not null;column:alasan" json:"alasan"` // rusak, tidak sesuai, cacat, dll
@@ -24,6 +26,13 @@ type ReturPenjualan struct {
 	DisetujuiOleh         *uint      `gorm:"index;column:disetujui_oleh" json:"disetujui_oleh"`
 	DisetujuiOlehPengguna *Pengguna  `gorm:"foreignKey:DisetujuiOleh" json:"disetujui_oleh_pengguna,omitempty"`
 	DisetujuiPada         *time.Time `gorm:"column:disetujui_pada" json:"disetujui_pada"`
+	AlasanPenolakan       string     `gorm:"type:text;column:alasan_penolakan" json:"alasan_penolakan"`
+	DitolakOleh           *uint      `gorm:"index;column:ditolak_oleh" json:"ditolak_oleh"`
+	DitolakOlehPengguna   *Pengguna  `gorm:"foreignKey:DitolakOleh" json:"ditolak_oleh_pengguna,omitempty"`
+	DitolakPada           *time.Time `gorm:"column:ditolak_pada" json:"ditolak_pada"`
+	DiselesaikanOleh      *uint      `gorm:"index;column:diselesaikan_oleh" json:"diselesaikan_oleh"` // Refund / tukar barang sudah beres
+	DiselesaikanPada      *time.Time `gorm:"column:diselesaikan_pada" json:"diselesaikan_pada"`
+	IDShift               *uint      `gorm:"index;column:id_shift" json:"id_shift"` // Shift kasir yang membayar refund tunai
 	DiprosesPada          time.Time  `gorm:"column:diproses_pada" json:"diproses_pada"`
 	DibuatPada            time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
 	DiperbaruiPada        time.Time  `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
@@ -87,6 +96,12 @@ type ReturPembelian struct {
 	DisetujuiOleh         *uint             `gorm:"index;column:disetujui_oleh" json:"disetujui_oleh"`
 	DisetujuiOlehPengguna *Pengguna         `gorm:"foreignKey:DisetujuiOleh" json:"disetujui_oleh_pengguna,omitempty"`
 	DisetujuiPada         *time.Time        `gorm:"column:disetujui_pada" json:"disetujui_pada"`
+	AlasanPenolakan       string            `gorm:"type:text;column:alasan_penolakan" json:"alasan_penolakan"`
+	DitolakOleh           *uint             `gorm:"index;column:ditolak_oleh" json:"ditolak_oleh"`
+	DitolakOlehPengguna   *Pengguna         `gorm:"foreignKey:DitolakOleh" json:"ditolak_oleh_pengguna,omitempty"`
+	DitolakPada           *time.Time        `gorm:"column:ditolak_pada" json:"ditolak_pada"`
+	DiselesaikanOleh      *uint             `gorm:"index;column:diselesaikan_oleh" json:"diselesaikan_oleh"` // Refund / potong hutang / tukar barang sudah beres
+	DiselesaikanPada      *time.Time        `gorm:"column:diselesaikan_pada" json:"diselesaikan_pada"`
 	DibuatPada            time.Time         `gorm:"column:dibuat_pada" json:"dibuat_pada"`
 	DiperbaruiPada        time.Time         `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
 
@@ -127,3 +142,29 @@ func (ItemReturPembelian) TableName() string {
 
 // PurchaseReturnItem adalah alias untuk backward compatibility (akan dihapus nanti)
 type PurchaseReturnItem = ItemReturPembelian
+
+// DisposisiKarantina adalah audit trail tindakan atas stok karantina retur penjualan.
+// Aksi: release (kembali ke stok jual), write_off (dihapus karena rusak), repair (dikirim perbaikan).
+type DisposisiKarantina struct {
+	ID                   uint       `gorm:"primaryKey;column:id" json:"id"`
+	IDBatch              uint       `gorm:"index;not null;column:id_batch" json:"id_batch"` // Batch karantina asal
+	Batch                StokBatch  `gorm:"foreignKey:IDBatch" json:"batch,omitempty"`
+	IDBatchHasil         *uint      `gorm:"index;column:id_batch_hasil" json:"id_batch_hasil"` // Batch hasil release/repair (null untuk write_off)
+	BatchHasil           *StokBatch `gorm:"foreignKey:IDBatchHasil" json:"batch_hasil,omitempty"`
+	IDProduk             uint       `gorm:"index;not null;column:id_produk" json:"id_produk"`
+	Produk               Produk     `gorm:"foreignKey:IDProduk" json:"produk,omitempty"`
+	IDGudang             uint       `gorm:"index;not null;column:id_gudang" json:"id_gudang"`
+	Gudang               Gudang     `gorm:"foreignKey:IDGudang" json:"gudang,omitempty"`
+	Aksi                 string     `gorm:"type:varchar(20);not null;column:aksi" json:"aksi"` // release, write_off, repair
+	Jumlah               int        `gorm:"not null;column:jumlah" json:"jumlah"`
+	NilaiModal           float64    `gorm:"type:decimal(15,2);not null;column:nilai_modal" json:"nilai_modal"` // HargaModal batch × Jumlah
+	Keterangan           string     `gorm:"type:text;column:keterangan" json:"keterangan"`
+	DiprosesOleh         uint       `gorm:"index;not null;column:diproses_oleh" json:"diproses_oleh"`
+	DiprosesOlehPengguna Pengguna   `gorm:"foreignKey:DiprosesOleh" json:"diproses_oleh_pengguna,omitempty"`
+	DibuatPada           time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model DisposisiKarantina
+func (DisposisiKarantina) TableName() string {
+	return "disposisi_karantina"
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// PeranPengguna adalah model untuk role yang bisa dikonfigurasi (owner, kasir, supervisor_toko, dll)
+// Pengguna.Peran menyimpan Kode role ini.
+type PeranPengguna struct {
+	ID             uint        `json:"id" gorm:"primaryKey;column:id"`
+	Kode           string      `json:"kode" gorm:"type:varchar(50);uniqueIndex;not null;column:kode"`
+	Nama           string      `json:"nama" gorm:"type:varchar(100);not null;column:nama"`
+	Deskripsi      string      `json:"deskripsi" gorm:"type:text;column:deskripsi"`
+	Sistem         bool        `json:"sistem" gorm:"default:false;column:sistem"` // Role bawaan, tidak bisa dihapus
+	Aktif          bool        `json:"aktif" gorm:"default:true;column:aktif"`
+	Izin           []PeranIzin `json:"izin,omitempty" gorm:"foreignKey:IDPeran"`
+	DibuatPada     time.Time   `json:"dibuat_pada" gorm:"column:dibuat_pada"`
+	DiperbaruiPada time.Time   `json:"diperbarui_pada" gorm:"column:diperbarui_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model PeranPengguna
+func (PeranPengguna) TableName() string {
+	return "peran"
+}
+
+// Izin adalah model untuk katalog permission (format kode: <modul>.<aksi>)
+type Izin struct {
+	ID         uint      `json:"id" gorm:"primaryKey;column:id"`
+	Kode       string    `json:"kode" gorm:"type:varchar(100);uniqueIndex;not null;column:kode"`
+	Modul      string    `json:"modul" gorm:"type:varchar(50);index;column:modul"`
+	Deskripsi  string    `json:"deskripsi" gorm:"type:text;column:deskripsi"`
+	DibuatPada time.Time `json:"dibuat_pada" gorm:"column:dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model Izin
+func (Izin) TableName() string {
+	return "izin"
+}
+
+// PeranIzin adalah tabel relasi role ↔ permission
+type PeranIzin struct {
+	ID      uint `json:"id" gorm:"primaryKey;column:id"`
+	IDPeran uint `json:"id_peran" gorm:"not null;uniqueIndex:idx_peran_izin;column:id_peran"`
+	IDIzin  uint `json:"id_izin" gorm:"not null;uniqueIndex:idx_peran_izin;column:id_izin"`
+	Izin    Izin `json:"izin,omitempty" gorm:"foreignKey:IDIzin"`
+}
+
+// TableName mengembalikan nama tabel untuk model PeranIzin
+func (PeranIzin) TableName() string {
+	return "peran_izin"
+}
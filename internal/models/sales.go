@@ -4,31 +4,53 @@ import (
 	"time"
 )
 
-// Penjualan adalah model untuk transaksi penjualan (Mode 1: POS langsung bayar)
+// Penjualan adalah model untuk transaksi penjualan.
+// Mode 1 (TipePenjualan = pos): langsung bayar, stok langsung keluar.
+// Mode 2 (TipePenjualan = pesanan): pesanan dengan DP, stok keluar saat barang disiapkan, pelunasan menyusul.
 type Penjualan struct {
-	ID               uint      `gorm:"primaryKey;column:id" json:"id"`
-	NomorTransaksi   string    `gorm:"uniqueIndex;not null;column:nomor_transaksi" json:"nomor_transaksi"`
-	IDGudang         uint      `gorm:"index;not null;column:id_gudang" json:"id_gudang"`
-	Gudang           Gudang    `gorm:"foreignKey:IDGudang" json:"gudang,omitempty"`
-	NamaPelanggan    string    `gorm:"type:varchar(100);column:nama_pelanggan" json:"nama_pelanggan"`
-	KontakPelanggan  string    `gorm:"type:varchar(50);column:kontak_pelanggan" json:"kontak_pelanggan"`
-	Subtotal         float64   `gorm:"type:decimal(15,2);not null;column:subtotal" json:"subtotal"`                             // Total sebelum diskon
-	JumlahDiskon     float64   `gorm:"type:decimal(15,2);default:0;column:jumlah_diskon" json:"jumlah_diskon"`                  // Total diskon
-	Total            float64   `gorm:"type:decimal(15,2);not null;column:total" json:"total"`                                   // Total setelah diskon (harga jual)
-	TotalHargaModal  float64   `gorm:"type:decimal(15,2);not null;default:0;column:total_harga_modal" json:"total_harga_modal"` // Total COGS (dari FIFO batch)
-	MetodePembayaran string    `gorm:"type:varchar(20);not null;column:metode_pembayaran" json:"metode_pembayaran"`             // cash, transfer
-	JumlahPembayaran float64   `gorm:"type:decimal(15,2);not null;column:jumlah_pembayaran" json:"jumlah_pembayaran"`           // Jumlah yang dibayar
-	JumlahKembalian  float64   `gorm:"type:decimal(15,2);default:0;column:jumlah_kembalian" json:"jumlah_kembalian"`            // Kembalian (untuk cash)
-	BuktiBayar       *string   `gorm:"type:text;column:bukti_bayar" json:"bukti_bayar,omitempty"`                               // Path foto bukti transfer (nullable)
-	Status           string    `gorm:"type:varchar(20);default:'completed';column:status" json:"status"`                        // completed, voided
-	CatatanInternal  string    `gorm:"type:text;column:catatan_internal" json:"catatan_internal,omitempty"`                     // Catatan opsional kasir
-	IDKasir          uint      `gorm:"index;not null;column:id_kasir" json:"id_kasir"`
-	Kasir            Pengguna  `gorm:"foreignKey:IDKasir" json:"kasir,omitempty"`
-	DibuatPada       time.Time `gorm:"column:dibuat_pada" json:"dibuat_pada"`
-	DiperbaruiPada   time.Time `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+	ID               uint       `gorm:"primaryKey;column:id" json:"id"`
+	NomorTransaksi   string     `gorm:"uniqueIndex;not null;column:nomor_transaksi" json:"nomor_transaksi"`
+	TipePenjualan    string     `gorm:"type:varchar(20);default:'pos';index;column:tipe_penjualan" json:"tipe_penjualan"` // pos, pesanan
+	IDGudang         uint       `gorm:"index;not null;column:id_gudang" json:"id_gudang"`
+	Gudang           Gudang     `gorm:"foreignKey:IDGudang" json:"gudang,omitempty"`
+	IDPelanggan      *uint      `gorm:"index;column:id_pelanggan" json:"id_pelanggan"` // Opsional, link ke master pelanggan
+	Pelanggan        *Pelanggan `gorm:"foreignKey:IDPelanggan" json:"pelanggan,omitempty"`
+	NamaPelanggan    string     `gorm:"type:varchar(100);column:nama_pelanggan" json:"nama_pelanggan"`
+	KontakPelanggan  string     `gorm:"type:varchar(50);column:kontak_pelanggan" json:"kontak_pelanggan"`
+	Subtotal         float64    `gorm:"type:decimal(15,2);not null;column:subtotal" json:"subtotal"`                             // Total sebelum diskon
+	JumlahDiskon     float64    `gorm:"type:decimal(15,2);default:0;column:jumlah_diskon" json:"jumlah_diskon"`                  // Total diskon
+	Total            float64    `gorm:"type:decimal(15,2);not null;column:total" json:"total"`                                   // Total setelah diskon (harga jual)
+	TotalHargaModal  float64    `gorm:"type:decimal(15,2);not null;default:0;column:total_harga_modal" json:"total_harga_modal"` // Total COGS (dari FIFO batch)
+	MetodePembayaran string     `gorm:"type:varchar(20);not null;column:metode_pembayaran" json:"metode_pembayaran"`             // cash, transfer, qris, debit, split (lebih dari satu metode)
+	JumlahPembayaran float64    `gorm:"type:decimal(15,2);not null;column:jumlah_pembayaran" json:"jumlah_pembayaran"`           // Jumlah yang diterima (termasuk uang tunai sebelum kembalian)
+	JumlahKembalian  float64    `gorm:"type:decimal(15,2);default:0;column:jumlah_kembalian" json:"jumlah_kembalian"`            // Kembalian (hanya dari porsi cash)
+	BuktiBayar       *string    `gorm:"type:text;column:bukti_bayar" json:"bukti_bayar,omitempty"`                               // Path foto bukti transfer (nullable)
+	TotalDibayar     float64    `gorm:"type:decimal(15,2);default:0;column:total_dibayar" json:"total_dibayar"`                  // Pesanan: DP + pembayaran berikutnya
+	Status           string     `gorm:"type:varchar(20);default:'completed';column:status" json:"status"`                        // POS: completed, voided. Pesanan: ordered, ready, delivered, paid, cancelled
+	CatatanInternal  string     `gorm:"type:text;column:catatan_internal" json:"catatan_internal,omitempty"`                     // Catatan opsional kasir
+	IDKasir          uint       `gorm:"index;not null;column:id_kasir" json:"id_kasir"`
+	IDShift          *uint      `gorm:"index;column:id_shift" json:"id_shift"` // Shift kasir saat transaksi dibuat
+	Kasir            Pengguna   `gorm:"foreignKey:IDKasir" json:"kasir,omitempty"`
+	DibuatPada       time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+	DiperbaruiPada   time.Time  `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+
+	// Pesanan (Mode 2)
+	AlamatPengiriman  string     `gorm:"type:text;column:alamat_pengiriman" json:"alamat_pengiriman,omitempty"`
+	TanggalPengiriman *time.Time `gorm:"column:tanggal_pengiriman" json:"tanggal_pengiriman,omitempty"` // Rencana kirim
+	DisiapkanPada     *time.Time `gorm:"column:disiapkan_pada" json:"disiapkan_pada,omitempty"`         // Stok keluar (FIFO)
+	DikirimPada       *time.Time `gorm:"column:dikirim_pada" json:"dikirim_pada,omitempty"`
+	LunasPada         *time.Time `gorm:"column:lunas_pada" json:"lunas_pada,omitempty"`
+	JatuhTempo        *time.Time `gorm:"index;column:jatuh_tempo" json:"jatuh_tempo,omitempty"` // Batas pelunasan piutang
+
+	// Pembatalan (void / pesanan dibatalkan) — diisi saat Status = voided / cancelled
+	AlasanPembatalan       string     `gorm:"type:text;column:alasan_pembatalan" json:"alasan_pembatalan,omitempty"`
+	DibatalkanOleh         *uint      `gorm:"index;column:dibatalkan_oleh" json:"dibatalkan_oleh,omitempty"`
+	DibatalkanOlehPengguna *Pengguna  `gorm:"foreignKey:DibatalkanOleh" json:"dibatalkan_oleh_pengguna,omitempty"`
+	DibatalkanPada         *time.Time `gorm:"column:dibatalkan_pada" json:"dibatalkan_pada,omitempty"`
 
 	// Relationship
-	Items []ItemPenjualan `gorm:"foreignKey:IDPenjualan;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	Items      []ItemPenjualan       `gorm:"foreignKey:IDPenjualan;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	Pembayaran []PembayaranPenjualan `gorm:"foreignKey:IDPenjualan;constraint:OnDelete:CASCADE" json:"pembayaran,omitempty"`
 }
 
 // TableName mengembalikan nama tabel untuk model Penjualan
@@ -39,6 +61,42 @@ func (Penjualan) TableName() string {
 // Sales adalah alias untuk backward compatibility
 type Sales = Penjualan
 
+// Status penjualan yang diakui sebagai omzet: POS selesai dan pesanan yang sudah dikirim
+var StatusPenjualanTerjual = []string{"completed", "delivered", "paid"}
+
+// PenjualanTerjual mengecek apakah status penjualan diakui sebagai omzet (bisa diretur, masuk laporan)
+func PenjualanTerjual(status string) bool {
+	for _, s := range StatusPenjualanTerjual {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// PembayaranPenjualan adalah model untuk baris pembayaran penjualan: tender POS (satu transaksi
+// bisa dibayar dengan beberapa metode) maupun DP / cicilan / pelunasan pesanan.
+// Jumlah baris cash POS sudah dikurangi kembalian, sehingga Σ jumlah = total yang diterima toko.
+type PembayaranPenjualan struct {
+	ID                   uint      `gorm:"primaryKey;column:id" json:"id"`
+	IDPenjualan          uint      `gorm:"index;not null;column:id_penjualan" json:"id_penjualan"`
+	Jenis                string    `gorm:"type:varchar(20);not null;column:jenis" json:"jenis"`                               // pos, dp, pembayaran
+	MetodePembayaran     string    `gorm:"type:varchar(20);not null;index;column:metode_pembayaran" json:"metode_pembayaran"` // cash, transfer, qris, debit
+	NomorReferensi       string    `gorm:"type:varchar(100);column:nomor_referensi" json:"nomor_referensi,omitempty"`         // No. referensi transfer / QRIS / approval code EDC
+	Jumlah               float64   `gorm:"type:decimal(15,2);not null;column:jumlah" json:"jumlah"`
+	Keterangan           string    `gorm:"type:text;column:keterangan" json:"keterangan"`
+	BuktiPembayaran      string    `gorm:"type:text;column:bukti_pembayaran" json:"bukti_pembayaran,omitempty"` // Path ke file bukti bayar
+	DiterimaOleh         uint      `gorm:"index;not null;column:diterima_oleh" json:"diterima_oleh"`
+	IDShift              *uint     `gorm:"index;column:id_shift" json:"id_shift"` // Shift kasir penerima (nil jika diterima di luar shift, mis. transfer ke finance)
+	DiterimaOlehPengguna Pengguna  `gorm:"foreignKey:DiterimaOleh" json:"diterima_oleh_pengguna,omitempty"`
+	DibuatPada           time.Time `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model PembayaranPenjualan
+func (PembayaranPenjualan) TableName() string {
+	return "pembayaran_penjualan"
+}
+
 // ItemPenjualan adalah model untuk detail item penjualan
 // Setiap item mencatat harga modal (COGS) rata-rata tertimbang dari batch FIFO yang terpakai.
 type ItemPenjualan struct {
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// ShiftKasir adalah model untuk sesi shift kasir (buka dengan kas awal, tutup dengan kas yang dihitung).
+// Penjualan POS, pembayaran yang diterima kasir, dan refund tunai retur terikat ke shift yang sedang buka.
+type ShiftKasir struct {
+	ID            uint     `gorm:"primaryKey;column:id" json:"id"`
+	NomorShift    string   `gorm:"uniqueIndex;not null;column:nomor_shift" json:"nomor_shift"`
+	IDKasir       uint     `gorm:"index;uniqueIndex:idx_shift_kasir_open,where:status = 'open';not null;column:id_kasir" json:"id_kasir"` // Satu shift open per kasir
+	Kasir         Pengguna `gorm:"foreignKey:IDKasir" json:"kasir,omitempty"`
+	IDGudang      uint     `gorm:"index;not null;column:id_gudang" json:"id_gudang"`
+	Gudang        Gudang   `gorm:"foreignKey:IDGudang" json:"gudang,omitempty"`
+	Status        string   `gorm:"type:varchar(20);default:'open';index;column:status" json:"status"`        // open, closed
+	KasAwal       float64  `gorm:"type:decimal(15,2);not null;default:0;column:kas_awal" json:"kas_awal"`    // Modal uang kembalian di laci
+	KasDiharapkan float64  `gorm:"type:decimal(15,2);default:0;column:kas_diharapkan" json:"kas_diharapkan"` // Kas awal + cash masuk - refund tunai (dihitung saat tutup)
+	KasDihitung   *float64 `gorm:"type:decimal(15,2);column:kas_dihitung" json:"kas_dihitung"`               // Uang fisik di laci saat tutup
+	SelisihKas    float64  `gorm:"type:decimal(15,2);default:0;column:selisih_kas" json:"selisih_kas"`       // Dihitung - diharapkan (minus = kurang)
+	CatatanBuka   string   `gorm:"type:text;column:catatan_buka" json:"catatan_buka,omitempty"`
+
+	// Ringkasan transaksi dibekukan saat tutup shift agar Z report tidak berubah oleh void / retur susulan
+	JumlahTransaksi int64   `gorm:"not null;default:0;column:jumlah_transaksi" json:"jumlah_transaksi"`
+	TotalPenjualan  float64 `gorm:"type:decimal(15,2);not null;default:0;column:total_penjualan" json:"total_penjualan"`
+	TotalDiskon     float64 `gorm:"type:decimal(15,2);not null;default:0;column:total_diskon" json:"total_diskon"`
+	JumlahVoid      int64   `gorm:"not null;default:0;column:jumlah_void" json:"jumlah_void"`
+	NilaiVoid       float64 `gorm:"type:decimal(15,2);not null;default:0;column:nilai_void" json:"nilai_void"`
+	JumlahRefund    int64   `gorm:"not null;default:0;column:jumlah_refund" json:"jumlah_refund"`
+	RefundTunai     float64 `gorm:"type:decimal(15,2);not null;default:0;column:refund_tunai" json:"refund_tunai"`
+
+	CatatanTutup    string     `gorm:"type:text;column:catatan_tutup" json:"catatan_tutup,omitempty"`
+	DibukaPada      time.Time  `gorm:"column:dibuka_pada" json:"dibuka_pada"`
+	DitutupPada     *time.Time `gorm:"column:ditutup_pada" json:"ditutup_pada"`
+	DitutupOleh     *uint      `gorm:"index;column:ditutup_oleh" json:"ditutup_oleh"`
+	DitutupOlehUser *Pengguna  `gorm:"foreignKey:DitutupOleh" json:"ditutup_oleh_pengguna,omitempty"`
+	DibuatPada      time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+	DiperbaruiPada  time.Time  `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+
+	// Relationship
+	Rekap []RekapShiftKasir `gorm:"foreignKey:IDShift;constraint:OnDelete:CASCADE" json:"rekap,omitempty"`
+}
+
+// TableName mengembalikan nama tabel untuk model ShiftKasir
+func (ShiftKasir) TableName() string {
+	return "shift_kasir"
+}
+
+// RekapShiftKasir adalah rekonsiliasi per metode pembayaran saat shift ditutup
+type RekapShiftKasir struct {
+	ID               uint     `gorm:"primaryKey;column:id" json:"id"`
+	IDShift          uint     `gorm:"index;not null;column:id_shift" json:"id_shift"`
+	MetodePembayaran string   `gorm:"type:varchar(20);not null;column:metode_pembayaran" json:"metode_pembayaran"` // cash, transfer, qris, debit
+	JumlahTransaksi  int64    `gorm:"not null;default:0;column:jumlah_transaksi" json:"jumlah_transaksi"`
+	Diharapkan       float64  `gorm:"type:decimal(15,2);not null;default:0;column:diharapkan" json:"diharapkan"` // Cash: termasuk kas awal, dikurangi refund tunai
+	Dihitung         *float64 `gorm:"type:decimal(15,2);column:dihitung" json:"dihitung"`                        // nil jika tidak dihitung (mis. settlement EDC belum ada)
+	Selisih          float64  `gorm:"type:decimal(15,2);default:0;column:selisih" json:"selisih"`
+}
+
+// TableName mengembalikan nama tabel untuk model RekapShiftKasir
+func (RekapShiftKasir) TableName() string {
+	return "rekap_shift_kasir"
+}
@@ -17,6 +17,10 @@ type BarangMasuk struct {
 	DisetujuiOleh         *uint      `gorm:"index;column:disetujui_oleh" json:"disetujui_oleh"`
 	DisetujuiOlehPengguna *Pengguna  `gorm:"foreignKey:DisetujuiOleh" json:"disetujui_oleh_pengguna,omitempty"`
 	DisetujuiPada         *time.Time `gorm:"column:disetujui_pada" json:"disetujui_pada"`
+	AlasanPenolakan       string     `gorm:"type:text;column:alasan_penolakan" json:"alasan_penolakan"`
+	DitolakOleh           *uint      `gorm:"index;column:ditolak_oleh" json:"ditolak_oleh"`
+	DitolakOlehPengguna   *Pengguna  `gorm:"foreignKey:DitolakOleh" json:"ditolak_oleh_pengguna,omitempty"`
+	DitolakPada           *time.Time `gorm:"column:ditolak_pada" json:"ditolak_pada"`
 	Status                string     `gorm:"type:varchar(20);default:'pending';column:status" json:"status"` // pending, approved, rejected
 	Keterangan            string     `gorm:"type:text;column:keterangan" json:"keterangan"`
 	DibuatPada            time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
@@ -95,6 +99,9 @@ type ItemBarangKeluar struct {
 	Gudang            Gudang           `gorm:"foreignKey:IDGudang" json:"gudang,omitempty"`
 	IDItemBarangMasuk *uint            `gorm:"index;column:id_stock_in_item" json:"id_item_barang_masuk"` // Untuk FIFO tracking
 	ItemBarangMasuk   *ItemBarangMasuk `gorm:"foreignKey:IDItemBarangMasuk" json:"item_barang_masuk,omitempty"`
+	IDBatch           *uint            `gorm:"index;column:id_batch" json:"id_batch"` // Batch FIFO yang dikurangi
+	Batch             *StokBatch       `gorm:"foreignKey:IDBatch" json:"batch,omitempty"`
+	HargaModal        float64          `gorm:"type:decimal(15,2);default:0;column:harga_modal" json:"harga_modal"` // HPP batch saat keluar
 	DibuatPada        time.Time        `gorm:"column:dibuat_pada" json:"dibuat_pada"`
 	DiperbaruiPada    time.Time        `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// PeringatanStok adalah model untuk peringatan stok menipis per produk per gudang.
+// Dibuka saat saldo turun sampai <= StokMinimum produk, selesai otomatis saat saldo kembali di atas minimum.
+// Hanya boleh ada satu peringatan aktif (open/acknowledged) untuk setiap produk+gudang.
+type PeringatanStok struct {
+	ID                       uint       `gorm:"primaryKey;column:id" json:"id"`
+	IDProduk                 uint       `gorm:"uniqueIndex:idx_peringatan_stok_aktif,where:status <> 'resolved';not null;column:id_produk" json:"id_produk"`
+	Produk                   Produk     `gorm:"foreignKey:IDProduk" json:"produk,omitempty"`
+	IDGudang                 uint       `gorm:"uniqueIndex:idx_peringatan_stok_aktif,where:status <> 'resolved';index;not null;column:id_gudang" json:"id_gudang"`
+	Gudang                   Gudang     `gorm:"foreignKey:IDGudang" json:"gudang,omitempty"`
+	Status                   string     `gorm:"type:varchar(20);default:'open';index;column:status" json:"status"` // open, acknowledged, resolved
+	StokMinimum              int        `gorm:"not null;column:stok_minimum" json:"stok_minimum"`                  // Minimum produk saat terakhir dievaluasi
+	JumlahSaatDibuka         int        `gorm:"not null;column:jumlah_saat_dibuka" json:"jumlah_saat_dibuka"`
+	JumlahTerakhir           int        `gorm:"not null;column:jumlah_terakhir" json:"jumlah_terakhir"`
+	DibukaPada               time.Time  `gorm:"column:dibuka_pada" json:"dibuka_pada"`
+	DikonfirmasiOleh         *uint      `gorm:"index;column:dikonfirmasi_oleh" json:"dikonfirmasi_oleh"`
+	DikonfirmasiOlehPengguna *Pengguna  `gorm:"foreignKey:DikonfirmasiOleh" json:"dikonfirmasi_oleh_pengguna,omitempty"`
+	DikonfirmasiPada         *time.Time `gorm:"column:dikonfirmasi_pada" json:"dikonfirmasi_pada"`
+	CatatanKonfirmasi        string     `gorm:"type:text;column:catatan_konfirmasi" json:"catatan_konfirmasi"`
+	DiselesaikanPada         *time.Time `gorm:"column:diselesaikan_pada" json:"diselesaikan_pada"`
+	DibuatPada               time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+	DiperbaruiPada           time.Time  `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model PeringatanStok
+func (PeringatanStok) TableName() string {
+	return "peringatan_stok"
+}
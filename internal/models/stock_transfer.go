@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+)
+
+// TransferStok adalah model dokumen transfer stok antar gudang.
+// Alur status: draft → shipped (barang dalam perjalanan) → received, atau cancelled.
+// Selama status shipped, stok sudah keluar dari gudang asal tapi belum masuk gudang tujuan (in-transit).
+type TransferStok struct {
+	ID                     uint       `gorm:"primaryKey;column:id" json:"id"`
+	NomorTransfer          string     `gorm:"uniqueIndex;not null;column:nomor_transfer" json:"nomor_transfer"`
+	IDGudangAsal           uint       `gorm:"index;not null;column:id_gudang_asal" json:"id_gudang_asal"`
+	GudangAsal             Gudang     `gorm:"foreignKey:IDGudangAsal" json:"gudang_asal,omitempty"`
+	IDGudangTujuan         uint       `gorm:"index;not null;column:id_gudang_tujuan" json:"id_gudang_tujuan"`
+	GudangTujuan           Gudang     `gorm:"foreignKey:IDGudangTujuan" json:"gudang_tujuan,omitempty"`
+	Status                 string     `gorm:"type:varchar(20);default:'draft';column:status" json:"status"` // draft, shipped, received, cancelled
+	Keterangan             string     `gorm:"type:text;column:keterangan" json:"keterangan"`
+	DibuatOleh             uint       `gorm:"index;not null;column:dibuat_oleh" json:"dibuat_oleh"`
+	DibuatOlehPengguna     Pengguna   `gorm:"foreignKey:DibuatOleh" json:"dibuat_oleh_pengguna,omitempty"`
+	DikirimOleh            *uint      `gorm:"index;column:dikirim_oleh" json:"dikirim_oleh"`
+	DikirimOlehPengguna    *Pengguna  `gorm:"foreignKey:DikirimOleh" json:"dikirim_oleh_pengguna,omitempty"`
+	DikirimPada            *time.Time `gorm:"column:dikirim_pada" json:"dikirim_pada"`
+	DiterimaOleh           *uint      `gorm:"index;column:diterima_oleh" json:"diterima_oleh"`
+	DiterimaOlehPengguna   *Pengguna  `gorm:"foreignKey:DiterimaOleh" json:"diterima_oleh_pengguna,omitempty"`
+	DiterimaPada           *time.Time `gorm:"column:diterima_pada" json:"diterima_pada"`
+	DibatalkanOleh         *uint      `gorm:"index;column:dibatalkan_oleh" json:"dibatalkan_oleh"`
+	DibatalkanOlehPengguna *Pengguna  `gorm:"foreignKey:DibatalkanOleh" json:"dibatalkan_oleh_pengguna,omitempty"`
+	DibatalkanPada         *time.Time `gorm:"column:dibatalkan_pada" json:"dibatalkan_pada"`
+	DibuatPada             time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+	DiperbaruiPada         time.Time  `gorm:"column:diperbarui_pada" json:"diperbarui_pada"`
+
+	// Relationship
+	Items []ItemTransferStok `gorm:"foreignKey:IDTransfer;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+// TableName mengembalikan nama tabel untuk model TransferStok
+func (TransferStok) TableName() string {
+	return "transfer_stok"
+}
+
+// ItemTransferStok adalah model untuk detail produk yang ditransfer
+type ItemTransferStok struct {
+	ID         uint      `gorm:"primaryKey;column:id" json:"id"`
+	IDTransfer uint      `gorm:"index;not null;column:id_transfer" json:"id_transfer"`
+	IDProduk   uint      `gorm:"index;not null;column:id_produk" json:"id_produk"`
+	Produk     Produk    `gorm:"foreignKey:IDProduk" json:"produk,omitempty"`
+	Jumlah     int       `gorm:"not null;column:jumlah" json:"jumlah"`
+	DibuatPada time.Time `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+
+	// Breakdown batch FIFO yang diambil dari gudang asal (terisi saat shipped)
+	Batches []ItemTransferStokBatch `gorm:"foreignKey:IDItemTransfer;constraint:OnDelete:CASCADE" json:"batches,omitempty"`
+}
+
+// TableName mengembalikan nama tabel untuk model ItemTransferStok
+func (ItemTransferStok) TableName() string {
+	return "item_transfer_stok"
+}
+
+// ItemTransferStokBatch mencatat qty per batch asal yang sedang/sudah dipindahkan.
+// Baris ini sekaligus menjadi "stok in-transit": qty sudah keluar dari batch asal,
+// dan baru menjadi batch di gudang tujuan (IDBatchTujuan) saat transfer diterima.
+type ItemTransferStokBatch struct {
+	ID             uint       `gorm:"primaryKey;column:id" json:"id"`
+	IDItemTransfer uint       `gorm:"index;not null;column:id_item_transfer" json:"id_item_transfer"`
+	IDBatchAsal    uint       `gorm:"index;not null;column:id_batch_asal" json:"id_batch_asal"`
+	BatchAsal      StokBatch  `gorm:"foreignKey:IDBatchAsal" json:"batch_asal,omitempty"`
+	IDBatchTujuan  *uint      `gorm:"index;column:id_batch_tujuan" json:"id_batch_tujuan"` // Terisi saat received
+	BatchTujuan    *StokBatch `gorm:"foreignKey:IDBatchTujuan" json:"batch_tujuan,omitempty"`
+	Jumlah         int        `gorm:"not null;column:jumlah" json:"jumlah"`
+	HargaModal     float64    `gorm:"type:decimal(15,2);not null;column:harga_modal" json:"harga_modal"` // HPP batch asal
+	DibuatPada     time.Time  `gorm:"column:dibuat_pada" json:"dibuat_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model ItemTransferStokBatch
+func (ItemTransferStokBatch) TableName() string {
+	return "item_transfer_stok_batch"
+}
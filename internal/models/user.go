@@ -12,8 +12,9 @@ type Pengguna struct {
 	Email          string         `json:"email" gorm:"uniqueIndex;not null;column:email"`
 	Password       string         `json:"-" gorm:"not null;column:password"`
 	Nama           string         `json:"nama" gorm:"not null;column:nama"`
-	Peran          string         `gorm:"type:varchar(50);default:'kasir';column:peran" json:"peran"` // owner, kasir, admin_gudang, finance - string dulu, bisa jadi FK nanti
+	Peran          string         `gorm:"type:varchar(50);default:'kasir';column:peran" json:"peran"` // Kode role di tabel peran (owner, kasir, admin_gudang, finance, atau role custom)
 	Aktif          bool           `gorm:"default:true;column:aktif" json:"aktif"`
+	VersiToken     int            `gorm:"default:0;not null;column:versi_token" json:"-"` // Naik saat logout semua device; access token versi lama ditolak
 	DibuatPada     time.Time      `json:"dibuat_pada" gorm:"column:dibuat_pada"`
 	DiperbaruiPada time.Time      `json:"diperbarui_pada" gorm:"column:diperbarui_pada"`
 	DihapusPada    gorm.DeletedAt `json:"-" gorm:"index;column:dihapus_pada"`
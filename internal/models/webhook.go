@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// LanggananWebhook adalah endpoint HTTP eksternal yang menerima event domain.
+// TipeEvent berisi daftar tipe event dipisah koma ("*" = semua event).
+type LanggananWebhook struct {
+	ID                 uint      `json:"id" gorm:"primaryKey;column:id"`
+	Nama               string    `json:"nama" gorm:"type:varchar(100);not null;column:nama"`
+	URL                string    `json:"url" gorm:"type:varchar(500);not null;column:url"`
+	Secret             string    `json:"-" gorm:"type:varchar(128);not null;column:secret"` // Kunci HMAC-SHA256 untuk header X-Webhook-Signature
+	TipeEvent          string    `json:"tipe_event" gorm:"type:text;not null;column:tipe_event"`
+	Aktif              bool      `json:"aktif" gorm:"default:true;column:aktif"`
+	DibuatOleh         uint      `json:"dibuat_oleh" gorm:"index;not null;column:dibuat_oleh"`
+	DibuatOlehPengguna Pengguna  `json:"dibuat_oleh_pengguna,omitempty" gorm:"foreignKey:DibuatOleh"`
+	DibuatPada         time.Time `json:"dibuat_pada" gorm:"column:dibuat_pada"`
+	DiperbaruiPada     time.Time `json:"diperbarui_pada" gorm:"column:diperbarui_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model LanggananWebhook
+func (LanggananWebhook) TableName() string {
+	return "langganan_webhook"
+}
+
+// PengirimanWebhook adalah log pengiriman satu event ke satu webhook, sekaligus antrean retry-nya
+type PengirimanWebhook struct {
+	ID                  uint             `json:"id" gorm:"primaryKey;column:id"`
+	IDWebhook           uint             `json:"id_webhook" gorm:"not null;uniqueIndex:idx_pengiriman_webhook_event;column:id_webhook"`
+	Webhook             LanggananWebhook `json:"webhook,omitempty" gorm:"foreignKey:IDWebhook;constraint:OnDelete:CASCADE"`
+	IDEvent             uint             `json:"id_event" gorm:"not null;uniqueIndex:idx_pengiriman_webhook_event;column:id_event"` // ID antrean_event
+	TipeEvent           string           `json:"tipe_event" gorm:"type:varchar(50);not null;column:tipe_event"`
+	Payload             string           `json:"payload" gorm:"type:jsonb;not null;column:payload"`                                                           // Body yang dikirim (ditandatangani)
+	Status              string           `json:"status" gorm:"type:varchar(20);not null;default:'pending';index:idx_pengiriman_webhook_status;column:status"` // pending, success, failed
+	Percobaan           int              `json:"percobaan" gorm:"not null;default:0;column:percobaan"`
+	PercobaanBerikutnya time.Time        `json:"percobaan_berikutnya" gorm:"not null;index:idx_pengiriman_webhook_status;column:percobaan_berikutnya"`
+	StatusHTTP          int              `json:"status_http" gorm:"column:status_http"`
+	ResponTerakhir      string           `json:"respon_terakhir" gorm:"type:text;column:respon_terakhir"` // Dipotong maksimal 1 KB
+	ErrorTerakhir       string           `json:"error_terakhir" gorm:"type:text;column:error_terakhir"`
+	DurasiMs            int64            `json:"durasi_ms" gorm:"column:durasi_ms"`
+	TerkirimPada        *time.Time       `json:"terkirim_pada" gorm:"column:terkirim_pada"`
+	DibuatPada          time.Time        `json:"dibuat_pada" gorm:"column:dibuat_pada"`
+	DiperbaruiPada      time.Time        `json:"diperbarui_pada" gorm:"column:diperbarui_pada"`
+}
+
+// TableName mengembalikan nama tabel untuk model PengirimanWebhook
+func (PengirimanWebhook) TableName() string {
+	return "pengiriman_webhook"
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HutangRepository interface {
+	// Buat hutang baru dalam TX (dipanggil saat barang masuk dari supplier diapprove)
+	Create(tx *gorm.DB, hutang *models.HutangPemasok) error
+
+	// Ambil detail hutang dengan riwayat pembayaran
+	FindByID(id uint) (*models.HutangPemasok, error)
+
+	// Ambil hutang dalam TX dengan row lock (untuk pembayaran)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*models.HutangPemasok, error)
+
+	// List hutang dengan filter dan pagination
+	FindAll(req *dto.ListHutangRequest) ([]models.HutangPemasok, int64, error)
+
+	// Hutang yang masih terbuka per `asOf` (untuk laporan aging), termasuk yang lunas setelahnya, beserta pembayarannya
+	FindOutstanding(idPemasok *uint, asOf time.Time) ([]models.HutangPemasok, error)
+
+	// Update saldo hutang setelah pembayaran
+	UpdatePayment(tx *gorm.DB, hutang *models.HutangPemasok) error
+
+	// Catat riwayat pembayaran
+	CreatePembayaran(tx *gorm.DB, pembayaran *models.PembayaranHutang) error
+
+	// Begin transaction
+	BeginTx() *gorm.DB
+}
+
+type hutangRepository struct {
+	db *gorm.DB
+}
+
+func NewHutangRepository(db *gorm.DB) HutangRepository {
+	return &hutangRepository{db: db}
+}
+
+func (r *hutangRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *hutangRepository) Create(tx *gorm.DB, hutang *models.HutangPemasok) error {
+	return tx.Create(hutang).Error
+}
+
+func (r *hutangRepository) FindByID(id uint) (*models.HutangPemasok, error) {
+	var hutang models.HutangPemasok
+	err := r.db.
+		Preload("Pemasok").
+		Preload("Pembayaran", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tanggal_bayar ASC")
+		}).
+		Preload("Pembayaran.DibayarOlehPengguna").
+		First(&hutang, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hutang, nil
+}
+
+func (r *hutangRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*models.HutangPemasok, error) {
+	var hutang models.HutangPemasok
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hutang, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hutang, nil
+}
+
+func (r *hutangRepository) FindAll(req *dto.ListHutangRequest) ([]models.HutangPemasok, int64, error) {
+	var hutangs []models.HutangPemasok
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.HutangPemasok{})
+
+	if req.IDPemasok != nil {
+		query = query.Where("id_supplier = ?", *req.IDPemasok)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.JatuhTempo {
+		query = query.Where("status <> ? AND jatuh_tempo < ?", "paid", time.Now())
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Pemasok").
+		Order("jatuh_tempo ASC NULLS LAST, dibuat_pada DESC").
+		Limit(limit).Offset(offset).
+		Find(&hutangs).Error
+
+	return hutangs, total, err
+}
+
+func (r *hutangRepository) FindOutstanding(idPemasok *uint, asOf time.Time) ([]models.HutangPemasok, error) {
+	var hutangs []models.HutangPemasok
+	query := r.db.Preload("Pemasok").Preload("Pembayaran").
+		Where("dibuat_pada <= ?", asOf).
+		Where("status <> ? OR EXISTS (SELECT 1 FROM pembayaran_hutang ph WHERE ph.id_hutang = hutang_pemasok.id AND ph.tanggal_bayar > ?)", "paid", asOf)
+	if idPemasok != nil {
+		query = query.Where("id_supplier = ?", *idPemasok)
+	}
+	err := query.Order("id_supplier ASC, jatuh_tempo ASC").Find(&hutangs).Error
+	return hutangs, err
+}
+
+func (r *hutangRepository) UpdatePayment(tx *gorm.DB, hutang *models.HutangPemasok) error {
+	return tx.Model(hutang).Select(
+		"jumlah_dibayar", "sisa_hutang", "status", "bukti_pembayaran", "dibayar_pada", "dibayar_oleh", "diperbarui_pada",
+	).Updates(hutang).Error
+}
+
+func (r *hutangRepository) CreatePembayaran(tx *gorm.DB, pembayaran *models.PembayaranHutang) error {
+	return tx.Create(pembayaran).Error
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvitationUnavailable dikembalikan saat undangan sudah dipakai, dibatalkan, atau kedaluwarsa
+var ErrInvitationUnavailable = errors.New("invitation is no longer available")
+
+// InvitationRepository adalah interface untuk undangan pengguna
+type InvitationRepository interface {
+	Create(invitation *models.UndanganPengguna) error
+	FindByID(id uint) (*models.UndanganPengguna, error)
+	FindByTokenHash(hash string) (*models.UndanganPengguna, error)
+	FindAll(page, pageSize int) ([]models.UndanganPengguna, int64, error)
+	Revoke(id uint, at time.Time) error
+	RevokePendingByEmail(email string, at time.Time) error
+	Accept(invitationID uint, user *models.User, at time.Time) error
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository membuat instance InvitationRepository baru
+func NewInvitationRepository() InvitationRepository {
+	return &invitationRepository{
+		db: database.DB,
+	}
+}
+
+func (r *invitationRepository) Create(invitation *models.UndanganPengguna) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByID(id uint) (*models.UndanganPengguna, error) {
+	var invitation models.UndanganPengguna
+	err := r.db.Preload("Pembuat").First(&invitation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(hash string) (*models.UndanganPengguna, error) {
+	var invitation models.UndanganPengguna
+	err := r.db.Where("token_hash = ?", hash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindAll(page, pageSize int) ([]models.UndanganPengguna, int64, error) {
+	var invitations []models.UndanganPengguna
+	var total int64
+
+	query := r.db.Model(&models.UndanganPengguna{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Preload("Pembuat").Offset(offset).Limit(pageSize).Order("dibuat_pada DESC").Find(&invitations).Error
+	return invitations, total, err
+}
+
+func (r *invitationRepository) Revoke(id uint, at time.Time) error {
+	result := r.db.Model(&models.UndanganPengguna{}).
+		Where("id = ? AND dipakai_pada IS NULL AND dibatalkan_pada IS NULL", id).
+		Update("dibatalkan_pada", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvitationUnavailable
+	}
+	return nil
+}
+
+func (r *invitationRepository) RevokePendingByEmail(email string, at time.Time) error {
+	return r.db.Model(&models.UndanganPengguna{}).
+		Where("email = ? AND dipakai_pada IS NULL AND dibatalkan_pada IS NULL", email).
+		Update("dibatalkan_pada", at).Error
+}
+
+// Accept membuat user dari undangan dan menandai undangan terpakai dalam satu transaksi.
+// Baris undangan dikunci agar token yang sama tidak bisa dipakai dua kali.
+func (r *invitationRepository) Accept(invitationID uint, user *models.User, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var invitation models.UndanganPengguna
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invitation, invitationID).Error; err != nil {
+			return err
+		}
+		if invitation.DipakaiPada != nil || invitation.DibatalkanPada != nil || !at.Before(invitation.KedaluwarsaPada) {
+			return ErrInvitationUnavailable
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&invitation).Updates(map[string]interface{}{
+			"dipakai_pada": at,
+			"id_pengguna":  user.ID,
+		}).Error
+	})
+}
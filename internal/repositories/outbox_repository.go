@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	BeginTx() *gorm.DB
+
+	// Simpan event di dalam transaksi bisnis (commit/rollback bersama datanya)
+	Create(tx *gorm.DB, events []models.AntreanEvent) error
+
+	// Ambil event pending yang sudah jatuh tempo dengan row lock SKIP LOCKED
+	// agar beberapa dispatcher tidak mengirim event yang sama
+	FindDueForUpdate(tx *gorm.DB, now time.Time, limit int) ([]models.AntreanEvent, error)
+	MarkDispatched(tx *gorm.DB, id uint, at time.Time) error
+	MarkRetry(tx *gorm.DB, id uint, attempts int, next time.Time, lastError string, failed bool) error
+
+	// Replay untuk client WebSocket yang reconnect (urut ID naik)
+	FindSince(sinceID uint, limit int) ([]models.AntreanEvent, error)
+
+	// Hapus event terkirim yang lebih lama dari batas retensi
+	DeleteDispatchedBefore(before time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *outboxRepository) Create(tx *gorm.DB, events []models.AntreanEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
+
+func (r *outboxRepository) FindDueForUpdate(tx *gorm.DB, now time.Time, limit int) ([]models.AntreanEvent, error) {
+	var events []models.AntreanEvent
+	err := tx.Where("status = ? AND percobaan_berikutnya <= ?", "pending", now).
+		Order("id ASC").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Find(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) MarkDispatched(tx *gorm.DB, id uint, at time.Time) error {
+	return tx.Model(&models.AntreanEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":         "dispatched",
+		"terkirim_pada":  at,
+		"error_terakhir": "",
+	}).Error
+}
+
+func (r *outboxRepository) MarkRetry(tx *gorm.DB, id uint, attempts int, next time.Time, lastError string, failed bool) error {
+	status := "pending"
+	if failed {
+		status = "failed"
+	}
+	return tx.Model(&models.AntreanEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":               status,
+		"percobaan":            attempts,
+		"percobaan_berikutnya": next,
+		"error_terakhir":       lastError,
+	}).Error
+}
+
+func (r *outboxRepository) FindSince(sinceID uint, limit int) ([]models.AntreanEvent, error) {
+	var events []models.AntreanEvent
+	err := r.db.Where("id > ?", sinceID).Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) DeleteDispatchedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND terkirim_pada < ?", "dispatched", before).Delete(&models.AntreanEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"time"
+
+	"real-erp-mebel/be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PelangganPurchaseSummary adalah agregat riwayat belanja satu pelanggan
+type PelangganPurchaseSummary struct {
+	TotalTransaksi    int64
+	TotalBelanja      float64
+	PembelianPertama  *time.Time
+	PembelianTerakhir *time.Time
+	TotalRetur        int64
+	NilaiRetur        float64
+}
+
+// SaleCustomerName adalah kombinasi nama/kontak pelanggan free-text pada penjualan yang belum ter-link
+type SaleCustomerName struct {
+	NamaPelanggan   string
+	KontakPelanggan string
+	Jumlah          int64
+	Terakhir        time.Time
+}
+
+type PelangganRepository interface {
+	Create(pelanggan *models.Pelanggan) error
+	FindByID(id uint) (*models.Pelanggan, error)
+	// Cari pelanggan lain dengan nomor telepon yang sama (hanya digit yang dibandingkan)
+	FindByTeleponDigits(digits []string, excludeID uint) (*models.Pelanggan, error)
+	List(filters map[string]interface{}, page, limit int) ([]models.Pelanggan, int64, error)
+	Update(pelanggan *models.Pelanggan) error
+	Delete(id uint) error
+
+	// Riwayat pembelian
+	PurchaseSummary(id uint) (*PelangganPurchaseSummary, error)
+	RecentSales(id uint, limit int) ([]models.Penjualan, error)
+	RecentReturns(id uint, limit int) ([]models.ReturPenjualan, error)
+
+	// Migrasi nama pelanggan free-text ke master pelanggan
+	FindAllForMatching() ([]models.Pelanggan, error)
+	UnlinkedSaleCustomers() ([]SaleCustomerName, error)
+	CreateTx(tx *gorm.DB, pelanggan *models.Pelanggan) error
+	LinkSales(tx *gorm.DB, pelangganID uint, nama, kontak string) (int64, error)
+	LinkReturnsFromSales(tx *gorm.DB) (int64, error)
+
+	BeginTx() *gorm.DB
+}
+
+type pelangganRepository struct {
+	db *gorm.DB
+}
+
+func NewPelangganRepository(db *gorm.DB) PelangganRepository {
+	return &pelangganRepository{db: db}
+}
+
+func (r *pelangganRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *pelangganRepository) Create(pelanggan *models.Pelanggan) error {
+	return r.db.Create(pelanggan).Error
+}
+
+func (r *pelangganRepository) FindByID(id uint) (*models.Pelanggan, error) {
+	var pelanggan models.Pelanggan
+	if err := r.db.First(&pelanggan, id).Error; err != nil {
+		return nil, err
+	}
+	return &pelanggan, nil
+}
+
+func (r *pelangganRepository) FindByTeleponDigits(digits []string, excludeID uint) (*models.Pelanggan, error) {
+	var pelanggan []models.Pelanggan
+	err := r.db.
+		Where("regexp_replace(telepon, '[^0-9]', '', 'g') IN ?", digits).
+		Where("id <> ?", excludeID).
+		Limit(1).
+		Find(&pelanggan).Error
+	if err != nil || len(pelanggan) == 0 {
+		return nil, err
+	}
+	return &pelanggan[0], nil
+}
+
+func (r *pelangganRepository) List(filters map[string]interface{}, page, limit int) ([]models.Pelanggan, int64, error) {
+	var pelanggan []models.Pelanggan
+	var total int64
+
+	query := r.db.Model(&models.Pelanggan{})
+
+	if search, ok := filters["search"].(string); ok && search != "" {
+		query = query.Where("nama ILIKE ? OR telepon ILIKE ? OR email ILIKE ?",
+			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+	}
+
+	if aktif, ok := filters["aktif"].(bool); ok {
+		query = query.Where("aktif = ?", aktif)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("nama ASC").Offset(offset).Limit(limit).Find(&pelanggan).Error
+	return pelanggan, total, err
+}
+
+func (r *pelangganRepository) Update(pelanggan *models.Pelanggan) error {
+	return r.db.Save(pelanggan).Error
+}
+
+func (r *pelangganRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Pelanggan{}, id).Error
+}
+
+// PurchaseSummary menghitung belanja dari penjualan terjual dan retur completed milik pelanggan
+func (r *pelangganRepository) PurchaseSummary(id uint) (*PelangganPurchaseSummary, error) {
+	var summary PelangganPurchaseSummary
+
+	err := r.db.Model(&models.Penjualan{}).
+		Select("COUNT(*) as total_transaksi, COALESCE(SUM(total), 0) as total_belanja, "+
+			"MIN(dibuat_pada) as pembelian_pertama, MAX(dibuat_pada) as pembelian_terakhir").
+		Where("id_pelanggan = ? AND status IN ?", id, models.StatusPenjualanTerjual).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var retur struct {
+		TotalRetur int64
+		NilaiRetur float64
+	}
+	err = r.db.Model(&models.ReturPenjualan{}).
+		Select("COUNT(*) as total_retur, COALESCE(SUM(total), 0) as nilai_retur").
+		Where("id_pelanggan = ? AND status = ?", id, "completed").
+		Scan(&retur).Error
+	if err != nil {
+		return nil, err
+	}
+	summary.TotalRetur = retur.TotalRetur
+	summary.NilaiRetur = retur.NilaiRetur
+
+	return &summary, nil
+}
+
+func (r *pelangganRepository) RecentSales(id uint, limit int) ([]models.Penjualan, error) {
+	var sales []models.Penjualan
+	err := r.db.
+		Preload("Gudang").
+		Where("id_pelanggan = ?", id).
+		Order("dibuat_pada DESC").
+		Limit(limit).
+		Find(&sales).Error
+	return sales, err
+}
+
+func (r *pelangganRepository) RecentReturns(id uint, limit int) ([]models.ReturPenjualan, error) {
+	var returns []models.ReturPenjualan
+	err := r.db.
+		Preload("Penjualan").
+		Where("id_pelanggan = ?", id).
+		Order("dibuat_pada DESC").
+		Limit(limit).
+		Find(&returns).Error
+	return returns, err
+}
+
+func (r *pelangganRepository) FindAllForMatching() ([]models.Pelanggan, error) {
+	var pelanggan []models.Pelanggan
+	err := r.db.Order("id ASC").Find(&pelanggan).Error
+	return pelanggan, err
+}
+
+// UnlinkedSaleCustomers mengelompokkan nama/kontak pelanggan pada penjualan yang belum punya id_pelanggan
+func (r *pelangganRepository) UnlinkedSaleCustomers() ([]SaleCustomerName, error) {
+	var rows []SaleCustomerName
+	err := r.db.Model(&models.Penjualan{}).
+		Select("COALESCE(nama_pelanggan, '') as nama_pelanggan, COALESCE(kontak_pelanggan, '') as kontak_pelanggan, " +
+			"COUNT(*) as jumlah, MAX(dibuat_pada) as terakhir").
+		Where("id_pelanggan IS NULL").
+		Where("COALESCE(nama_pelanggan, '') <> '' OR COALESCE(kontak_pelanggan, '') <> ''").
+		Group("nama_pelanggan, kontak_pelanggan").
+		Order("terakhir DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *pelangganRepository) CreateTx(tx *gorm.DB, pelanggan *models.Pelanggan) error {
+	return tx.Create(pelanggan).Error
+}
+
+func (r *pelangganRepository) LinkSales(tx *gorm.DB, pelangganID uint, nama, kontak string) (int64, error) {
+	result := tx.Model(&models.Penjualan{}).
+		Where("id_pelanggan IS NULL").
+		Where("COALESCE(nama_pelanggan, '') = ? AND COALESCE(kontak_pelanggan, '') = ?", nama, kontak).
+		Update("id_pelanggan", pelangganID)
+	return result.RowsAffected, result.Error
+}
+
+// LinkReturnsFromSales menyalin id_pelanggan dari penjualan asal ke retur yang belum ter-link
+func (r *pelangganRepository) LinkReturnsFromSales(tx *gorm.DB) (int64, error) {
+	result := tx.Exec(`UPDATE retur_penjualan SET id_pelanggan = penjualan.id_pelanggan
+		FROM penjualan
+		WHERE retur_penjualan.id_penjualan = penjualan.id
+		AND retur_penjualan.id_pelanggan IS NULL
+		AND penjualan.id_pelanggan IS NOT NULL`)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"errors"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPOStatusChanged dikembalikan saat status PO sudah diubah transaksi lain (approve / cancel / penerimaan bersamaan)
+var ErrPOStatusChanged = errors.New("status purchase order sudah berubah, muat ulang data purchase order")
+
+type PurchaseOrderRepository interface {
+	// Buat PO lengkap dalam TX (header + items)
+	Create(tx *gorm.DB, po *models.PesananPembelian) error
+
+	// Ambil detail PO by ID dengan semua relasi
+	FindByID(id uint) (*models.PesananPembelian, error)
+
+	// List PO dengan filter dan pagination
+	FindAll(req *dto.ListPurchaseOrderRequest) ([]models.PesananPembelian, int64, error)
+
+	// Update header PO (tanpa items)
+	Update(tx *gorm.DB, po *models.PesananPembelian) error
+
+	// Ganti seluruh item PO (hanya dipakai saat status draft)
+	ReplaceItems(tx *gorm.DB, poID uint, items []models.ItemPesananPembelian) error
+
+	// Update status PO beserta kolom tambahan (disetujui_oleh, dll); hanya berlaku jika status masih fromStatus,
+	// selain itu ErrPOStatusChanged
+	UpdateStatus(tx *gorm.DB, id uint, fromStatus, status string, extra map[string]interface{}) error
+
+	// Ambil PO beserta items dalam TX dengan row lock (untuk penerimaan barang)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*models.PesananPembelian, error)
+
+	// Tambah jumlah_diterima pada item PO
+	IncrementReceived(tx *gorm.DB, itemID uint, qty int) error
+
+	// Begin transaction
+	BeginTx() *gorm.DB
+}
+
+type purchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+func (r *purchaseOrderRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *purchaseOrderRepository) Create(tx *gorm.DB, po *models.PesananPembelian) error {
+	return tx.Create(po).Error
+}
+
+func (r *purchaseOrderRepository) FindByID(id uint) (*models.PesananPembelian, error) {
+	var po models.PesananPembelian
+	err := r.db.
+		Preload("Pemasok").
+		Preload("DibuatOlehPengguna").
+		Preload("DisetujuiOlehPengguna").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Items.Produk").
+		First(&po, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+func (r *purchaseOrderRepository) FindAll(req *dto.ListPurchaseOrderRequest) ([]models.PesananPembelian, int64, error) {
+	var pos []models.PesananPembelian
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.PesananPembelian{})
+
+	if req.IDPemasok != nil {
+		query = query.Where("id_supplier = ?", *req.IDPemasok)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.TanggalDari != nil {
+		startOfDay := time.Date(req.TanggalDari.Year(), req.TanggalDari.Month(), req.TanggalDari.Day(), 0, 0, 0, 0, req.TanggalDari.Location())
+		query = query.Where("tanggal_pesan >= ?", startOfDay)
+	}
+	if req.TanggalSampai != nil {
+		endOfDay := time.Date(req.TanggalSampai.Year(), req.TanggalSampai.Month(), req.TanggalSampai.Day(), 23, 59, 59, 999999999, req.TanggalSampai.Location())
+		query = query.Where("tanggal_pesan <= ?", endOfDay)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Pemasok").
+		Preload("DibuatOlehPengguna").
+		Order("dibuat_pada DESC").
+		Limit(limit).Offset(offset).
+		Find(&pos).Error
+
+	return pos, total, err
+}
+
+func (r *purchaseOrderRepository) Update(tx *gorm.DB, po *models.PesananPembelian) error {
+	return tx.Model(po).Select(
+		"id_supplier", "tanggal_pesan", "tanggal_jatuh_tempo", "total", "diperbarui_pada",
+	).Updates(po).Error
+}
+
+func (r *purchaseOrderRepository) ReplaceItems(tx *gorm.DB, poID uint, items []models.ItemPesananPembelian) error {
+	if err := tx.Where("id_po = ?", poID).Delete(&models.ItemPesananPembelian{}).Error; err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].IDPO = poID
+	}
+	return tx.Create(&items).Error
+}
+
+func (r *purchaseOrderRepository) UpdateStatus(tx *gorm.DB, id uint, fromStatus, status string, extra map[string]interface{}) error {
+	updates := map[string]interface{}{
+		"status":          status,
+		"diperbarui_pada": time.Now(),
+	}
+	for k, v := range extra {
+		updates[k] = v
+	}
+	result := tx.Model(&models.PesananPembelian{}).Where("id = ? AND status = ?", id, fromStatus).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPOStatusChanged
+	}
+	return nil
+}
+
+// FindByIDForUpdate mengunci baris PO (FOR UPDATE) agar penerimaan paralel tidak melebihi qty PO
+func (r *purchaseOrderRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*models.PesananPembelian, error) {
+	var po models.PesananPembelian
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Items.Produk").
+		First(&po, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+func (r *purchaseOrderRepository) IncrementReceived(tx *gorm.DB, itemID uint, qty int) error {
+	return tx.Model(&models.ItemPesananPembelian{}).Where("id = ?", itemID).Updates(map[string]interface{}{
+		"jumlah_diterima": gorm.Expr("jumlah_diterima + ?", qty),
+		"diperbarui_pada": time.Now(),
+	}).Error
+}
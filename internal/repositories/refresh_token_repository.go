@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRefreshTokenRevoked dikembalikan saat token yang akan dirotasi ternyata sudah dicabut
+var ErrRefreshTokenRevoked = errors.New("refresh token has been revoked")
+
+// RefreshTokenRepository adalah interface untuk refresh token repository
+type RefreshTokenRepository interface {
+	Create(token *models.TokenRefresh) error
+	FindByHash(hash string) (*models.TokenRefresh, error)
+	Rotate(oldID uint, newToken *models.TokenRefresh, at time.Time) error
+	Revoke(id uint, at time.Time) error
+	RevokeAllByUser(userID uint, at time.Time) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository membuat instance RefreshTokenRepository baru
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: database.DB,
+	}
+}
+
+func (r *refreshTokenRepository) Create(token *models.TokenRefresh) error {
+	return r.db.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(hash string) (*models.TokenRefresh, error) {
+	var token models.TokenRefresh
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate mencabut token lama dan menyimpan token pengganti dalam satu transaksi.
+// Baris token lama dikunci agar dua refresh paralel dengan token yang sama tidak sama-sama lolos.
+func (r *refreshTokenRepository) Rotate(oldID uint, newToken *models.TokenRefresh, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var old models.TokenRefresh
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&old, oldID).Error; err != nil {
+			return err
+		}
+		if old.DicabutPada != nil {
+			return ErrRefreshTokenRevoked
+		}
+
+		if err := tx.Create(newToken).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&old).Updates(map[string]interface{}{
+			"dicabut_pada": at,
+			"diganti_oleh": newToken.ID,
+		}).Error
+	})
+}
+
+func (r *refreshTokenRepository) Revoke(id uint, at time.Time) error {
+	return r.db.Model(&models.TokenRefresh{}).
+		Where("id = ? AND dicabut_pada IS NULL", id).
+		Update("dicabut_pada", at).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllByUser(userID uint, at time.Time) error {
+	return r.db.Model(&models.TokenRefresh{}).
+		Where("id_pengguna = ? AND dicabut_pada IS NULL", userID).
+		Update("dicabut_pada", at).Error
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"time"
+
+	"real-erp-mebel/be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ProductWarehouseQty adalah agregat jumlah per produk per gudang
+type ProductWarehouseQty struct {
+	IDProduk uint
+	IDGudang uint
+	Jumlah   int
+}
+
+// ReorderRepository menyediakan data agregat untuk saran pemesanan ulang (reorder)
+type ReorderRepository interface {
+	// Total qty terjual (penjualan completed / pesanan terkirim) sejak `since` per produk+gudang
+	SalesQtySince(since time.Time, warehouseID *uint) ([]ProductWarehouseQty, error)
+	// Saldo stok_inventori per produk+gudang
+	StockLevels(warehouseID *uint) ([]models.StokInventori, error)
+	// Sisa qty PO terbuka (draft, sent, approved, partially_received) per produk
+	OpenPurchaseQty() (map[uint]int, error)
+	FindProducts(ids []uint) ([]models.Produk, error)
+	FindWarehouses(ids []uint) ([]models.Gudang, error)
+}
+
+type reorderRepository struct {
+	db *gorm.DB
+}
+
+func NewReorderRepository(db *gorm.DB) ReorderRepository {
+	return &reorderRepository{db: db}
+}
+
+func (r *reorderRepository) SalesQtySince(since time.Time, warehouseID *uint) ([]ProductWarehouseQty, error) {
+	var rows []ProductWarehouseQty
+	q := r.db.Table("item_penjualan ip").
+		Select("ip.id_produk, ip.id_gudang, COALESCE(SUM(ip.jumlah), 0) as jumlah").
+		Joins("JOIN penjualan p ON p.id = ip.id_penjualan").
+		Where("p.status IN ? AND p.dibuat_pada >= ?", models.StatusPenjualanTerjual, since)
+	if warehouseID != nil {
+		q = q.Where("ip.id_gudang = ?", *warehouseID)
+	}
+	err := q.Group("ip.id_produk, ip.id_gudang").Scan(&rows).Error
+	return rows, err
+}
+
+func (r *reorderRepository) StockLevels(warehouseID *uint) ([]models.StokInventori, error) {
+	var stocks []models.StokInventori
+	q := r.db.Model(&models.StokInventori{})
+	if warehouseID != nil {
+		q = q.Where("id_gudang = ?", *warehouseID)
+	}
+	err := q.Find(&stocks).Error
+	return stocks, err
+}
+
+func (r *reorderRepository) OpenPurchaseQty() (map[uint]int, error) {
+	var rows []struct {
+		IDProduk uint
+		Jumlah   int
+	}
+	err := r.db.Table("item_pesanan_pembelian i").
+		Select("i.id_produk, COALESCE(SUM(i.jumlah - i.jumlah_diterima), 0) as jumlah").
+		Joins("JOIN pesanan_pembelian po ON po.id = i.id_po").
+		Where("po.status IN ?", []string{"draft", "sent", "approved", "partially_received"}).
+		Group("i.id_produk").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		result[row.IDProduk] = row.Jumlah
+	}
+	return result, nil
+}
+
+// FindProducts mengambil produk aktif beserta pemasoknya
+func (r *reorderRepository) FindProducts(ids []uint) ([]models.Produk, error) {
+	var products []models.Produk
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := r.db.Preload("Pemasok").Where("id IN ? AND aktif = ?", ids, true).Find(&products).Error
+	return products, err
+}
+
+func (r *reorderRepository) FindWarehouses(ids []uint) ([]models.Gudang, error) {
+	var warehouses []models.Gudang
+	if len(ids) == 0 {
+		return warehouses, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&warehouses).Error
+	return warehouses, err
+}
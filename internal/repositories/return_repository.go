@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"real-erp-mebel/be/internal/dto"
 	"real-erp-mebel/be/internal/models"
 	"time"
@@ -8,18 +9,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrReturStatusChanged dikembalikan saat status retur sudah diubah transaksi lain (approve / reject / complete bersamaan)
+var ErrReturStatusChanged = errors.New("status retur sudah berubah, muat ulang data retur")
+
 type ReturnRepository interface {
 	// Retur Penjualan
 	CreateReturPenjualan(tx *gorm.DB, retur *models.ReturPenjualan) error
 	FindReturPenjualanByID(id uint) (*models.ReturPenjualan, error)
 	FindAllReturPenjualan(req *dto.ListReturPenjualanRequest) ([]models.ReturPenjualan, int64, error)
-	UpdateStatusReturPenjualan(tx *gorm.DB, id uint, status string, approvedBy uint) error
+	// Update hanya berlaku jika status masih fromStatus, selain itu ErrReturStatusChanged
+	UpdateStatusReturPenjualan(tx *gorm.DB, id uint, fromStatus, status string, approvedBy uint) error
+	RejectReturPenjualan(tx *gorm.DB, id, rejectedBy uint, alasan string) error
+	// Catat shift kasir yang membayar refund tunai
+	AssignShiftReturPenjualan(tx *gorm.DB, id, shiftID uint) error
 
 	// Retur Pembelian
 	CreateReturPembelian(tx *gorm.DB, retur *models.ReturPembelian) error
 	FindReturPembelianByID(id uint) (*models.ReturPembelian, error)
 	FindAllReturPembelian(req *dto.ListReturPembelianRequest) ([]models.ReturPembelian, int64, error)
-	UpdateStatusReturPembelian(tx *gorm.DB, id uint, status string, approvedBy uint) error
+	// Update hanya berlaku jika status masih fromStatus, selain itu ErrReturStatusChanged
+	UpdateStatusReturPembelian(tx *gorm.DB, id uint, fromStatus, status string, approvedBy uint) error
+	RejectReturPembelian(tx *gorm.DB, id, rejectedBy uint, alasan string) error
+
+	// Stok Karantina
+	FindAllStokKarantina(req *dto.ListStokKarantinaRequest) ([]models.StokBatch, int64, error)
+	CreateDisposisiKarantina(tx *gorm.DB, disposisi *models.DisposisiKarantina) error
+	FindAllDisposisiKarantina(req *dto.ListDisposisiKarantinaRequest) ([]models.DisposisiKarantina, int64, error)
 
 	// Utility
 	BeginTx() *gorm.DB
@@ -103,17 +118,36 @@ func (r *returnRepository) FindAllReturPenjualan(req *dto.ListReturPenjualanRequ
 	return returs, total, err
 }
 
-func (r *returnRepository) UpdateStatusReturPenjualan(tx *gorm.DB, id uint, status string, approvedBy uint) error {
+func (r *returnRepository) UpdateStatusReturPenjualan(tx *gorm.DB, id uint, fromStatus, status string, approvedBy uint) error {
 	now := time.Now()
 	updates := map[string]interface{}{
 		"status":          status,
 		"diperbarui_pada": now,
 	}
-	if status == "approved" || status == "completed" {
+	switch status {
+	case "approved":
 		updates["disetujui_oleh"] = approvedBy
 		updates["disetujui_pada"] = now
+	case "completed":
+		updates["diselesaikan_oleh"] = approvedBy
+		updates["diselesaikan_pada"] = now
 	}
-	return tx.Model(&models.ReturPenjualan{}).Where("id = ?", id).Updates(updates).Error
+	return guardedReturUpdate(tx.Model(&models.ReturPenjualan{}).Where("id = ? AND status = ?", id, fromStatus).Updates(updates))
+}
+
+func (r *returnRepository) RejectReturPenjualan(tx *gorm.DB, id, rejectedBy uint, alasan string) error {
+	now := time.Now()
+	return guardedReturUpdate(tx.Model(&models.ReturPenjualan{}).Where("id = ? AND status = ?", id, "pending").Updates(map[string]interface{}{
+		"status":           "rejected",
+		"alasan_penolakan": alasan,
+		"ditolak_oleh":     rejectedBy,
+		"ditolak_pada":     now,
+		"diperbarui_pada":  now,
+	}))
+}
+
+func (r *returnRepository) AssignShiftReturPenjualan(tx *gorm.DB, id, shiftID uint) error {
+	return tx.Model(&models.ReturPenjualan{}).Where("id = ?", id).Update("id_shift", shiftID).Error
 }
 
 // ===========================
@@ -177,15 +211,139 @@ func (r *returnRepository) FindAllReturPembelian(req *dto.ListReturPembelianRequ
 	return returs, total, err
 }
 
-func (r *returnRepository) UpdateStatusReturPembelian(tx *gorm.DB, id uint, status string, approvedBy uint) error {
+func (r *returnRepository) UpdateStatusReturPembelian(tx *gorm.DB, id uint, fromStatus, status string, approvedBy uint) error {
 	now := time.Now()
 	updates := map[string]interface{}{
 		"status":          status,
 		"diperbarui_pada": now,
 	}
-	if status == "approved" || status == "completed" {
+	switch status {
+	case "approved":
 		updates["disetujui_oleh"] = approvedBy
 		updates["disetujui_pada"] = now
+	case "completed":
+		updates["diselesaikan_oleh"] = approvedBy
+		updates["diselesaikan_pada"] = now
+	}
+	return guardedReturUpdate(tx.Model(&models.ReturPembelian{}).Where("id = ? AND status = ?", id, fromStatus).Updates(updates))
+}
+
+func (r *returnRepository) RejectReturPembelian(tx *gorm.DB, id, rejectedBy uint, alasan string) error {
+	now := time.Now()
+	return guardedReturUpdate(tx.Model(&models.ReturPembelian{}).Where("id = ? AND status = ?", id, "pending").Updates(map[string]interface{}{
+		"status":           "rejected",
+		"alasan_penolakan": alasan,
+		"ditolak_oleh":     rejectedBy,
+		"ditolak_pada":     now,
+		"diperbarui_pada":  now,
+	}))
+}
+
+// guardedReturUpdate mengubah update tanpa baris terdampak (status sudah berubah) menjadi ErrReturStatusChanged
+func guardedReturUpdate(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReturStatusChanged
+	}
+	return nil
+}
+
+// ===========================
+// STOK KARANTINA
+// ===========================
+
+// FindAllStokKarantina mengambil batch retur yang belum aktif (karantina / perbaikan) dan masih bersisa
+func (r *returnRepository) FindAllStokKarantina(req *dto.ListStokKarantinaRequest) ([]models.StokBatch, int64, error) {
+	var batches []models.StokBatch
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	tipe := []string{"retur_penjualan", "perbaikan"}
+	switch req.Status {
+	case "karantina":
+		tipe = []string{"retur_penjualan"}
+	case "perbaikan":
+		tipe = []string{"perbaikan"}
+	}
+
+	query := r.db.Model(&models.StokBatch{}).
+		Where("tipe_referensi IN ?", tipe).
+		Where("aktif = ?", false).
+		Where("jumlah_saat_ini > ?", 0)
+
+	if req.IDProduk != nil {
+		query = query.Where("id_produk = ?", *req.IDProduk)
+	}
+	if req.IDGudang != nil {
+		query = query.Where("id_gudang = ?", *req.IDGudang)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	return tx.Model(&models.ReturPembelian{}).Where("id = ?", id).Updates(updates).Error
+
+	err := query.
+		Preload("Produk").
+		Preload("Gudang").
+		Order("tanggal_masuk ASC").
+		Limit(limit).Offset(offset).
+		Find(&batches).Error
+
+	return batches, total, err
+}
+
+func (r *returnRepository) CreateDisposisiKarantina(tx *gorm.DB, disposisi *models.DisposisiKarantina) error {
+	return tx.Create(disposisi).Error
+}
+
+func (r *returnRepository) FindAllDisposisiKarantina(req *dto.ListDisposisiKarantinaRequest) ([]models.DisposisiKarantina, int64, error) {
+	var records []models.DisposisiKarantina
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.DisposisiKarantina{})
+
+	if req.IDBatch != nil {
+		query = query.Where("id_batch = ?", *req.IDBatch)
+	}
+	if req.IDProduk != nil {
+		query = query.Where("id_produk = ?", *req.IDProduk)
+	}
+	if req.Aksi != "" {
+		query = query.Where("aksi = ?", req.Aksi)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Produk").
+		Preload("Gudang").
+		Preload("DiprosesOlehPengguna").
+		Order("dibuat_pada DESC").
+		Limit(limit).Offset(offset).
+		Find(&records).Error
+
+	return records, total, err
 }
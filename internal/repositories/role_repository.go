@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository adalah interface untuk role & permission repository
+type RoleRepository interface {
+	BeginTx() *gorm.DB
+	Create(tx *gorm.DB, role *models.PeranPengguna) error
+	Update(tx *gorm.DB, role *models.PeranPengguna) error
+	Delete(tx *gorm.DB, id uint) error
+	ReplacePermissions(tx *gorm.DB, roleID uint, permissionIDs []uint) error
+	FindByID(id uint) (*models.PeranPengguna, error)
+	FindByKode(kode string) (*models.PeranPengguna, error)
+	FindAll() ([]models.PeranPengguna, error)
+	FindAllPermissions() ([]models.Izin, error)
+	FindPermissionsByKode(kode []string) ([]models.Izin, error)
+	FindPermissionCodes(roleKode string) ([]string, bool, error)
+	CountUsers(roleKode string) (int64, error)
+	SeedDefaults(permissions []models.Izin, rolePermissions map[string][]string) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository membuat instance RoleRepository baru
+func NewRoleRepository() RoleRepository {
+	return &roleRepository{
+		db: database.DB,
+	}
+}
+
+func (r *roleRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *roleRepository) Create(tx *gorm.DB, role *models.PeranPengguna) error {
+	return tx.Create(role).Error
+}
+
+func (r *roleRepository) Update(tx *gorm.DB, role *models.PeranPengguna) error {
+	return tx.Omit("Izin").Save(role).Error
+}
+
+func (r *roleRepository) Delete(tx *gorm.DB, id uint) error {
+	if err := tx.Where("id_peran = ?", id).Delete(&models.PeranIzin{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.PeranPengguna{}, id).Error
+}
+
+func (r *roleRepository) ReplacePermissions(tx *gorm.DB, roleID uint, permissionIDs []uint) error {
+	if err := tx.Where("id_peran = ?", roleID).Delete(&models.PeranIzin{}).Error; err != nil {
+		return err
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]models.PeranIzin, len(permissionIDs))
+	for i, id := range permissionIDs {
+		rows[i] = models.PeranIzin{IDPeran: roleID, IDIzin: id}
+	}
+	return tx.Create(&rows).Error
+}
+
+func (r *roleRepository) FindByID(id uint) (*models.PeranPengguna, error) {
+	var role models.PeranPengguna
+	err := r.db.Preload("Izin.Izin").First(&role, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByKode(kode string) (*models.PeranPengguna, error) {
+	var role models.PeranPengguna
+	err := r.db.Preload("Izin.Izin").Where("kode = ?", kode).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindAll() ([]models.PeranPengguna, error) {
+	var roles []models.PeranPengguna
+	err := r.db.Preload("Izin.Izin").Order("sistem DESC, kode ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindAllPermissions() ([]models.Izin, error) {
+	var permissions []models.Izin
+	err := r.db.Order("modul ASC, kode ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepository) FindPermissionsByKode(kode []string) ([]models.Izin, error) {
+	var permissions []models.Izin
+	err := r.db.Where("kode IN ?", kode).Find(&permissions).Error
+	return permissions, err
+}
+
+// FindPermissionCodes mengembalikan kode permission role aktif; role nonaktif tidak punya permission
+func (r *roleRepository) FindPermissionCodes(roleKode string) ([]string, bool, error) {
+	var role models.PeranPengguna
+	err := r.db.Select("id", "aktif").Where("kode = ?", roleKode).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if !role.Aktif {
+		return []string{}, true, nil
+	}
+
+	codes := []string{}
+	err = r.db.Model(&models.PeranIzin{}).
+		Joins("JOIN izin ON izin.id = peran_izin.id_izin").
+		Where("peran_izin.id_peran = ?", role.ID).
+		Pluck("izin.kode", &codes).Error
+	if err != nil {
+		return nil, false, err
+	}
+	return codes, true, nil
+}
+
+func (r *roleRepository) CountUsers(roleKode string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("peran = ?", roleKode).Count(&count).Error
+	return count, err
+}
+
+// SeedDefaults memastikan katalog permission dan role bawaan ada.
+// Permission role bawaan yang sudah ada tidak ditimpa (bisa saja sudah diubah admin).
+func (r *roleRepository) SeedDefaults(permissions []models.Izin, rolePermissions map[string][]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		permissionIDs := make(map[string]uint, len(permissions))
+		for _, p := range permissions {
+			izin := p
+			if err := tx.Where(models.Izin{Kode: p.Kode}).
+				Assign(models.Izin{Modul: p.Modul, Deskripsi: p.Deskripsi}).
+				FirstOrCreate(&izin).Error; err != nil {
+				return err
+			}
+			permissionIDs[izin.Kode] = izin.ID
+		}
+
+		for kode, codes := range rolePermissions {
+			var existing int64
+			if err := tx.Model(&models.PeranPengguna{}).Where("kode = ?", kode).Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				continue
+			}
+
+			role := models.PeranPengguna{Kode: kode, Nama: roleNameFromKode(kode), Sistem: true, Aktif: true}
+			for _, code := range codes {
+				if id, ok := permissionIDs[code]; ok {
+					role.Izin = append(role.Izin, models.PeranIzin{IDIzin: id})
+				}
+			}
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// roleNameFromKode membuat nama tampilan dari kode role (admin_gudang → Admin Gudang)
+func roleNameFromKode(kode string) string {
+	words := strings.Fields(strings.ReplaceAll(kode, "_", " "))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
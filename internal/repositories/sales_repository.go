@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SalesRepository interface {
@@ -21,6 +22,24 @@ type SalesRepository interface {
 	// Update path bukti bayar pada penjualan
 	UpdateBuktiBayar(id uint, filePath string) error
 
+	// Ambil penjualan dalam TX dengan row lock beserta batch usage (untuk void)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*models.Penjualan, error)
+
+	// Hitung retur penjualan yang belum ditolak untuk transaksi ini
+	CountActiveReturns(tx *gorm.DB, saleID uint) (int64, error)
+
+	// Tandai penjualan sebagai voided beserta alasan dan pembatal
+	MarkVoided(tx *gorm.DB, id, userID uint, alasan string, at time.Time) error
+
+	// Pesanan (Mode 2): update kolom header, simpan HPP item saat stok keluar, catat pembayaran
+	UpdateFields(tx *gorm.DB, id uint, fields map[string]interface{}) error
+	UpdateItemCOGS(tx *gorm.DB, item *models.ItemPenjualan) error
+	CreateBatchUsage(tx *gorm.DB, usages []models.ItemPenjualanBatch) error
+	CreatePayment(tx *gorm.DB, payment *models.PembayaranPenjualan) error
+
+	// Buat baris pembayaran untuk penjualan POS lama yang belum punya (sebelum split tender)
+	BackfillPOSPaymentLines() (int64, error)
+
 	// Begin transaction
 	BeginTx() *gorm.DB
 }
@@ -46,11 +65,14 @@ func (r *salesRepository) FindByID(id uint) (*models.Penjualan, error) {
 	err := r.db.
 		Preload("Gudang").
 		Preload("Kasir").
+		Preload("DibatalkanOlehPengguna").
 		Preload("Items").
 		Preload("Items.Produk").
 		Preload("Items.Gudang").
 		Preload("Items.BatchUsage").
 		Preload("Items.BatchUsage.Batch").
+		Preload("Pembayaran", func(db *gorm.DB) *gorm.DB { return db.Order("dibuat_pada ASC") }).
+		Preload("Pembayaran.DiterimaOlehPengguna").
 		First(&sale, id).Error
 	if err != nil {
 		return nil, err
@@ -82,9 +104,20 @@ func (r *salesRepository) FindAll(req *dto.ListSalesRequest) ([]models.Penjualan
 	if req.IDKasir != nil {
 		query = query.Where("id_kasir = ?", *req.IDKasir)
 	}
-	// Filter metode pembayaran
+	// Filter pelanggan
+	if req.IDPelanggan != nil {
+		query = query.Where("id_pelanggan = ?", *req.IDPelanggan)
+	}
+	// Filter tipe (pos / pesanan) & status
+	if req.TipePenjualan != "" {
+		query = query.Where("tipe_penjualan = ?", req.TipePenjualan)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	// Filter metode pembayaran (dari baris pembayaran, termasuk split tender)
 	if req.MetodePembayaran != "" {
-		query = query.Where("metode_pembayaran = ?", req.MetodePembayaran)
+		query = query.Where("id IN (SELECT id_penjualan FROM pembayaran_penjualan WHERE metode_pembayaran = ?)", req.MetodePembayaran)
 	}
 	// Filter tanggal
 	if req.TanggalDari != nil {
@@ -120,3 +153,69 @@ func (r *salesRepository) UpdateBuktiBayar(id uint, filePath string) error {
 			"diperbarui_pada": time.Now(),
 		}).Error
 }
+
+func (r *salesRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*models.Penjualan, error) {
+	var sale models.Penjualan
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		Preload("Items.BatchUsage").
+		First(&sale, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+func (r *salesRepository) CountActiveReturns(tx *gorm.DB, saleID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&models.ReturPenjualan{}).
+		Where("id_penjualan = ? AND status <> ?", saleID, "rejected").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *salesRepository) MarkVoided(tx *gorm.DB, id, userID uint, alasan string, at time.Time) error {
+	return tx.Model(&models.Penjualan{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":            "voided",
+			"alasan_pembatalan": alasan,
+			"dibatalkan_oleh":   userID,
+			"dibatalkan_pada":   at,
+			"diperbarui_pada":   at,
+		}).Error
+}
+
+func (r *salesRepository) UpdateFields(tx *gorm.DB, id uint, fields map[string]interface{}) error {
+	return tx.Model(&models.Penjualan{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *salesRepository) UpdateItemCOGS(tx *gorm.DB, item *models.ItemPenjualan) error {
+	return tx.Model(&models.ItemPenjualan{}).
+		Where("id = ?", item.ID).
+		Updates(map[string]interface{}{
+			"harga_modal": item.HargaModal,
+			"total_modal": item.TotalModal,
+		}).Error
+}
+
+func (r *salesRepository) CreateBatchUsage(tx *gorm.DB, usages []models.ItemPenjualanBatch) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	return tx.Create(&usages).Error
+}
+
+func (r *salesRepository) CreatePayment(tx *gorm.DB, payment *models.PembayaranPenjualan) error {
+	return tx.Create(payment).Error
+}
+
+// BackfillPOSPaymentLines menyalin metode & total penjualan POS lama ke satu baris pembayaran (idempotent)
+func (r *salesRepository) BackfillPOSPaymentLines() (int64, error) {
+	result := r.db.Exec(`INSERT INTO pembayaran_penjualan (id_penjualan, jenis, metode_pembayaran, jumlah, diterima_oleh, dibuat_pada)
+		SELECT p.id, 'pos', p.metode_pembayaran, p.total, p.id_kasir, p.dibuat_pada
+		FROM penjualan p
+		WHERE p.tipe_penjualan = 'pos'
+		AND NOT EXISTS (SELECT 1 FROM pembayaran_penjualan pp WHERE pp.id_penjualan = p.id)`)
+	return result.RowsAffected, result.Error
+}
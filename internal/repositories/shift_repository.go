@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"real-erp-mebel/be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShiftPaymentTotal adalah agregat pembayaran yang diterima dalam satu shift per metode
+type ShiftPaymentTotal struct {
+	MetodePembayaran string
+	JumlahTransaksi  int64
+	Total            float64
+}
+
+// ShiftSalesSummary adalah ringkasan transaksi penjualan yang dibuat dalam satu shift
+type ShiftSalesSummary struct {
+	JumlahTransaksi int64
+	TotalPenjualan  float64
+	TotalDiskon     float64
+	JumlahVoid      int64
+	NilaiVoid       float64
+}
+
+// ShiftRefundTotal adalah agregat refund tunai retur penjualan dalam satu shift
+type ShiftRefundTotal struct {
+	JumlahRetur int64
+	Total       float64
+}
+
+type ShiftRepository interface {
+	Create(tx *gorm.DB, shift *models.ShiftKasir) error
+	FindByID(id uint) (*models.ShiftKasir, error)
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*models.ShiftKasir, error)
+	// Shift yang sedang buka milik kasir (nil jika tidak ada). tx nil = tanpa lock
+	FindOpenByKasir(tx *gorm.DB, kasirID uint) (*models.ShiftKasir, error)
+	List(filters map[string]interface{}, page, limit int) ([]models.ShiftKasir, int64, error)
+	Close(tx *gorm.DB, shift *models.ShiftKasir, rekap []models.RekapShiftKasir) error
+
+	// Rekap transaksi shift (tx nil = dibaca di luar transaksi)
+	PaymentTotals(tx *gorm.DB, shiftID uint) ([]ShiftPaymentTotal, error)
+	SalesSummary(tx *gorm.DB, shiftID uint) (*ShiftSalesSummary, error)
+	CashRefundTotal(tx *gorm.DB, shiftID uint) (*ShiftRefundTotal, error)
+
+	BeginTx() *gorm.DB
+}
+
+type shiftRepository struct {
+	db *gorm.DB
+}
+
+func NewShiftRepository(db *gorm.DB) ShiftRepository {
+	return &shiftRepository{db: db}
+}
+
+func (r *shiftRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+// conn memakai transaksi yang sedang berjalan jika ada
+func (r *shiftRepository) conn(tx *gorm.DB) *gorm.DB {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *shiftRepository) Create(tx *gorm.DB, shift *models.ShiftKasir) error {
+	return tx.Create(shift).Error
+}
+
+func (r *shiftRepository) FindByID(id uint) (*models.ShiftKasir, error) {
+	var shift models.ShiftKasir
+	err := r.db.
+		Preload("Kasir").
+		Preload("Gudang").
+		Preload("DitutupOlehUser").
+		Preload("Rekap", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&shift, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func (r *shiftRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*models.ShiftKasir, error) {
+	var shift models.ShiftKasir
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&shift, id).Error; err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// FindOpenByKasir memakai FOR SHARE agar tutup shift menunggu transaksi yang sedang berjalan di shift tersebut
+func (r *shiftRepository) FindOpenByKasir(tx *gorm.DB, kasirID uint) (*models.ShiftKasir, error) {
+	var shifts []models.ShiftKasir
+	query := r.db
+	if tx != nil {
+		query = tx.Clauses(clause.Locking{Strength: "SHARE"})
+	}
+	err := query.
+		Where("id_kasir = ? AND status = ?", kasirID, "open").
+		Limit(1).
+		Find(&shifts).Error
+	if err != nil || len(shifts) == 0 {
+		return nil, err
+	}
+	return &shifts[0], nil
+}
+
+func (r *shiftRepository) List(filters map[string]interface{}, page, limit int) ([]models.ShiftKasir, int64, error) {
+	var shifts []models.ShiftKasir
+	var total int64
+
+	query := r.db.Model(&models.ShiftKasir{})
+
+	if kasirID, ok := filters["id_kasir"].(uint); ok && kasirID > 0 {
+		query = query.Where("id_kasir = ?", kasirID)
+	}
+	if gudangID, ok := filters["id_gudang"].(uint); ok && gudangID > 0 {
+		query = query.Where("id_gudang = ?", gudangID)
+	}
+	if status, ok := filters["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if dari, ok := filters["tanggal_dari"].(string); ok && dari != "" {
+		query = query.Where("DATE(dibuka_pada) >= ?", dari)
+	}
+	if sampai, ok := filters["tanggal_sampai"].(string); ok && sampai != "" {
+		query = query.Where("DATE(dibuka_pada) <= ?", sampai)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Preload("Kasir").
+		Preload("Gudang").
+		Order("dibuka_pada DESC").
+		Offset(offset).Limit(limit).
+		Find(&shifts).Error
+	return shifts, total, err
+}
+
+// Close menyimpan hasil rekonsiliasi & ringkasan transaksi shift beserta rekap per metode pembayaran
+func (r *shiftRepository) Close(tx *gorm.DB, shift *models.ShiftKasir, rekap []models.RekapShiftKasir) error {
+	if err := tx.Model(&models.ShiftKasir{}).Where("id = ?", shift.ID).Updates(map[string]interface{}{
+		"status":           shift.Status,
+		"kas_diharapkan":   shift.KasDiharapkan,
+		"kas_dihitung":     shift.KasDihitung,
+		"selisih_kas":      shift.SelisihKas,
+		"jumlah_transaksi": shift.JumlahTransaksi,
+		"total_penjualan":  shift.TotalPenjualan,
+		"total_diskon":     shift.TotalDiskon,
+		"jumlah_void":      shift.JumlahVoid,
+		"nilai_void":       shift.NilaiVoid,
+		"jumlah_refund":    shift.JumlahRefund,
+		"refund_tunai":     shift.RefundTunai,
+		"catatan_tutup":    shift.CatatanTutup,
+		"ditutup_pada":     shift.DitutupPada,
+		"ditutup_oleh":     shift.DitutupOleh,
+		"diperbarui_pada":  shift.DiperbaruiPada,
+	}).Error; err != nil {
+		return err
+	}
+	if len(rekap) == 0 {
+		return nil
+	}
+	return tx.Create(&rekap).Error
+}
+
+// PaymentTotals menjumlahkan baris pembayaran shift per metode; pembayaran penjualan yang di-void tidak dihitung
+func (r *shiftRepository) PaymentTotals(tx *gorm.DB, shiftID uint) ([]ShiftPaymentTotal, error) {
+	var rows []ShiftPaymentTotal
+	err := r.conn(tx).Table("pembayaran_penjualan pp").
+		Select("pp.metode_pembayaran, COUNT(*) as jumlah_transaksi, COALESCE(SUM(pp.jumlah), 0) as total").
+		Joins("JOIN penjualan p ON p.id = pp.id_penjualan").
+		Where("pp.id_shift = ? AND p.status <> ?", shiftID, "voided").
+		Group("pp.metode_pembayaran").
+		Order("pp.metode_pembayaran ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *shiftRepository) SalesSummary(tx *gorm.DB, shiftID uint) (*ShiftSalesSummary, error) {
+	var summary ShiftSalesSummary
+	err := r.conn(tx).Model(&models.Penjualan{}).
+		Select("COUNT(*) FILTER (WHERE status <> 'voided') as jumlah_transaksi, "+
+			"COALESCE(SUM(total) FILTER (WHERE status <> 'voided'), 0) as total_penjualan, "+
+			"COALESCE(SUM(jumlah_diskon) FILTER (WHERE status <> 'voided'), 0) as total_diskon, "+
+			"COUNT(*) FILTER (WHERE status = 'voided') as jumlah_void, "+
+			"COALESCE(SUM(total) FILTER (WHERE status = 'voided'), 0) as nilai_void").
+		Where("id_shift = ?", shiftID).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *shiftRepository) CashRefundTotal(tx *gorm.DB, shiftID uint) (*ShiftRefundTotal, error) {
+	var refund ShiftRefundTotal
+	err := r.conn(tx).Model(&models.ReturPenjualan{}).
+		Select("COUNT(*) as jumlah_retur, COALESCE(SUM(total), 0) as total").
+		Where("id_shift = ? AND status = ? AND metode_pengembalian = ?", shiftID, "completed", "cash").
+		Scan(&refund).Error
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
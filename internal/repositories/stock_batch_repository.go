@@ -24,6 +24,9 @@ type StockBatchRepository interface {
 	// Get batch by ID
 	FindByID(batchID uint) (*models.StokBatch, error)
 
+	// Get batch by ID dalam TX dengan row lock (untuk restore / disposisi batch spesifik)
+	FindByIDForUpdate(tx *gorm.DB, batchID uint) (*models.StokBatch, error)
+
 	// Ambil movement opname terakhir per batch
 	GetLatestOpnameByBatchIDs(batchIDs []uint) (map[uint]models.PergerakanStok, error)
 
@@ -98,6 +101,15 @@ func (r *stockBatchRepository) FindByID(batchID uint) (*models.StokBatch, error)
 	return &batch, nil
 }
 
+func (r *stockBatchRepository) FindByIDForUpdate(tx *gorm.DB, batchID uint) (*models.StokBatch, error) {
+	var batch models.StokBatch
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&batch, batchID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
 func (r *stockBatchRepository) GetLatestOpnameByBatchIDs(batchIDs []uint) (map[uint]models.PergerakanStok, error) {
 	result := make(map[uint]models.PergerakanStok)
 	if len(batchIDs) == 0 {
@@ -1,10 +1,12 @@
 package repositories
 
 import (
+	"real-erp-mebel/be/internal/dto"
 	"real-erp-mebel/be/internal/models"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StockRepository interface {
@@ -19,10 +21,30 @@ type StockRepository interface {
 	// Atomic Update (used within Tx)
 	UpdateStockBalance(tx *gorm.DB, productID, warehouseID uint, delta int) error
 	CreateStockMovement(tx *gorm.DB, movement *models.PergerakanStok) error
+	// Saldo terbaru + data produk di dalam Tx (termasuk perubahan yang belum di-commit)
+	FindStockBalance(tx *gorm.DB, productID, warehouseID uint) (*models.StokInventori, error)
 
 	// Headers (used within Tx)
 	CreateStockIn(tx *gorm.DB, header *models.BarangMasuk) error
 	CreateStockOut(tx *gorm.DB, header *models.BarangKeluar) error
+
+	// Dokumen barang masuk (approval workflow)
+	FindStockInByID(id uint) (*models.BarangMasuk, error)
+	FindStockInByIDForUpdate(tx *gorm.DB, id uint) (*models.BarangMasuk, error)
+	FindAllStockIn(req *dto.ListStockInRequest) ([]models.BarangMasuk, int64, error)
+	UpdateStockInStatus(tx *gorm.DB, id uint, status string, extra map[string]interface{}) error
+	FindStockInItemForBatch(tx *gorm.DB, stockInID, productID uint) (*models.ItemBarangMasuk, error)
+
+	// Dokumen barang keluar
+	CreateStockOutItem(tx *gorm.DB, item *models.ItemBarangKeluar) error
+	FindStockOutByID(id uint) (*models.BarangKeluar, error)
+
+	// Peringatan stok menipis (dievaluasi setiap saldo berubah)
+	FindActiveStockAlert(tx *gorm.DB, productID, warehouseID uint) (*models.PeringatanStok, error)
+	SaveStockAlert(tx *gorm.DB, alert *models.PeringatanStok) error
+	FindStockAlertByID(id uint) (*models.PeringatanStok, error)
+	FindStockAlertByIDForUpdate(tx *gorm.DB, id uint) (*models.PeringatanStok, error)
+	FindAllStockAlerts(req *dto.ListStockAlertRequest) ([]models.PeringatanStok, int64, error)
 }
 
 type stockRepository struct {
@@ -48,6 +70,17 @@ func (r *stockRepository) GetStockByProductAndWarehouse(productID, warehouseID u
 	return &stock, nil
 }
 
+func (r *stockRepository) FindStockBalance(tx *gorm.DB, productID, warehouseID uint) (*models.StokInventori, error) {
+	var stock models.StokInventori
+	err := tx.Preload("Produk").
+		Where("id_produk = ? AND id_gudang = ?", productID, warehouseID).
+		First(&stock).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
 func (r *stockRepository) GetStockByWarehouse(warehouseID uint, limit, offset int) ([]models.StokInventori, int64, error) {
 	var stocks []models.StokInventori
 	var total int64
@@ -156,3 +189,191 @@ func (r *stockRepository) CreateStockIn(tx *gorm.DB, header *models.BarangMasuk)
 func (r *stockRepository) CreateStockOut(tx *gorm.DB, header *models.BarangKeluar) error {
 	return tx.Create(header).Error
 }
+
+func (r *stockRepository) FindStockInByID(id uint) (*models.BarangMasuk, error) {
+	var header models.BarangMasuk
+	err := r.db.
+		Preload("Pemasok").
+		Preload("DiterimaOlehPengguna").
+		Preload("DisetujuiOlehPengguna").
+		Preload("DitolakOlehPengguna").
+		Preload("Items").
+		Preload("Items.Produk").
+		Preload("Items.Gudang").
+		First(&header, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+func (r *stockRepository) FindStockInByIDForUpdate(tx *gorm.DB, id uint) (*models.BarangMasuk, error) {
+	var header models.BarangMasuk
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		First(&header, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+func (r *stockRepository) FindAllStockIn(req *dto.ListStockInRequest) ([]models.BarangMasuk, int64, error) {
+	var headers []models.BarangMasuk
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.BarangMasuk{})
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.SupplierID != nil {
+		query = query.Where("id_supplier = ?", *req.SupplierID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Pemasok").
+		Preload("DiterimaOlehPengguna").
+		Preload("Items").
+		Order("dibuat_pada DESC").
+		Limit(limit).Offset(offset).
+		Find(&headers).Error
+
+	return headers, total, err
+}
+
+func (r *stockRepository) UpdateStockInStatus(tx *gorm.DB, id uint, status string, extra map[string]interface{}) error {
+	updates := map[string]interface{}{
+		"status":          status,
+		"diperbarui_pada": time.Now(),
+	}
+	for k, v := range extra {
+		updates[k] = v
+	}
+	return tx.Model(&models.BarangMasuk{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// FindStockInItemForBatch mencari baris barang masuk asal sebuah batch (untuk link FIFO di barang keluar)
+func (r *stockRepository) FindStockInItemForBatch(tx *gorm.DB, stockInID, productID uint) (*models.ItemBarangMasuk, error) {
+	var item models.ItemBarangMasuk
+	err := tx.Where("id_stock_in = ? AND id_produk = ?", stockInID, productID).
+		Order("id ASC").
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *stockRepository) CreateStockOutItem(tx *gorm.DB, item *models.ItemBarangKeluar) error {
+	return tx.Create(item).Error
+}
+
+func (r *stockRepository) FindStockOutByID(id uint) (*models.BarangKeluar, error) {
+	var header models.BarangKeluar
+	err := r.db.
+		Preload("DibuatOlehPengguna").
+		Preload("Items").
+		Preload("Items.Produk").
+		Preload("Items.Gudang").
+		First(&header, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
+// FindActiveStockAlert mengambil peringatan open/acknowledged untuk produk+gudang (dikunci di dalam Tx).
+// Mengembalikan nil tanpa error jika tidak ada peringatan aktif (kasus paling umum).
+func (r *stockRepository) FindActiveStockAlert(tx *gorm.DB, productID, warehouseID uint) (*models.PeringatanStok, error) {
+	var alerts []models.PeringatanStok
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id_produk = ? AND id_gudang = ? AND status <> ?", productID, warehouseID, "resolved").
+		Limit(1).
+		Find(&alerts).Error
+	if err != nil || len(alerts) == 0 {
+		return nil, err
+	}
+	return &alerts[0], nil
+}
+
+func (r *stockRepository) SaveStockAlert(tx *gorm.DB, alert *models.PeringatanStok) error {
+	return tx.Omit(clause.Associations).Save(alert).Error
+}
+
+func (r *stockRepository) FindStockAlertByID(id uint) (*models.PeringatanStok, error) {
+	var alert models.PeringatanStok
+	err := r.db.Preload("Produk").Preload("Gudang").Preload("DikonfirmasiOlehPengguna").First(&alert, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *stockRepository) FindStockAlertByIDForUpdate(tx *gorm.DB, id uint) (*models.PeringatanStok, error) {
+	var alert models.PeringatanStok
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&alert, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *stockRepository) FindAllStockAlerts(req *dto.ListStockAlertRequest) ([]models.PeringatanStok, int64, error) {
+	var alerts []models.PeringatanStok
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.PeringatanStok{})
+
+	switch req.Status {
+	case "", "active":
+		query = query.Where("status <> ?", "resolved")
+	case "all":
+	default:
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.WarehouseID != nil {
+		query = query.Where("id_gudang = ?", *req.WarehouseID)
+	}
+	if req.ProductID != nil {
+		query = query.Where("id_produk = ?", *req.ProductID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Produk").
+		Preload("Gudang").
+		Preload("DikonfirmasiOlehPengguna").
+		Order("dibuka_pada DESC").
+		Limit(limit).Offset(offset).
+		Find(&alerts).Error
+
+	return alerts, total, err
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockTransferRepository interface {
+	// Buat dokumen transfer (header + items) dalam TX
+	Create(tx *gorm.DB, transfer *models.TransferStok) error
+
+	// Ambil detail transfer dengan semua relasi
+	FindByID(id uint) (*models.TransferStok, error)
+
+	// Ambil transfer dalam TX dengan row lock beserta breakdown batch
+	FindByIDForUpdate(tx *gorm.DB, id uint) (*models.TransferStok, error)
+
+	// List transfer dengan filter dan pagination
+	FindAll(req *dto.ListStockTransferRequest) ([]models.TransferStok, int64, error)
+
+	// Update status beserta kolom tambahan (dikirim_oleh, diterima_pada, dll)
+	UpdateStatus(tx *gorm.DB, id uint, status string, extra map[string]interface{}) error
+
+	// Catat breakdown batch asal saat transfer dikirim
+	CreateBatchUsage(tx *gorm.DB, usage *models.ItemTransferStokBatch) error
+
+	// Set batch tujuan saat transfer diterima
+	SetTargetBatch(tx *gorm.DB, usageID, batchID uint) error
+
+	// Begin transaction
+	BeginTx() *gorm.DB
+}
+
+type stockTransferRepository struct {
+	db *gorm.DB
+}
+
+func NewStockTransferRepository(db *gorm.DB) StockTransferRepository {
+	return &stockTransferRepository{db: db}
+}
+
+func (r *stockTransferRepository) BeginTx() *gorm.DB {
+	return r.db.Begin()
+}
+
+func (r *stockTransferRepository) Create(tx *gorm.DB, transfer *models.TransferStok) error {
+	return tx.Create(transfer).Error
+}
+
+func (r *stockTransferRepository) FindByID(id uint) (*models.TransferStok, error) {
+	var transfer models.TransferStok
+	err := r.db.
+		Preload("GudangAsal").
+		Preload("GudangTujuan").
+		Preload("DibuatOlehPengguna").
+		Preload("DikirimOlehPengguna").
+		Preload("DiterimaOlehPengguna").
+		Preload("DibatalkanOlehPengguna").
+		Preload("Items").
+		Preload("Items.Produk").
+		Preload("Items.Batches").
+		First(&transfer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *stockTransferRepository) FindByIDForUpdate(tx *gorm.DB, id uint) (*models.TransferStok, error) {
+	var transfer models.TransferStok
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		Preload("Items.Batches").
+		Preload("Items.Batches.BatchAsal").
+		First(&transfer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *stockTransferRepository) FindAll(req *dto.ListStockTransferRequest) ([]models.TransferStok, int64, error) {
+	var transfers []models.TransferStok
+	var total int64
+
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	limit := req.Limit
+	if limit < 1 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.TransferStok{})
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.SourceWarehouseID != nil {
+		query = query.Where("id_gudang_asal = ?", *req.SourceWarehouseID)
+	}
+	if req.TargetWarehouseID != nil {
+		query = query.Where("id_gudang_tujuan = ?", *req.TargetWarehouseID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("GudangAsal").
+		Preload("GudangTujuan").
+		Preload("DibuatOlehPengguna").
+		Order("dibuat_pada DESC").
+		Limit(limit).Offset(offset).
+		Find(&transfers).Error
+
+	return transfers, total, err
+}
+
+func (r *stockTransferRepository) UpdateStatus(tx *gorm.DB, id uint, status string, extra map[string]interface{}) error {
+	updates := map[string]interface{}{
+		"status":          status,
+		"diperbarui_pada": time.Now(),
+	}
+	for k, v := range extra {
+		updates[k] = v
+	}
+	return tx.Model(&models.TransferStok{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *stockTransferRepository) CreateBatchUsage(tx *gorm.DB, usage *models.ItemTransferStokBatch) error {
+	return tx.Create(usage).Error
+}
+
+func (r *stockTransferRepository) SetTargetBatch(tx *gorm.DB, usageID, batchID uint) error {
+	return tx.Model(&models.ItemTransferStokBatch{}).Where("id = ?", usageID).Update("id_batch_tujuan", batchID).Error
+}
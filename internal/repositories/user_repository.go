@@ -17,6 +17,9 @@ type UserRepository interface {
 	UpdatePassword(id uint, hashedPassword string) error
 	Delete(id uint) error
 	Count(search, peran string, aktif *bool) (int64, error)
+	CountAll() (int64, error)
+	IncrementTokenVersion(id uint) error
+	CreateIfEmpty(user *models.User) (bool, error)
 }
 
 type userRepository struct {
@@ -119,3 +122,42 @@ func (r *userRepository) Count(search, peran string, aktif *bool) (int64, error)
 	err := query.Count(&count).Error
 	return count, err
 }
+
+// IncrementTokenVersion menaikkan versi token sehingga semua access token lama user ditolak
+func (r *userRepository) IncrementTokenVersion(id uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).
+		UpdateColumn("versi_token", gorm.Expr("versi_token + 1")).Error
+}
+
+// CountAll menghitung semua user termasuk yang sudah di-soft delete
+func (r *userRepository) CountAll() (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+
+// CreateIfEmpty membuat user hanya jika tabel pengguna masih kosong (bootstrap owner pertama).
+// Tabel dikunci selama transaksi agar dua request bootstrap tidak bisa lolos bersamaan.
+func (r *userRepository) CreateIfEmpty(user *models.User) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE pengguna IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&models.User{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
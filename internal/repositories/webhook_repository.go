@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	// Endpoint webhook
+	Create(webhook *models.LanggananWebhook) error
+	Update(webhook *models.LanggananWebhook) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.LanggananWebhook, error)
+	FindAll(page, limit int) ([]models.LanggananWebhook, int64, error)
+	FindActive(tx *gorm.DB) ([]models.LanggananWebhook, error)
+
+	// Log & antrean pengiriman
+	CreateDeliveries(tx *gorm.DB, deliveries []models.PengirimanWebhook) error
+	ClaimDueDeliveries(now, leaseUntil time.Time, limit int) ([]models.PengirimanWebhook, error)
+	UpdateDelivery(id uint, updates map[string]interface{}) error
+	FindDeliveryByID(id uint) (*models.PengirimanWebhook, error)
+	FindDeliveries(webhookID uint, status string, page, limit int) ([]models.PengirimanWebhook, int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(webhook *models.LanggananWebhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepository) Update(webhook *models.LanggananWebhook) error {
+	return r.db.Save(webhook).Error
+}
+
+// Delete menghapus webhook beserta log pengirimannya
+func (r *webhookRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id_webhook = ?", id).Delete(&models.PengirimanWebhook{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.LanggananWebhook{}, id).Error
+	})
+}
+
+func (r *webhookRepository) FindByID(id uint) (*models.LanggananWebhook, error) {
+	var webhook models.LanggananWebhook
+	if err := r.db.Preload("DibuatOlehPengguna").First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) FindAll(page, limit int) ([]models.LanggananWebhook, int64, error) {
+	var webhooks []models.LanggananWebhook
+	var total int64
+
+	query := r.db.Model(&models.LanggananWebhook{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("DibuatOlehPengguna").Order("id ASC").Offset(offset).Limit(limit).Find(&webhooks).Error
+	return webhooks, total, err
+}
+
+func (r *webhookRepository) FindActive(tx *gorm.DB) ([]models.LanggananWebhook, error) {
+	var webhooks []models.LanggananWebhook
+	err := tx.Where("aktif = ?", true).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// CreateDeliveries mengantrekan pengiriman; pasangan webhook+event yang sudah ada diabaikan
+func (r *webhookRepository) CreateDeliveries(tx *gorm.DB, deliveries []models.PengirimanWebhook) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ClaimDueDeliveries mengambil pengiriman pending yang jatuh tempo dan menundanya sampai leaseUntil,
+// sehingga worker lain tidak mengirim baris yang sama selama request HTTP berjalan.
+func (r *webhookRepository) ClaimDueDeliveries(now, leaseUntil time.Time, limit int) ([]models.PengirimanWebhook, error) {
+	var ids []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.PengirimanWebhook
+		if err := tx.Select("id").
+			Where("status = ? AND percobaan_berikutnya <= ?", "pending", now).
+			Order("id ASC").
+			Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		for _, d := range due {
+			ids = append(ids, d.ID)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&models.PengirimanWebhook{}).Where("id IN ?", ids).
+			Update("percobaan_berikutnya", leaseUntil).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []models.PengirimanWebhook
+	err = r.db.Preload("Webhook").Where("id IN ?", ids).Order("id ASC").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) UpdateDelivery(id uint, updates map[string]interface{}) error {
+	return r.db.Model(&models.PengirimanWebhook{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (r *webhookRepository) FindDeliveryByID(id uint) (*models.PengirimanWebhook, error) {
+	var delivery models.PengirimanWebhook
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveries(webhookID uint, status string, page, limit int) ([]models.PengirimanWebhook, int64, error) {
+	var deliveries []models.PengirimanWebhook
+	var total int64
+
+	query := r.db.Model(&models.PengirimanWebhook{}).Where("id_webhook = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,7 +14,12 @@ func SetupAuthRoutes(api *gin.RouterGroup) {
 	auth := api.Group("/auth")
 	{
 		auth.POST("/login", authHandler.Login)
-		auth.POST("/register", authHandler.Register)
+		auth.POST("/refresh", authHandler.Refresh)     // Rotasi refresh token
+		auth.POST("/logout", middleware.AuthMiddleware(), authHandler.Logout)
+		auth.POST("/register", authHandler.Register)   // Nonaktif kecuali AUTH_ALLOW_REGISTRATION=true
+		auth.GET("/bootstrap", authHandler.BootstrapStatus)
+		auth.POST("/bootstrap", authHandler.Bootstrap) // Owner pertama, hanya saat tabel pengguna kosong
+		auth.POST("/invitations/accept", authHandler.AcceptInvitation)
 	}
 }
 
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupFinanceRoutes mengatur routes untuk modul keuangan (hutang supplier)
+func SetupFinanceRoutes(api *gin.RouterGroup, db *gorm.DB) {
+	hutangRepo := repositories.NewHutangRepository(db)
+
+	financeService := services.NewFinanceService(hutangRepo)
+	financeHandler := handlers.NewFinanceHandler(financeService)
+
+	// Hutang Supplier (dibentuk otomatis dari penerimaan barang)
+	supplierDebts := api.Group("/supplier-debts")
+	supplierDebts.Use(middleware.AuthMiddleware())
+	{
+		supplierDebts.GET("", middleware.RequirePermission(middleware.PermFinanceRead), financeHandler.ListHutang)
+		supplierDebts.GET("/aging", middleware.RequirePermission(middleware.PermFinanceRead), financeHandler.GetAgingHutang)
+		supplierDebts.GET("/:id", middleware.RequirePermission(middleware.PermFinanceRead), financeHandler.GetHutang)
+		supplierDebts.POST("/:id/payments", middleware.RequirePermission(middleware.PermFinancePayment), financeHandler.CreatePembayaranHutang) // Cicilan / pelunasan + bukti bayar
+	}
+}
@@ -19,10 +19,10 @@ func SetupGudangRoutes(r *gin.RouterGroup) {
 	gudang := r.Group("/warehouses")
 	gudang.Use(middleware.AuthMiddleware())
 	{
-		gudang.POST("", gudangHandler.CreateGudang)
-		gudang.GET("", gudangHandler.ListGudangs)
-		gudang.GET("/:id", gudangHandler.GetGudang)
-		gudang.PUT("/:id", gudangHandler.UpdateGudang)
-		gudang.DELETE("/:id", gudangHandler.DeleteGudang)
+		gudang.POST("", middleware.RequirePermission(middleware.PermWarehouseWrite), gudangHandler.CreateGudang)
+		gudang.GET("", middleware.RequirePermission(middleware.PermWarehouseRead), gudangHandler.ListGudangs)
+		gudang.GET("/:id", middleware.RequirePermission(middleware.PermWarehouseRead), gudangHandler.GetGudang)
+		gudang.PUT("/:id", middleware.RequirePermission(middleware.PermWarehouseWrite), gudangHandler.UpdateGudang)
+		gudang.DELETE("/:id", middleware.RequirePermission(middleware.PermWarehouseWrite), gudangHandler.DeleteGudang)
 	}
 }
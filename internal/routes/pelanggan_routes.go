@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPelangganRoutes(api *gin.RouterGroup) {
+	repo := repositories.NewPelangganRepository(database.DB)
+	service := services.NewPelangganService(repo)
+	handler := handlers.NewPelangganHandler(service)
+
+	customers := api.Group("/customers")
+	customers.Use(middleware.AuthMiddleware())
+	{
+		customers.POST("", middleware.RequirePermission(middleware.PermCustomerWrite), handler.CreatePelanggan)
+		customers.GET("", middleware.RequirePermission(middleware.PermCustomerRead), handler.ListPelanggan)
+		customers.GET("/:id", middleware.RequirePermission(middleware.PermCustomerRead), handler.GetPelanggan) // Termasuk riwayat pembelian
+		customers.PUT("/:id", middleware.RequirePermission(middleware.PermCustomerWrite), handler.UpdatePelanggan)
+		customers.DELETE("/:id", middleware.RequirePermission(middleware.PermCustomerWrite), handler.DeletePelanggan)
+	}
+}
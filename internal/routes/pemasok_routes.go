@@ -18,10 +18,10 @@ func SetupPemasokRoutes(api *gin.RouterGroup) {
 	suppliers := api.Group("/suppliers")
 	suppliers.Use(middleware.AuthMiddleware())
 	{
-		suppliers.POST("", handler.CreatePemasok)
-		suppliers.GET("", handler.ListPemasok)
-		suppliers.GET("/:id", handler.GetPemasok)
-		suppliers.PUT("/:id", handler.UpdatePemasok)
-		suppliers.DELETE("/:id", handler.DeletePemasok)
+		suppliers.POST("", middleware.RequirePermission(middleware.PermSupplierWrite), handler.CreatePemasok)
+		suppliers.GET("", middleware.RequirePermission(middleware.PermSupplierRead), handler.ListPemasok)
+		suppliers.GET("/:id", middleware.RequirePermission(middleware.PermSupplierRead), handler.GetPemasok)
+		suppliers.PUT("/:id", middleware.RequirePermission(middleware.PermSupplierWrite), handler.UpdatePemasok)
+		suppliers.DELETE("/:id", middleware.RequirePermission(middleware.PermSupplierWrite), handler.DeletePemasok)
 	}
 }
@@ -22,12 +22,12 @@ func SetupProductRoutes(api *gin.RouterGroup) {
 	products := api.Group("/products")
 	products.Use(middleware.AuthMiddleware())
 	{
-		products.POST("", productHandler.CreateProduct)
-		products.GET("", productHandler.ListProducts)
-		products.GET("/:id", productHandler.GetProduct)
-		products.PUT("/:id", productHandler.UpdateProduct)
-		products.DELETE("/:id", productHandler.DeleteProduct)
-		products.POST("/:id/images", productHandler.UploadProductImages)
-		products.DELETE("/:id/images/:imageId", productHandler.DeleteProductImage)
+		products.POST("", middleware.RequirePermission(middleware.PermProductWrite), productHandler.CreateProduct)
+		products.GET("", middleware.RequirePermission(middleware.PermProductRead), productHandler.ListProducts)
+		products.GET("/:id", middleware.RequirePermission(middleware.PermProductRead), productHandler.GetProduct)
+		products.PUT("/:id", middleware.RequirePermission(middleware.PermProductWrite), productHandler.UpdateProduct)
+		products.DELETE("/:id", middleware.RequirePermission(middleware.PermProductWrite), productHandler.DeleteProduct)
+		products.POST("/:id/images", middleware.RequirePermission(middleware.PermProductWrite), productHandler.UploadProductImages)
+		products.DELETE("/:id/images/:imageId", middleware.RequirePermission(middleware.PermProductWrite), productHandler.DeleteProductImage)
 	}
 }
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupPurchaseOrderRoutes mengatur routes untuk Purchase Order (pembelian ke supplier)
+func SetupPurchaseOrderRoutes(api *gin.RouterGroup, db *gorm.DB, publisher events.Publisher) {
+	poRepo := repositories.NewPurchaseOrderRepository(db)
+	pemasokRepo := repositories.NewPemasokRepository(db)
+	productRepo := repositories.NewProductRepository(db)
+	stockRepo := repositories.NewStockRepository(db)
+	batchRepo := repositories.NewStockBatchRepository(db)
+	hutangRepo := repositories.NewHutangRepository(db)
+
+	poService := services.NewPurchaseOrderService(poRepo, pemasokRepo, productRepo, stockRepo, batchRepo, hutangRepo, publisher)
+	poHandler := handlers.NewPurchaseOrderHandler(poService)
+
+	reorderService := services.NewReorderService(repositories.NewReorderRepository(db), poRepo)
+	reorderHandler := handlers.NewReorderHandler(reorderService)
+
+	purchaseOrders := api.Group("/purchase-orders")
+	purchaseOrders.Use(middleware.AuthMiddleware())
+	{
+		purchaseOrders.GET("", middleware.RequirePermission(middleware.PermPurchaseOrderRead), poHandler.ListPurchaseOrders)
+		purchaseOrders.POST("", middleware.RequirePermission(middleware.PermPurchaseOrderWrite), poHandler.CreatePurchaseOrder)
+		purchaseOrders.GET("/reorder-suggestions", middleware.RequirePermission(middleware.PermPurchaseOrderRead), reorderHandler.GetReorderSuggestions)
+		purchaseOrders.POST("/reorder-suggestions/generate", middleware.RequirePermission(middleware.PermPurchaseOrderWrite), reorderHandler.GenerateReorderPurchaseOrders) // Satu draft PO per pemasok
+		purchaseOrders.GET("/:id", middleware.RequirePermission(middleware.PermPurchaseOrderRead), poHandler.GetPurchaseOrder)
+		purchaseOrders.PUT("/:id", middleware.RequirePermission(middleware.PermPurchaseOrderWrite), poHandler.UpdatePurchaseOrder)              // Hanya draft
+		purchaseOrders.PATCH("/:id/send", middleware.RequirePermission(middleware.PermPurchaseOrderWrite), poHandler.SendPurchaseOrder)         // draft → sent
+		purchaseOrders.PATCH("/:id/approve", middleware.RequirePermission(middleware.PermPurchaseOrderApprove), poHandler.ApprovePurchaseOrder) // sent → approved
+		purchaseOrders.PATCH("/:id/cancel", middleware.RequirePermission(middleware.PermPurchaseOrderWrite), poHandler.CancelPurchaseOrder)     // draft/sent/approved → cancelled
+		purchaseOrders.POST("/:id/receive", middleware.RequirePermission(middleware.PermPurchaseOrderReceive), poHandler.ReceivePurchaseOrder)  // Penerimaan barang → batch FIFO harga PO
+	}
+}
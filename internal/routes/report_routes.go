@@ -9,7 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupReportRoutes mengatur routes untuk laporan penjualan
+// SetupReportRoutes mengatur routes untuk laporan penjualan, retur, stok dan piutang
 func SetupReportRoutes(api *gin.RouterGroup) {
 	reportService := services.NewReportService(database.DB)
 	reportHandler := handlers.NewReportHandler(reportService)
@@ -18,14 +18,19 @@ func SetupReportRoutes(api *gin.RouterGroup) {
 	reports.Use(middleware.AuthMiddleware())
 	{
 		// Sales Reports
-		reports.GET("/sales", reportHandler.GetSalesReportByPeriod)               // ?tanggal_dari=&tanggal_sampai=&id_gudang=
-		reports.GET("/sales/by-product", reportHandler.GetSalesReportByProduct)   // ?tanggal_dari=&tanggal_sampai=&id_gudang=
-		reports.GET("/sales/by-customer", reportHandler.GetSalesReportByCustomer) // ?tanggal_dari=&tanggal_sampai=
+		reports.GET("/sales", middleware.RequirePermission(middleware.PermReportRead), reportHandler.GetSalesReportByPeriod)               // ?tanggal_dari=&tanggal_sampai=&id_gudang=
+		reports.GET("/sales/by-product", middleware.RequirePermission(middleware.PermReportRead), reportHandler.GetSalesReportByProduct)   // ?tanggal_dari=&tanggal_sampai=&id_gudang=
+		reports.GET("/sales/by-customer", middleware.RequirePermission(middleware.PermReportRead), reportHandler.GetSalesReportByCustomer) // ?tanggal_dari=&tanggal_sampai=
 
 		// Returns Report
-		reports.GET("/returns", reportHandler.GetReturnReport) // ?tanggal_dari=&tanggal_sampai=
+		reports.GET("/returns", middleware.RequirePermission(middleware.PermReportRead), reportHandler.GetReturnReport) // ?tanggal_dari=&tanggal_sampai=
 
 		// Stocks / Inventory Report
-		reports.GET("/stocks", reportHandler.GetStockReport) // ?page=1&limit=10&search=&low_stock_only=true
+		reports.GET("/stocks", middleware.RequirePermission(middleware.PermReportRead), reportHandler.GetStockReport) // ?page=1&limit=10&search=&low_stock_only=true
+
+		// Receivables (Piutang Pelanggan)
+		reports.GET("/receivables", middleware.RequirePermission(middleware.PermFinanceRead), reportHandler.GetPiutang)                                   // ?id_pelanggan=&jatuh_tempo=true
+		reports.GET("/receivables/aging", middleware.RequirePermission(middleware.PermFinanceRead), reportHandler.GetAgingPiutang)                        // ?tanggal=&id_pelanggan=
+		reports.GET("/receivables/customers/:id/statement", middleware.RequirePermission(middleware.PermFinanceRead), reportHandler.GetCustomerStatement) // ?tanggal_dari=&tanggal_sampai=
 	}
 }
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"real-erp-mebel/be/internal/events"
 	"real-erp-mebel/be/internal/handlers"
 	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/repositories"
@@ -11,32 +12,48 @@ import (
 )
 
 // SetupReturnRoutes mengatur routes untuk retur penjualan dan retur pembelian
-func SetupReturnRoutes(api *gin.RouterGroup, db *gorm.DB) {
+func SetupReturnRoutes(api *gin.RouterGroup, db *gorm.DB, publisher events.Publisher) {
 	returnRepo := repositories.NewReturnRepository(db)
 	stockRepo := repositories.NewStockRepository(db)
 	batchRepo := repositories.NewStockBatchRepository(db)
 	salesRepo := repositories.NewSalesRepository(db)
+	shiftRepo := repositories.NewShiftRepository(db)
 
-	returnService := services.NewReturnService(returnRepo, stockRepo, batchRepo, salesRepo)
+	returnService := services.NewReturnService(returnRepo, stockRepo, batchRepo, salesRepo, shiftRepo, publisher)
 	returnHandler := handlers.NewReturnHandler(returnService)
 
 	// Retur Penjualan (Customer → Toko)
 	salesReturns := api.Group("/sales-returns")
 	salesReturns.Use(middleware.AuthMiddleware())
 	{
-		salesReturns.GET("", returnHandler.ListReturPenjualan)
-		salesReturns.POST("", returnHandler.CreateReturPenjualan)
-		salesReturns.GET("/:id", returnHandler.GetReturPenjualan)
-		salesReturns.PATCH("/:id/approve", returnHandler.ApproveReturPenjualan) // Stok masuk kembali
+		salesReturns.GET("", middleware.RequirePermission(middleware.PermSalesReturnRead), returnHandler.ListReturPenjualan)
+		salesReturns.POST("", middleware.RequirePermission(middleware.PermSalesReturnCreate), returnHandler.CreateReturPenjualan)
+		salesReturns.GET("/:id", middleware.RequirePermission(middleware.PermSalesReturnRead), returnHandler.GetReturPenjualan)
+		salesReturns.PATCH("/:id/approve", middleware.RequirePermission(middleware.PermSalesReturnApprove), returnHandler.ApproveReturPenjualan)    // Barang masuk stok karantina
+		salesReturns.PATCH("/:id/reject", middleware.RequirePermission(middleware.PermSalesReturnApprove), returnHandler.RejectReturPenjualan)      // Wajib alasan
+		salesReturns.PATCH("/:id/complete", middleware.RequirePermission(middleware.PermSalesReturnComplete), returnHandler.CompleteReturPenjualan) // Refund / tukar barang beres
 	}
 
 	// Retur Pembelian (Toko → Supplier/Vendor)
 	purchaseReturns := api.Group("/purchase-returns")
 	purchaseReturns.Use(middleware.AuthMiddleware())
 	{
-		purchaseReturns.GET("", returnHandler.ListReturPembelian)
-		purchaseReturns.POST("", returnHandler.CreateReturPembelian)
-		purchaseReturns.GET("/:id", returnHandler.GetReturPembelian)
-		purchaseReturns.PATCH("/:id/approve", returnHandler.ApproveReturPembelian) // Stok keluar via FIFO
+		purchaseReturns.GET("", middleware.RequirePermission(middleware.PermPurchaseReturnRead), returnHandler.ListReturPembelian)
+		purchaseReturns.POST("", middleware.RequirePermission(middleware.PermPurchaseReturnCreate), returnHandler.CreateReturPembelian)
+		purchaseReturns.GET("/:id", middleware.RequirePermission(middleware.PermPurchaseReturnRead), returnHandler.GetReturPembelian)
+		purchaseReturns.PATCH("/:id/approve", middleware.RequirePermission(middleware.PermPurchaseReturnApprove), returnHandler.ApproveReturPembelian)    // Stok keluar via FIFO
+		purchaseReturns.PATCH("/:id/reject", middleware.RequirePermission(middleware.PermPurchaseReturnApprove), returnHandler.RejectReturPembelian)      // Wajib alasan
+		purchaseReturns.PATCH("/:id/complete", middleware.RequirePermission(middleware.PermPurchaseReturnComplete), returnHandler.CompleteReturPembelian) // Refund / potong hutang beres
+	}
+
+	// Stok Karantina (barang retur penjualan yang menunggu disposisi)
+	quarantine := api.Group("/quarantine-stocks")
+	quarantine.Use(middleware.AuthMiddleware())
+	{
+		quarantine.GET("", middleware.RequirePermission(middleware.PermQuarantineRead), returnHandler.ListStokKarantina)
+		quarantine.GET("/dispositions", middleware.RequirePermission(middleware.PermQuarantineRead), returnHandler.ListDisposisiKarantina) // Audit trail
+		quarantine.POST("/:id/release", middleware.RequirePermission(middleware.PermQuarantineManage), returnHandler.ReleaseKarantina)     // Kembali ke stok jual
+		quarantine.POST("/:id/write-off", middleware.RequirePermission(middleware.PermQuarantineManage), returnHandler.WriteOffKarantina)  // Rusak, dihapus
+		quarantine.POST("/:id/repair", middleware.RequirePermission(middleware.PermQuarantineManage), returnHandler.RepairKarantina)       // Dikirim perbaikan
 	}
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRoleRoutes mengatur routes untuk role & permission management
+func SetupRoleRoutes(api *gin.RouterGroup) {
+	roleHandler := handlers.NewRoleHandler()
+
+	roles := api.Group("/roles")
+	roles.Use(middleware.AuthMiddleware(), middleware.RequirePermission(middleware.PermRoleManage))
+	{
+		roles.GET("", roleHandler.ListRoles)
+		roles.GET("/permissions", roleHandler.ListPermissions) // Katalog permission
+		roles.POST("", roleHandler.CreateRole)
+		roles.GET("/:id", roleHandler.GetRole)
+		roles.PUT("/:id", roleHandler.UpdateRole)
+		roles.DELETE("/:id", roleHandler.DeleteRole) // Hanya role custom yang tidak dipakai user
+	}
+}
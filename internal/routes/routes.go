@@ -2,6 +2,9 @@ package routes
 
 import (
 	"real-erp-mebel/be/internal/database"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
 	"real-erp-mebel/be/internal/utils"
 	"real-erp-mebel/be/internal/websocket"
 
@@ -14,12 +17,22 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 	// Health check (public)
 	r.GET("/health", healthCheck)
 
-	// WebSocket endpoint (public)
+	// WebSocket endpoint (JWT via ?token= atau header Authorization)
 	r.GET("/ws", websocket.HandleWebSocket(hub))
 
 	// Serve Static Files for Uploads
 	r.Static("/uploads", "./uploads")
 
+	// Event domain (sale.created, stock.changed, dll) dicatat ke outbox di dalam transaksi service;
+	// pengiriman ke WebSocket dilakukan events.Dispatcher (lihat cmd/server)
+	publisher := events.NewOutboxPublisher(repositories.NewOutboxRepository(database.DB))
+
+	// AuthMiddleware memuat user per request untuk menolak user nonaktif/terhapus & token yang dicabut
+	middleware.SetAuthUserLoader(repositories.NewUserRepository().FindByID)
+
+	// Permission per request dibaca dari tabel peran/peran_izin (dicache di middleware)
+	middleware.SetRolePermissionLoader(repositories.NewRoleRepository().FindPermissionCodes)
+
 	// API routes
 	api := r.Group("/api/v1")
 	{
@@ -29,17 +42,21 @@ func SetupRoutes(r *gin.Engine, hub *websocket.Hub) {
 		// Protected routes (require authentication)
 		// Routes dipisahkan per modul untuk kemudahan maintenance
 		SetupUserRoutes(api)
+		SetupRoleRoutes(api) // Role & permission management
 
 		// Add more module routes here:
 		SetupProductRoutes(api)
-		SetupStockRoutes(api, database.DB)  // Registered Stock Routes
-		SetupPemasokRoutes(api)             // Registered Supplier Routes
-		SetupGudangRoutes(api)              // Registered Warehouse Routes
-		SetupSalesRoutes(api, database.DB)  // Registered Sales Routes (Mode 1: POS)
-		SetupReturnRoutes(api, database.DB) // Registered Return Routes (Sales Return + Purchase Return)
-		SetupReportRoutes(api)              // Registered Report Routes (Sales by Period/Product/Customer)
-		// SetupPurchaseOrderRoutes(api)
-		// SetupFinanceRoutes(api)
+		SetupStockRoutes(api, database.DB, publisher)         // Registered Stock Routes
+		SetupPemasokRoutes(api)                               // Registered Supplier Routes
+		SetupPelangganRoutes(api)                             // Registered Customer Routes
+		SetupGudangRoutes(api)                                // Registered Warehouse Routes
+		SetupSalesRoutes(api, database.DB, publisher)         // Registered Sales Routes (Mode 1: POS)
+		SetupReturnRoutes(api, database.DB, publisher)        // Registered Return Routes (Sales Return + Purchase Return)
+		SetupShiftRoutes(api, database.DB, publisher)         // Registered Cashier Shift Routes (kas awal & Z report)
+		SetupReportRoutes(api)                                // Registered Report Routes (Sales by Period/Product/Customer)
+		SetupPurchaseOrderRoutes(api, database.DB, publisher) // Registered Purchase Order Routes
+		SetupFinanceRoutes(api, database.DB)                  // Registered Finance Routes (Supplier Debts)
+		SetupWebhookRoutes(api, database.DB)                  // Registered Webhook Routes (endpoint & log pengiriman)
 	}
 }
 
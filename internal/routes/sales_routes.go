@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"real-erp-mebel/be/internal/events"
 	"real-erp-mebel/be/internal/handlers"
 	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/repositories"
@@ -10,28 +11,63 @@ import (
 	"gorm.io/gorm"
 )
 
-// SetupSalesRoutes mengatur semua routes untuk modul penjualan (Mode 1: POS)
-func SetupSalesRoutes(api *gin.RouterGroup, db *gorm.DB) {
+// SetupSalesRoutes mengatur semua routes untuk modul penjualan (Mode 1: POS, Mode 2: pesanan)
+func SetupSalesRoutes(api *gin.RouterGroup, db *gorm.DB, publisher events.Publisher) {
 	// Initialize dependencies
 	salesRepo := repositories.NewSalesRepository(db)
 	stockRepo := repositories.NewStockRepository(db)
 	batchRepo := repositories.NewStockBatchRepository(db)
+	pelangganRepo := repositories.NewPelangganRepository(db)
+	shiftRepo := repositories.NewShiftRepository(db)
 
-	salesService := services.NewSalesService(salesRepo, stockRepo, batchRepo)
+	salesService := services.NewSalesService(salesRepo, stockRepo, batchRepo, pelangganRepo, shiftRepo, publisher)
 	salesHandler := handlers.NewSalesHandler(salesService)
 
+	salesOrderService := services.NewSalesOrderService(salesRepo, stockRepo, batchRepo, pelangganRepo, shiftRepo, salesService, publisher)
+	salesOrderHandler := handlers.NewSalesOrderHandler(salesOrderService)
+
 	sales := api.Group("/sales")
 	sales.Use(middleware.AuthMiddleware())
 	{
 		// Daftar & buat transaksi penjualan
-		sales.GET("", salesHandler.ListSales)
-		sales.POST("", salesHandler.CreateSale) // multipart/form-data: field "data" (JSON) + "bukti_bayar" (file, opsional)
+		sales.GET("", middleware.RequirePermission(middleware.PermSalesRead), salesHandler.ListSales)
+		sales.POST("", middleware.RequirePermission(middleware.PermSalesCreate), salesHandler.CreateSale) // multipart/form-data: field "data" (JSON) + "bukti_bayar" (file, opsional)
 
 		// Detail & invoice
-		sales.GET("/:id", salesHandler.GetSale)
-		sales.GET("/:id/invoice", salesHandler.GetInvoice)
+		sales.GET("/:id", middleware.RequirePermission(middleware.PermSalesRead), salesHandler.GetSale)
+		sales.GET("/:id/invoice", middleware.RequirePermission(middleware.PermSalesRead), salesHandler.GetInvoice)
 
 		// Upload bukti bayar (untuk transaksi transfer yang belum upload saat transaksi)
-		sales.POST("/:id/bukti-bayar", salesHandler.UploadBuktiBayar)
+		sales.POST("/:id/bukti-bayar", middleware.RequirePermission(middleware.PermSalesCreate), salesHandler.UploadBuktiBayar)
+
+		// Void transaksi (owner / finance) — stok dikembalikan ke batch FIFO asal
+		sales.PATCH("/:id/void", middleware.RequirePermission(middleware.PermSalesVoid), salesHandler.VoidSale)
+	}
+
+	orders := api.Group("/sales-orders")
+	orders.Use(middleware.AuthMiddleware())
+	{
+		// Daftar & buat pesanan (DP opsional)
+		orders.GET("", middleware.RequirePermission(middleware.PermSalesRead), salesOrderHandler.ListOrders)
+		orders.POST("", middleware.RequirePermission(middleware.PermSalesCreate), salesOrderHandler.CreateOrder)
+		orders.GET("/:id", middleware.RequirePermission(middleware.PermSalesRead), salesOrderHandler.GetOrder)
+
+		// Cicilan / pelunasan
+		orders.POST("/:id/payments", middleware.RequirePermission(middleware.PermSalesCreate), salesOrderHandler.AddPayment)
+
+		// Siap (stok keluar FIFO) & terkirim — tim gudang
+		orders.PATCH("/:id/ready", middleware.RequirePermission(middleware.PermSalesOrderFulfill), salesOrderHandler.MarkReady)
+		orders.PATCH("/:id/deliver", middleware.RequirePermission(middleware.PermSalesOrderFulfill), salesOrderHandler.MarkDelivered)
+
+		// Batal sebelum dikirim — stok dikembalikan ke batch asal
+		orders.PATCH("/:id/cancel", middleware.RequirePermission(middleware.PermSalesVoid), salesOrderHandler.CancelOrder)
+	}
+
+	// Penagihan piutang (tim finance) — :id adalah ID pesanan, laporan piutang ada di /reports/receivables
+	receivables := api.Group("/receivables")
+	receivables.Use(middleware.AuthMiddleware())
+	{
+		receivables.POST("/:id/payments", middleware.RequirePermission(middleware.PermFinancePayment), salesOrderHandler.AddPayment) // Cicilan / pelunasan + bukti bayar
+		receivables.PATCH("/:id/due-date", middleware.RequirePermission(middleware.PermFinancePayment), salesOrderHandler.UpdateJatuhTempo)
 	}
 }
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupShiftRoutes mengatur routes shift kasir (buka dengan kas awal, tutup dengan rekonsiliasi kas)
+func SetupShiftRoutes(api *gin.RouterGroup, db *gorm.DB, publisher events.Publisher) {
+	shiftRepo := repositories.NewShiftRepository(db)
+	shiftService := services.NewShiftService(shiftRepo, publisher)
+	shiftHandler := handlers.NewShiftHandler(shiftService)
+
+	// shift.manage memperluas akses ke shift semua kasir (list, detail, tutup, laporan)
+	shifts := api.Group("/shifts")
+	shifts.Use(middleware.AuthMiddleware(), middleware.RequirePermission(middleware.PermShiftOperate))
+	{
+		shifts.POST("/open", shiftHandler.OpenShift)
+		shifts.GET("/current", shiftHandler.GetCurrentShift)
+		shifts.GET("", shiftHandler.ListShifts)
+		shifts.GET("/:id", shiftHandler.GetShift)
+		shifts.PATCH("/:id/close", shiftHandler.CloseShift)    // Hitung kas & simpan Z report
+		shifts.GET("/:id/report", shiftHandler.GetShiftReport) // X report (open) / Z report (closed), siap cetak
+	}
+}
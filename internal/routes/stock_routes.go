@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"real-erp-mebel/be/internal/events"
 	"real-erp-mebel/be/internal/handlers"
 	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/repositories"
@@ -10,21 +11,55 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupStockRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func SetupStockRoutes(r *gin.RouterGroup, db *gorm.DB, publisher events.Publisher) {
 	stockRepo := repositories.NewStockRepository(db)
 	batchRepo := repositories.NewStockBatchRepository(db)
-	stockService := services.NewStockService(stockRepo, batchRepo)
+	hutangRepo := repositories.NewHutangRepository(db)
+	stockService := services.NewStockService(stockRepo, batchRepo, hutangRepo, publisher)
 	stockHandler := handlers.NewStockHandler(stockService)
 
+	transferRepo := repositories.NewStockTransferRepository(db)
+	transferService := services.NewStockTransferService(transferRepo, stockRepo, batchRepo, publisher)
+	transferHandler := handlers.NewStockTransferHandler(transferService)
+
+	alertService := services.NewStockAlertService(stockRepo, publisher)
+	alertHandler := handlers.NewStockAlertHandler(alertService)
+
 	stocks := r.Group("/stocks")
 	stocks.Use(middleware.AuthMiddleware())
 	{
-		stocks.GET("", stockHandler.GetStocks)
-		stocks.GET("/history", stockHandler.GetStockHistory)
-		stocks.GET("/batches", stockHandler.GetStockBatches)
-		stocks.POST("/in", stockHandler.CreateStockIn)
-		stocks.POST("/out", stockHandler.CreateStockOut)
-		stocks.POST("/adjustment", stockHandler.CreateStockOpname)
-		stocks.POST("/transfer", stockHandler.CreateStockTransfer)
+		stocks.GET("", middleware.RequirePermission(middleware.PermStockRead), stockHandler.GetStocks)
+		stocks.GET("/history", middleware.RequirePermission(middleware.PermStockRead), stockHandler.GetStockHistory)
+		stocks.GET("/batches", middleware.RequirePermission(middleware.PermStockRead), stockHandler.GetStockBatches)
+		stocks.GET("/in", middleware.RequirePermission(middleware.PermStockRead), stockHandler.ListStockIn)
+		stocks.POST("/in", middleware.RequirePermission(middleware.PermStockIn), stockHandler.CreateStockIn)                      // Status pending
+		stocks.PATCH("/in/:id/approve", middleware.RequirePermission(middleware.PermStockInApprove), stockHandler.ApproveStockIn) // Owner: stok & batch dibukukan
+		stocks.PATCH("/in/:id/reject", middleware.RequirePermission(middleware.PermStockInApprove), stockHandler.RejectStockIn)   // Owner: wajib alasan
+		stocks.POST("/out", middleware.RequirePermission(middleware.PermStockOut), stockHandler.CreateStockOut)
+		stocks.GET("/in/:id", middleware.RequirePermission(middleware.PermStockRead), stockHandler.GetStockIn)
+		stocks.GET("/out/:id", middleware.RequirePermission(middleware.PermStockRead), stockHandler.GetStockOut)
+		stocks.POST("/adjustment", middleware.RequirePermission(middleware.PermStockOpname), stockHandler.CreateStockOpname)
+		stocks.POST("/transfer", middleware.RequirePermission(middleware.PermStockTransfer), transferHandler.CreateStockTransfer)
+	}
+
+	// Dokumen transfer: draft → shipped (in-transit) → received / cancelled
+	transfers := r.Group("/stock-transfers")
+	transfers.Use(middleware.AuthMiddleware())
+	{
+		transfers.GET("", middleware.RequirePermission(middleware.PermStockRead), transferHandler.ListTransfers)
+		transfers.POST("", middleware.RequirePermission(middleware.PermStockTransfer), transferHandler.CreateTransferDocument)
+		transfers.GET("/:id", middleware.RequirePermission(middleware.PermStockRead), transferHandler.GetTransfer)
+		transfers.PATCH("/:id/ship", middleware.RequirePermission(middleware.PermStockTransfer), transferHandler.ShipTransfer)
+		transfers.PATCH("/:id/receive", middleware.RequirePermission(middleware.PermStockTransfer), transferHandler.ReceiveTransfer)
+		transfers.PATCH("/:id/cancel", middleware.RequirePermission(middleware.PermStockTransfer), transferHandler.CancelTransfer)
+	}
+
+	// Peringatan stok menipis: dibuka/diselesaikan otomatis dari perubahan saldo vs stok minimum produk
+	alerts := r.Group("/stock-alerts")
+	alerts.Use(middleware.AuthMiddleware())
+	{
+		alerts.GET("", middleware.RequirePermission(middleware.PermStockRead), alertHandler.ListAlerts) // ?status=active|open|acknowledged|resolved|all
+		alerts.GET("/:id", middleware.RequirePermission(middleware.PermStockRead), alertHandler.GetAlert)
+		alerts.PATCH("/:id/acknowledge", middleware.RequirePermission(middleware.PermStockAlertAck), alertHandler.AcknowledgeAlert)
 	}
 }
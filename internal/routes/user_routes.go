@@ -20,11 +20,16 @@ func SetupUserRoutes(api *gin.RouterGroup) {
 		users.PUT("/me/password", userHandler.ChangePassword)  // Change password
 
 		// User management routes
-		users.GET("", userHandler.ListUsers)                    // List all users (owner/admin only)
-		users.POST("", userHandler.CreateUser)                 // Create user (owner/admin only)
+		users.GET("", middleware.RequirePermission(middleware.PermUserManage), userHandler.ListUsers)                    // List all users (owner/admin only)
+		users.POST("", middleware.RequirePermission(middleware.PermUserManage), userHandler.CreateUser)                 // Create user (owner/admin only)
 		users.GET("/:id", userHandler.GetUserByID)             // Get user by ID
 		users.PUT("/:id", userHandler.UpdateUser)              // Update user
-		users.DELETE("/:id", userHandler.DeleteUser)           // Delete user (owner/admin only)
+		users.DELETE("/:id", middleware.RequirePermission(middleware.PermUserManage), userHandler.DeleteUser)           // Delete user (owner/admin only)
+
+		// Staff invitations (pengganti self-registration)
+		users.GET("/invitations", middleware.RequirePermission(middleware.PermUserManage), userHandler.ListInvitations)
+		users.POST("/invitations", middleware.RequirePermission(middleware.PermUserManage), userHandler.CreateInvitation)
+		users.DELETE("/invitations/:id", middleware.RequirePermission(middleware.PermUserManage), userHandler.RevokeInvitation)
 	}
 }
 
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"real-erp-mebel/be/internal/handlers"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SetupWebhookRoutes mengatur routes untuk webhook event bisnis (endpoint & log pengiriman)
+func SetupWebhookRoutes(api *gin.RouterGroup, db *gorm.DB) {
+	webhookRepo := repositories.NewWebhookRepository(db)
+	webhookService := services.NewWebhookService(webhookRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	webhooks := api.Group("/webhooks")
+	webhooks.Use(middleware.AuthMiddleware())
+	webhooks.Use(middleware.RequirePermission(middleware.PermWebhookManage))
+	{
+		webhooks.GET("", webhookHandler.ListWebhooks)
+		webhooks.POST("", webhookHandler.CreateWebhook) // Secret hanya ditampilkan sekali
+		webhooks.GET("/:id", webhookHandler.GetWebhook)
+		webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)                    // Log pengiriman
+		webhooks.POST("/:id/deliveries/:delivery_id/retry", webhookHandler.RetryDelivery) // Kirim ulang manual
+	}
+}
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"real-erp-mebel/be/internal/config"
@@ -16,25 +20,36 @@ import (
 
 // AuthService adalah interface untuk auth service
 type AuthService interface {
-	Login(req dto.LoginRequest) (*dto.LoginResponse, error)
+	Login(req dto.LoginRequest, client dto.ClientInfo) (*dto.LoginResponse, error)
+	Refresh(req dto.RefreshTokenRequest, client dto.ClientInfo) (*dto.LoginResponse, error)
+	Logout(userID uint, req dto.LogoutRequest) error
 	Register(req dto.RegisterRequest) (*dto.UserResponse, error)
+	BootstrapStatus() (*dto.BootstrapStatusResponse, error)
+	Bootstrap(req dto.BootstrapRequest) (*dto.UserResponse, error)
+	AcceptInvitation(req dto.AcceptInvitationRequest) (*dto.UserResponse, error)
 	GenerateToken(user *models.User) (string, error)
 }
 
 type authService struct {
-	userRepo repositories.UserRepository
-	logger   *zap.Logger
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	invitationRepo repositories.InvitationRepository
+	refreshRepo    repositories.RefreshTokenRepository
+	logger         *zap.Logger
 }
 
 // NewAuthService membuat instance AuthService baru
 func NewAuthService() AuthService {
 	return &authService{
-		userRepo: repositories.NewUserRepository(),
-		logger:   utils.GetLogger(),
+		userRepo:       repositories.NewUserRepository(),
+		roleRepo:       repositories.NewRoleRepository(),
+		invitationRepo: repositories.NewInvitationRepository(),
+		refreshRepo:    repositories.NewRefreshTokenRepository(),
+		logger:         utils.GetLogger(),
 	}
 }
 
-func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
+func (s *authService) Login(req dto.LoginRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
@@ -54,7 +69,116 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
 		return nil, utils.ErrInvalidCredentials
 	}
 
-	// Generate token
+	if !user.Aktif {
+		s.logger.Warn("Login attempt by inactive user",
+			zap.Uint("user_id", user.ID),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeUnauthorized, "Account is inactive", nil)
+	}
+
+	response, err := s.issueTokens(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User logged in successfully",
+		zap.Uint("user_id", user.ID),
+		zap.String("email", user.Email),
+	)
+
+	return response, nil
+}
+
+func (s *authService) Refresh(req dto.RefreshTokenRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	errInvalid := utils.NewAppError(utils.ErrCodeUnauthorized, "Invalid or expired refresh token", nil)
+
+	stored, err := s.refreshRepo.FindByHash(hashOpaqueToken(req.RefreshToken))
+	if err != nil {
+		return nil, errInvalid
+	}
+
+	now := time.Now()
+	if stored.DicabutPada != nil {
+		// Token hasil rotasi dipakai lagi: kemungkinan bocor, cabut semua sesi user
+		if stored.DigantiOleh != nil {
+			s.logger.Warn("Rotated refresh token reused, revoking all sessions",
+				zap.Uint("user_id", stored.IDPengguna),
+				zap.Uint("token_id", stored.ID),
+			)
+			s.revokeAllSessions(stored.IDPengguna, now)
+		}
+		return nil, errInvalid
+	}
+	if !now.Before(stored.KedaluwarsaPada) {
+		return nil, errInvalid
+	}
+
+	user, err := s.userRepo.FindByID(stored.IDPengguna)
+	if err != nil {
+		return nil, errInvalid
+	}
+	if !user.Aktif {
+		s.revokeAllSessions(user.ID, now)
+		return nil, utils.NewAppError(utils.ErrCodeUnauthorized, "Account is inactive", nil)
+	}
+
+	refreshToken, replacement, err := newRefreshToken(user.ID, client, now)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to generate refresh token", err)
+	}
+	if err := s.refreshRepo.Rotate(stored.ID, replacement, now); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenRevoked) {
+			return nil, errInvalid
+		}
+		s.logger.Error("Failed to rotate refresh token",
+			zap.Uint("user_id", user.ID),
+			zap.Error(err),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to rotate refresh token", err)
+	}
+
+	token, err := s.GenerateToken(user)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to generate token", err)
+	}
+
+	return toLoginResponse(user, token, refreshToken), nil
+}
+
+func (s *authService) Logout(userID uint, req dto.LogoutRequest) error {
+	now := time.Now()
+
+	if req.AllDevices {
+		if err := s.refreshRepo.RevokeAllByUser(userID, now); err != nil {
+			return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to revoke sessions", err)
+		}
+		// Access token yang masih berlaku ikut ditolak karena versinya tidak cocok lagi
+		if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+			return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to revoke sessions", err)
+		}
+		s.logger.Info("User logged out from all devices", zap.Uint("user_id", userID))
+		return nil
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	// Token milik user lain atau yang tidak dikenal diabaikan (logout selalu idempoten)
+	stored, err := s.refreshRepo.FindByHash(hashOpaqueToken(req.RefreshToken))
+	if err != nil || stored.IDPengguna != userID {
+		return nil
+	}
+	if err := s.refreshRepo.Revoke(stored.ID, now); err != nil {
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to revoke refresh token", err)
+	}
+
+	s.logger.Info("User logged out", zap.Uint("user_id", userID))
+	return nil
+}
+
+// issueTokens membuat pasangan access token + refresh token baru untuk user
+func (s *authService) issueTokens(user *models.User, client dto.ClientInfo) (*dto.LoginResponse, error) {
 	token, err := s.GenerateToken(user)
 	if err != nil {
 		s.logger.Error("Failed to generate token",
@@ -64,13 +188,27 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
 		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to generate token", err)
 	}
 
-	s.logger.Info("User logged in successfully",
-		zap.Uint("user_id", user.ID),
-		zap.String("email", user.Email),
-	)
+	refreshToken, record, err := newRefreshToken(user.ID, client, time.Now())
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to generate refresh token", err)
+	}
+	if err := s.refreshRepo.Create(record); err != nil {
+		s.logger.Error("Failed to store refresh token",
+			zap.Uint("user_id", user.ID),
+			zap.Error(err),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to store refresh token", err)
+	}
 
+	return toLoginResponse(user, token, refreshToken), nil
+}
+
+// toLoginResponse menyusun response token untuk login dan refresh
+func toLoginResponse(user *models.User, token, refreshToken string) *dto.LoginResponse {
 	return &dto.LoginResponse{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTokenTTL().Seconds()),
 		User: dto.UserResponse{
 			ID:    user.ID,
 			Email: user.Email,
@@ -78,10 +216,25 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
 			Peran: user.Peran,
 			Aktif: user.Aktif,
 		},
-	}, nil
+	}
+}
+
+// revokeAllSessions mencabut semua refresh token dan access token user (best effort, hanya dicatat kalau gagal)
+func (s *authService) revokeAllSessions(userID uint, at time.Time) {
+	if err := s.refreshRepo.RevokeAllByUser(userID, at); err != nil {
+		s.logger.Error("Failed to revoke refresh tokens", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	if err := s.userRepo.IncrementTokenVersion(userID); err != nil {
+		s.logger.Error("Failed to bump token version", zap.Uint("user_id", userID), zap.Error(err))
+	}
 }
 
 func (s *authService) Register(req dto.RegisterRequest) (*dto.UserResponse, error) {
+	// Self-registration default mati; staff baru masuk lewat undangan
+	if !config.AppConfig.Auth.AllowRegistration {
+		return nil, utils.NewAppError(utils.ErrCodeForbidden, "Self-registration is disabled", nil)
+	}
+
 	// Check if user already exists
 	existingUser, err := s.userRepo.FindByEmail(req.Email)
 	if err == nil && existingUser != nil {
@@ -101,17 +254,12 @@ func (s *authService) Register(req dto.RegisterRequest) (*dto.UserResponse, erro
 		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to hash password", err)
 	}
 
-	role := "kasir"
-	if req.Role != "" {
-		role = req.Role
-	}
-
-	// Create user
+	// Role tidak bisa dipilih sendiri; naik role hanya lewat user management
 	user := &models.User{
 		Email:    req.Email,
 		Password: string(hashedPassword),
 		Nama:     req.Name,
-		Peran:    role,
+		Peran:    "kasir",
 		Aktif:    true,
 	}
 
@@ -137,12 +285,129 @@ func (s *authService) Register(req dto.RegisterRequest) (*dto.UserResponse, erro
 	}, nil
 }
 
+func (s *authService) BootstrapStatus() (*dto.BootstrapStatusResponse, error) {
+	count, err := s.userRepo.CountAll()
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to check users", err)
+	}
+
+	return &dto.BootstrapStatusResponse{
+		BootstrapRequired:   count == 0,
+		RegistrationEnabled: config.AppConfig.Auth.AllowRegistration,
+	}, nil
+}
+
+func (s *authService) Bootstrap(req dto.BootstrapRequest) (*dto.UserResponse, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to hash password", err)
+	}
+
+	user := &models.User{
+		Email:    req.Email,
+		Password: string(hashedPassword),
+		Nama:     req.Name,
+		Peran:    "owner",
+		Aktif:    true,
+	}
+
+	created, err := s.userRepo.CreateIfEmpty(user)
+	if err != nil {
+		s.logger.Error("Failed to bootstrap owner",
+			zap.String("email", req.Email),
+			zap.Error(err),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to create owner", err)
+	}
+	if !created {
+		s.logger.Warn("Bootstrap attempt after first user exists",
+			zap.String("email", req.Email),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeForbidden, "Bootstrap is only available before the first user is created", nil)
+	}
+
+	s.logger.Info("First owner bootstrapped",
+		zap.Uint("user_id", user.ID),
+		zap.String("email", user.Email),
+	)
+
+	return &dto.UserResponse{
+		ID:    user.ID,
+		Email: user.Email,
+		Nama:  user.Nama,
+		Peran: user.Peran,
+		Aktif: user.Aktif,
+	}, nil
+}
+
+func (s *authService) AcceptInvitation(req dto.AcceptInvitationRequest) (*dto.UserResponse, error) {
+	errInvalid := utils.NewAppError(utils.ErrCodeInvalidInput, "Invitation is invalid or expired", nil)
+
+	invitation, err := s.invitationRepo.FindByTokenHash(hashOpaqueToken(req.Token))
+	if err != nil {
+		return nil, errInvalid
+	}
+
+	now := time.Now()
+	if invitationStatus(invitation, now) != "pending" {
+		return nil, errInvalid
+	}
+
+	if existingUser, err := s.userRepo.FindByEmail(invitation.Email); err == nil && existingUser != nil {
+		return nil, utils.ErrUserExists
+	}
+
+	role, err := s.roleRepo.FindByKode(invitation.Peran)
+	if err != nil || !role.Aktif {
+		return nil, utils.NewAppError(utils.ErrCodeValidationError, "Invited role is no longer available", err)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to hash password", err)
+	}
+
+	user := &models.User{
+		Email:    invitation.Email,
+		Password: string(hashedPassword),
+		Nama:     req.Name,
+		Peran:    invitation.Peran,
+		Aktif:    true,
+	}
+
+	if err := s.invitationRepo.Accept(invitation.ID, user, now); err != nil {
+		if errors.Is(err, repositories.ErrInvitationUnavailable) {
+			return nil, errInvalid
+		}
+		s.logger.Error("Failed to accept invitation",
+			zap.Uint("invitation_id", invitation.ID),
+			zap.Error(err),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to create user", err)
+	}
+
+	s.logger.Info("Invitation accepted",
+		zap.Uint("invitation_id", invitation.ID),
+		zap.Uint("user_id", user.ID),
+		zap.String("peran", user.Peran),
+	)
+
+	return &dto.UserResponse{
+		ID:    user.ID,
+		Email: user.Email,
+		Nama:  user.Nama,
+		Peran: user.Peran,
+		Aktif: user.Aktif,
+	}, nil
+}
+
 func (s *authService) GenerateToken(user *models.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+	expirationTime := time.Now().Add(accessTokenTTL())
 	claims := jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
 		"role":    user.Peran,
+		"ver":     user.VersiToken,
 		"exp":     expirationTime.Unix(),
 		"iat":     time.Now().Unix(),
 	}
@@ -155,3 +420,58 @@ func (s *authService) GenerateToken(user *models.User) (string, error) {
 
 	return tokenString, nil
 }
+
+// accessTokenTTL membaca umur access token dari config (fallback 15 menit)
+func accessTokenTTL() time.Duration {
+	d, err := time.ParseDuration(config.AppConfig.JWT.Expiration)
+	if err != nil || d <= 0 {
+		return 15 * time.Minute
+	}
+	return d
+}
+
+// refreshTokenTTL membaca umur refresh token dari config (fallback 7 hari)
+func refreshTokenTTL() time.Duration {
+	d, err := time.ParseDuration(config.AppConfig.JWT.RefreshExpiration)
+	if err != nil || d <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return d
+}
+
+// newRefreshToken membuat refresh token mentah beserta record yang akan disimpan
+func newRefreshToken(userID uint, client dto.ClientInfo, now time.Time) (string, *models.TokenRefresh, error) {
+	token, hash, err := generateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	return token, &models.TokenRefresh{
+		IDPengguna:      userID,
+		TokenHash:       hash,
+		KedaluwarsaPada: now.Add(refreshTokenTTL()),
+		UserAgent:       userAgent,
+		AlamatIP:        client.IP,
+	}, nil
+}
+
+// generateOpaqueToken membuat token acak (hex) beserta hash SHA-256 untuk disimpan di database
+func generateOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, hashOpaqueToken(token), nil
+}
+
+// hashOpaqueToken meng-hash token mentah; token tidak pernah disimpan dalam bentuk asli
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/config"
+	"real-erp-mebel/be/internal/dto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withJWTConfig(t *testing.T, expiration, refreshExpiration string) {
+	previous := config.AppConfig
+	config.AppConfig = &config.Config{JWT: config.JWTConfig{
+		Secret:            "test-secret",
+		Expiration:        expiration,
+		RefreshExpiration: refreshExpiration,
+	}}
+	t.Cleanup(func() { config.AppConfig = previous })
+}
+
+func TestTokenTTL_FromConfig(t *testing.T) {
+	withJWTConfig(t, "5m", "24h")
+	if got := accessTokenTTL(); got != 5*time.Minute {
+		t.Errorf("Expected access TTL 5m, got %s", got)
+	}
+	if got := refreshTokenTTL(); got != 24*time.Hour {
+		t.Errorf("Expected refresh TTL 24h, got %s", got)
+	}
+}
+
+func TestTokenTTL_InvalidConfigFallsBack(t *testing.T) {
+	withJWTConfig(t, "sebentar", "-1h")
+	if got := accessTokenTTL(); got != 15*time.Minute {
+		t.Errorf("Expected fallback access TTL 15m, got %s", got)
+	}
+	if got := refreshTokenTTL(); got != 7*24*time.Hour {
+		t.Errorf("Expected fallback refresh TTL 168h, got %s", got)
+	}
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	withJWTConfig(t, "15m", "48h")
+	now := time.Date(2024, 11, 2, 8, 0, 0, 0, time.UTC)
+
+	token, record, err := newRefreshToken(7, dto.ClientInfo{UserAgent: strings.Repeat("a", 300), IP: "10.0.0.5"}, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if record.TokenHash != hashOpaqueToken(token) {
+		t.Error("Expected stored hash to match the raw token")
+	}
+	if record.IDPengguna != 7 || !record.KedaluwarsaPada.Equal(now.Add(48*time.Hour)) {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if len(record.UserAgent) != 255 {
+		t.Errorf("Expected user agent truncated to 255, got %d", len(record.UserAgent))
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"time"
+
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
+
+	"gorm.io/gorm"
+)
+
+// stockChange mencatat perubahan stok_inventori di dalam transaksi.
+// Event stock.changed dibangun sebelum commit dari saldo terbaru di transaksi yang sama.
+type stockChange struct {
+	ProductID   uint
+	WarehouseID uint
+	Delta       int
+}
+
+// mergeStockChanges menjumlahkan delta per produk+gudang (urutan kemunculan pertama dipertahankan)
+// dan membuang produk yang totalnya tidak berubah.
+func mergeStockChanges(changes []stockChange) []stockChange {
+	type key struct{ productID, warehouseID uint }
+	index := make(map[key]int)
+	var merged []stockChange
+	for _, c := range changes {
+		k := key{c.ProductID, c.WarehouseID}
+		if i, ok := index[k]; ok {
+			merged[i].Delta += c.Delta
+			continue
+		}
+		index[k] = len(merged)
+		merged = append(merged, c)
+	}
+
+	result := merged[:0]
+	for _, c := range merged {
+		if c.Delta != 0 {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// publishWithStockEvents mencatat event tambahan beserta event stok ke outbox di dalam tx.
+// Dipanggil tepat sebelum commit agar event ikut di-rollback bila transaksi gagal.
+func publishWithStockEvents(tx *gorm.DB, publisher events.Publisher, repo repositories.StockRepository, refType string, refID uint, changes []stockChange, extra ...events.Event) error {
+	stockEvts, err := stockEvents(tx, repo, refType, refID, changes)
+	if err != nil {
+		return err
+	}
+	return publisher.Publish(tx, append(extra, stockEvts...)...)
+}
+
+// stockEvents membaca saldo terbaru (di dalam tx) setiap produk+gudang yang berubah, mengevaluasi
+// peringatan stok terhadap stok minimum produk, lalu membangun event stok
+func stockEvents(tx *gorm.DB, repo repositories.StockRepository, refType string, refID uint, changes []stockChange) ([]events.Event, error) {
+	now := time.Now()
+	var result []events.Event
+	for _, change := range mergeStockChanges(changes) {
+		stock, err := repo.FindStockBalance(tx, change.ProductID, change.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, stockChangedEvent(change, stock, refType, refID))
+
+		alertEvts, err := evaluateStockAlert(tx, repo, stock, now)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, alertEvts...)
+	}
+	return result, nil
+}
+
+// stockChangedEvent membangun stock.changed dengan saldo terbaru
+func stockChangedEvent(change stockChange, stock *models.StokInventori, refType string, refID uint) events.Event {
+	return events.New(events.TypeStockChanged, events.StockChanged{
+		ProductID:     change.ProductID,
+		WarehouseID:   change.WarehouseID,
+		Quantity:      stock.Jumlah,
+		Delta:         change.Delta,
+		ReferenceType: refType,
+		ReferenceID:   refID,
+	}, websocket.TopicWarehouse(change.WarehouseID))
+}
+
+// salesReturnEvent membangun event retur penjualan; topic gudang ditambahkan otomatis
+func salesReturnEvent(eventType, status string, r *models.ReturPenjualan, topics ...string) events.Event {
+	var warehouseID uint
+	if len(r.Items) > 0 {
+		warehouseID = r.Items[0].IDGudang
+	}
+	return returnEvent(eventType, events.ReturnStatus{
+		ID:          r.ID,
+		NomorRetur:  r.NomorRetur,
+		Kind:        events.ReturnKindSales,
+		Status:      status,
+		WarehouseID: warehouseID,
+		Total:       r.Total,
+	}, topics)
+}
+
+// purchaseReturnEvent membangun event retur pembelian; topic gudang ditambahkan otomatis
+func purchaseReturnEvent(eventType, status string, r *models.ReturPembelian, topics ...string) events.Event {
+	var warehouseID uint
+	if len(r.Items) > 0 {
+		warehouseID = r.Items[0].IDGudang
+	}
+	return returnEvent(eventType, events.ReturnStatus{
+		ID:          r.ID,
+		NomorRetur:  r.NomorRetur,
+		Kind:        events.ReturnKindPurchase,
+		Status:      status,
+		WarehouseID: warehouseID,
+		Total:       r.Total,
+	}, topics)
+}
+
+func returnEvent(eventType string, data events.ReturnStatus, topics []string) events.Event {
+	if data.WarehouseID != 0 {
+		topics = append(topics, websocket.TopicWarehouse(data.WarehouseID))
+	}
+	return events.New(eventType, data, topics...)
+}
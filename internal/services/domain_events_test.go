@@ -0,0 +1,43 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/models"
+	"testing"
+)
+
+func TestMergeStockChanges(t *testing.T) {
+	merged := mergeStockChanges([]stockChange{
+		{ProductID: 1, WarehouseID: 1, Delta: -2},
+		{ProductID: 2, WarehouseID: 1, Delta: 5},
+		{ProductID: 1, WarehouseID: 1, Delta: -3},
+		{ProductID: 2, WarehouseID: 1, Delta: -5},
+		{ProductID: 1, WarehouseID: 2, Delta: 4},
+	})
+
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 merged changes, got %+v", merged)
+	}
+	if merged[0].ProductID != 1 || merged[0].WarehouseID != 1 || merged[0].Delta != -5 {
+		t.Errorf("Unexpected first change: %+v", merged[0])
+	}
+	if merged[1].ProductID != 1 || merged[1].WarehouseID != 2 || merged[1].Delta != 4 {
+		t.Errorf("Unexpected second change: %+v", merged[1])
+	}
+}
+
+func TestStockChangedEvent(t *testing.T) {
+	stock := &models.StokInventori{IDProduk: 7, IDGudang: 3, Jumlah: 4}
+
+	got := stockChangedEvent(stockChange{ProductID: 7, WarehouseID: 3, Delta: -2}, stock, "sales", 11)
+	if got.Type != events.TypeStockChanged {
+		t.Fatalf("Expected stock.changed, got %s", got.Type)
+	}
+	changed := got.Data.(events.StockChanged)
+	if changed.Quantity != 4 || changed.Delta != -2 || changed.ReferenceID != 11 {
+		t.Errorf("Unexpected stock.changed payload: %+v", changed)
+	}
+	if len(got.Topics) != 1 || got.Topics[0] != "warehouse:3" {
+		t.Errorf("Expected warehouse topic, got %v", got.Topics)
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fifoUsage mencatat berapa qty yang diambil dari satu batch saat deduct FIFO.
+// Batch berisi snapshot batch SETELAH dikurangi (TanggalMasuk & HargaModal tetap batch asal).
+type fifoUsage struct {
+	Batch  models.StokBatch
+	Jumlah int
+}
+
+// deductFIFO mengurangi stok batch aktif (terlama dulu) sampai qty terpenuhi.
+// Hanya mengubah StokBatch — saldo stok_inventori & pergerakan stok menjadi tanggung jawab pemanggil,
+// karena tipe pergerakan dan referensinya berbeda per transaksi.
+// Wajib dipanggil di dalam TX (batch di-lock FOR UPDATE oleh GetAvailableBatches).
+func deductFIFO(tx *gorm.DB, batchRepo repositories.StockBatchRepository, productID, warehouseID uint, qty int) ([]fifoUsage, error) {
+	batches, err := batchRepo.GetAvailableBatches(tx, productID, warehouseID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil batch produk %d: %w", productID, err)
+	}
+
+	totalAvailable := 0
+	for _, b := range batches {
+		totalAvailable += b.JumlahSaatIni
+	}
+	if totalAvailable < qty {
+		return nil, fmt.Errorf("stok tidak cukup untuk produk ID %d (dibutuhkan: %d, tersedia: %d)",
+			productID, qty, totalAvailable)
+	}
+
+	var usages []fifoUsage
+	remaining := qty
+	for i := range batches {
+		if remaining == 0 {
+			break
+		}
+		batch := &batches[i]
+		deduct := batch.JumlahSaatIni
+		if deduct > remaining {
+			deduct = remaining
+		}
+		remaining -= deduct
+
+		batch.JumlahSaatIni -= deduct
+		if err := batchRepo.Update(tx, batch); err != nil {
+			return nil, fmt.Errorf("gagal update batch #%d: %w", batch.ID, err)
+		}
+		usages = append(usages, fifoUsage{Batch: *batch, Jumlah: deduct})
+	}
+
+	return usages, nil
+}
+
+// consumeSaleItemFIFO mengeluarkan stok satu item penjualan (POS maupun pesanan) secara FIFO:
+// batch dikurangi, pergerakan stok dicatat per batch, lalu saldo stok_inventori diperbarui.
+// Mengembalikan breakdown batch untuk item_penjualan_batch beserta total HPP item.
+func consumeSaleItemFIFO(
+	tx *gorm.DB,
+	stockRepo repositories.StockRepository,
+	batchRepo repositories.StockBatchRepository,
+	productID, warehouseID uint,
+	qty int,
+	userID uint,
+	refType string,
+	refID uint,
+	label string,
+	now time.Time,
+) ([]models.ItemPenjualanBatch, float64, error) {
+	usages, err := deductFIFO(tx, batchRepo, productID, warehouseID, qty)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var records []models.ItemPenjualanBatch
+	var totalCOGS float64
+	for _, usage := range usages {
+		batchID := usage.Batch.ID
+		totalCOGSBatch := math.Round(usage.Batch.HargaModal*float64(usage.Jumlah)*100) / 100
+		totalCOGS += totalCOGSBatch
+
+		// Catat breakdown batch untuk item ini
+		records = append(records, models.ItemPenjualanBatch{
+			IDBatch:    batchID,
+			Jumlah:     usage.Jumlah,
+			HargaModal: usage.Batch.HargaModal,
+			TotalModal: totalCOGSBatch,
+			DibuatPada: now,
+		})
+
+		// Log pergerakan stok per batch
+		movement := models.PergerakanStok{
+			IDProduk:       productID,
+			IDGudang:       warehouseID,
+			IDBatch:        &batchID,
+			TipePergerakan: "out",
+			TipeReferensi:  refType,
+			IDReferensi:    &refID,
+			Jumlah:         -usage.Jumlah,
+			IDPengguna:     userID,
+			Keterangan:     fmt.Sprintf("%s (Batch #%d, HPP: %.2f)", label, batchID, usage.Batch.HargaModal),
+			DibuatPada:     now,
+		}
+		if err := stockRepo.CreateStockMovement(tx, &movement); err != nil {
+			return nil, 0, fmt.Errorf("gagal log pergerakan stok: %w", err)
+		}
+	}
+
+	// Update total stok inventori
+	if err := stockRepo.UpdateStockBalance(tx, productID, warehouseID, -qty); err != nil {
+		return nil, 0, fmt.Errorf("gagal update stok inventori produk %d: %w", productID, err)
+	}
+
+	return records, totalCOGS, nil
+}
+
+// restoreSaleItemBatches mengembalikan qty item penjualan ke batch asal yang persis sama (HPP tetap akurat),
+// dipakai saat void penjualan POS dan pembatalan pesanan yang stoknya sudah keluar.
+func restoreSaleItemBatches(
+	tx *gorm.DB,
+	stockRepo repositories.StockRepository,
+	batchRepo repositories.StockBatchRepository,
+	item models.ItemPenjualan,
+	userID uint,
+	refType string,
+	refID uint,
+	label, alasan string,
+	now time.Time,
+) ([]stockChange, error) {
+	var changes []stockChange
+	for _, usage := range item.BatchUsage {
+		batch, err := batchRepo.FindByIDForUpdate(tx, usage.IDBatch)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil batch #%d: %w", usage.IDBatch, err)
+		}
+		batch.JumlahSaatIni += usage.Jumlah
+		batch.Aktif = true
+		if err := batchRepo.Update(tx, batch); err != nil {
+			return nil, fmt.Errorf("gagal mengembalikan batch #%d: %w", batch.ID, err)
+		}
+
+		if err := stockRepo.UpdateStockBalance(tx, item.IDProduk, item.IDGudang, usage.Jumlah); err != nil {
+			return nil, fmt.Errorf("gagal update stok inventori produk %d: %w", item.IDProduk, err)
+		}
+		changes = append(changes, stockChange{ProductID: item.IDProduk, WarehouseID: item.IDGudang, Delta: usage.Jumlah})
+
+		batchID := batch.ID
+		movement := models.PergerakanStok{
+			IDProduk:       item.IDProduk,
+			IDGudang:       item.IDGudang,
+			IDBatch:        &batchID,
+			TipePergerakan: "in",
+			TipeReferensi:  refType,
+			IDReferensi:    &refID,
+			Jumlah:         usage.Jumlah,
+			IDPengguna:     userID,
+			Keterangan:     fmt.Sprintf("%s (Batch #%d dikembalikan) — %s", label, batchID, alasan),
+			DibuatPada:     now,
+		}
+		if err := stockRepo.CreateStockMovement(tx, &movement); err != nil {
+			return nil, fmt.Errorf("gagal log pergerakan stok: %w", err)
+		}
+	}
+	return changes, nil
+}
+
+// cloneBatchForWarehouse membuat batch baru di gudang tujuan yang mewarisi TanggalMasuk,
+// TanggalKadaluarsa dan HargaModal batch asal — urutan FIFO & HPP tetap terjaga setelah dipindah.
+func cloneBatchForWarehouse(source models.StokBatch, warehouseID uint, qty int, note string) models.StokBatch {
+	return models.StokBatch{
+		IDProduk:          source.IDProduk,
+		IDGudang:          warehouseID,
+		TanggalMasuk:      source.TanggalMasuk,
+		TanggalKadaluarsa: source.TanggalKadaluarsa,
+		JumlahAwal:        qty,
+		JumlahSaatIni:     qty,
+		HargaModal:        source.HargaModal,
+		TipeReferensi:     "transfer",
+		Aktif:             true,
+		Keterangan:        note,
+	}
+}
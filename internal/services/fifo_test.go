@@ -0,0 +1,39 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCloneBatchForWarehouse(t *testing.T) {
+	masuk := time.Date(2024, 11, 2, 8, 0, 0, 0, time.UTC)
+	source := models.StokBatch{
+		ID:            12,
+		IDProduk:      4,
+		IDGudang:      1,
+		TanggalMasuk:  masuk,
+		JumlahAwal:    20,
+		JumlahSaatIni: 5,
+		HargaModal:    875000,
+		TipeReferensi: "stock_in",
+	}
+
+	clone := cloneBatchForWarehouse(source, 2, 3, "Transfer dari batch #12")
+
+	if clone.ID != 0 {
+		t.Errorf("Expected new batch (ID 0), got %d", clone.ID)
+	}
+	if clone.IDGudang != 2 || clone.IDProduk != 4 {
+		t.Errorf("Expected produk 4 di gudang 2, got produk %d gudang %d", clone.IDProduk, clone.IDGudang)
+	}
+	if !clone.TanggalMasuk.Equal(masuk) {
+		t.Errorf("Expected tanggal masuk %v (FIFO order), got %v", masuk, clone.TanggalMasuk)
+	}
+	if clone.HargaModal != 875000 {
+		t.Errorf("Expected harga modal 875000, got %.2f", clone.HargaModal)
+	}
+	if clone.JumlahAwal != 3 || clone.JumlahSaatIni != 3 || !clone.Aktif {
+		t.Errorf("Expected batch aktif dengan qty 3, got awal %d saat ini %d aktif %v", clone.JumlahAwal, clone.JumlahSaatIni, clone.Aktif)
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultTerminHutang adalah termin pembayaran default jika PO tidak punya tanggal jatuh tempo
+const defaultTerminHutang = 30 * 24 * time.Hour
+
+type FinanceService interface {
+	// Hutang Supplier
+	ListHutang(req *dto.ListHutangRequest) ([]dto.HutangResponse, int64, error)
+	GetHutangByID(id uint) (*dto.HutangResponse, error)
+	CreatePembayaranHutang(id, userID uint, req *dto.CreatePembayaranHutangRequest, buktiPath string) (*dto.HutangResponse, error)
+	GetAgingHutang(req *dto.AgingReportRequest) (*dto.AgingHutangResponse, error)
+}
+
+type financeService struct {
+	hutangRepo repositories.HutangRepository
+}
+
+func NewFinanceService(hutangRepo repositories.HutangRepository) FinanceService {
+	return &financeService{
+		hutangRepo: hutangRepo,
+	}
+}
+
+// newHutangFromBarangMasuk menyusun hutang supplier dari dokumen barang masuk yang sudah approved.
+// Nilai hutang = Σ (jumlah × harga satuan) item barang masuk.
+func newHutangFromBarangMasuk(header *models.BarangMasuk, jatuhTempo *time.Time) *models.HutangPemasok {
+	var total float64
+	for _, item := range header.Items {
+		total += float64(item.Jumlah) * item.HargaSatuan
+	}
+	if jatuhTempo == nil {
+		due := header.DiterimaPada.Add(defaultTerminHutang)
+		jatuhTempo = &due
+	}
+	return &models.HutangPemasok{
+		IDPemasok:      *header.IDPemasok,
+		IDPO:           header.IDPO,
+		IDBarangMasuk:  &header.ID,
+		Jumlah:         total,
+		SisaHutang:     total,
+		JatuhTempo:     jatuhTempo,
+		Status:         "unpaid",
+		DibuatPada:     header.DibuatPada,
+		DiperbaruiPada: header.DibuatPada,
+	}
+}
+
+// daysOverdue menghitung jumlah hari lewat jatuh tempo relatif terhadap asOf (0 jika belum lewat)
+func daysOverdue(jatuhTempo *time.Time, asOf time.Time) int {
+	if jatuhTempo == nil {
+		return 0
+	}
+	due := time.Date(jatuhTempo.Year(), jatuhTempo.Month(), jatuhTempo.Day(), 0, 0, 0, 0, asOf.Location())
+	today := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location())
+	days := int(today.Sub(due).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// addToAgingBucket menambahkan sisa ke kelompok umur yang sesuai.
+// Dipakai bersama untuk laporan umur hutang maupun piutang.
+func addToAgingBucket(bucket *dto.AgingBucket, sisa float64, jatuhTempo *time.Time, asOf time.Time) {
+	days := daysOverdue(jatuhTempo, asOf)
+	switch {
+	case days == 0:
+		bucket.BelumJatuhTempo += sisa
+	case days <= 30:
+		bucket.Hari1Sampai30 += sisa
+	case days <= 60:
+		bucket.Hari31Sampai60 += sisa
+	case days <= 90:
+		bucket.Hari61Sampai90 += sisa
+	default:
+		bucket.LebihDari90 += sisa
+	}
+	bucket.Total += sisa
+}
+
+// ===========================
+// HUTANG SUPPLIER
+// ===========================
+
+func (s *financeService) ListHutang(req *dto.ListHutangRequest) ([]dto.HutangResponse, int64, error) {
+	hutangs, total, err := s.hutangRepo.FindAll(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := time.Now()
+	var responses []dto.HutangResponse
+	for _, h := range hutangs {
+		responses = append(responses, *mapHutangToResponse(&h, now))
+	}
+	return responses, total, nil
+}
+
+func (s *financeService) GetHutangByID(id uint) (*dto.HutangResponse, error) {
+	hutang, err := s.hutangRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("hutang tidak ditemukan")
+		}
+		return nil, err
+	}
+	return mapHutangToResponse(hutang, time.Now()), nil
+}
+
+// CreatePembayaranHutang mencatat pembayaran (sebagian / pelunasan) atas satu hutang supplier.
+// Status berubah otomatis: unpaid → partially_paid → paid.
+func (s *financeService) CreatePembayaranHutang(id, userID uint, req *dto.CreatePembayaranHutangRequest, buktiPath string) (*dto.HutangResponse, error) {
+	tx := s.hutangRepo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	hutang, err := s.hutangRepo.FindByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("hutang tidak ditemukan")
+		}
+		return nil, err
+	}
+	if hutang.Status == "paid" {
+		tx.Rollback()
+		return nil, errors.New("hutang sudah lunas")
+	}
+	// Toleransi pembulatan 1 sen agar pelunasan penuh tidak ditolak karena floating point
+	if req.Jumlah-hutang.SisaHutang > 0.005 {
+		tx.Rollback()
+		return nil, fmt.Errorf("jumlah pembayaran (%.2f) melebihi sisa hutang (%.2f)", req.Jumlah, hutang.SisaHutang)
+	}
+
+	now := time.Now()
+	tanggalBayar := now
+	if req.TanggalBayar != nil {
+		tanggalBayar = *req.TanggalBayar
+	}
+
+	pembayaran := models.PembayaranHutang{
+		IDHutang:         hutang.ID,
+		Jumlah:           req.Jumlah,
+		MetodePembayaran: req.MetodePembayaran,
+		TanggalBayar:     tanggalBayar,
+		BuktiPembayaran:  buktiPath,
+		Keterangan:       req.Keterangan,
+		DibayarOleh:      userID,
+		DibuatPada:       now,
+	}
+	if err := s.hutangRepo.CreatePembayaran(tx, &pembayaran); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat pembayaran hutang: %w", err)
+	}
+
+	hutang.JumlahDibayar += req.Jumlah
+	hutang.SisaHutang = math.Max(0, hutang.Jumlah-hutang.JumlahDibayar)
+	if hutang.SisaHutang < 0.005 {
+		hutang.SisaHutang = 0
+		hutang.Status = "paid"
+	} else {
+		hutang.Status = "partially_paid"
+	}
+	if buktiPath != "" {
+		hutang.BuktiPembayaran = buktiPath
+	}
+	hutang.DibayarPada = &tanggalBayar
+	hutang.DibayarOleh = &userID
+	hutang.DiperbaruiPada = now
+
+	if err := s.hutangRepo.UpdatePayment(tx, hutang); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengupdate saldo hutang: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetHutangByID(id)
+}
+
+// GetAgingHutang menyusun laporan umur hutang (belum jatuh tempo, 1-30, 31-60, 61-90, >90 hari)
+// per akhir hari tanggal acuan: sisa hutang dihitung dari pembayaran yang tanggal bayarnya sampai tanggal tersebut.
+func (s *financeService) GetAgingHutang(req *dto.AgingReportRequest) (*dto.AgingHutangResponse, error) {
+	asOf := time.Now()
+	if req.Tanggal != nil {
+		asOf = *req.Tanggal
+	}
+	endOfDay := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 23, 59, 59, 999999999, asOf.Location())
+
+	hutangs, err := s.hutangRepo.FindOutstanding(req.IDPemasok, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	hutangs = hutangOutstandingAsOf(hutangs, endOfDay)
+
+	result := &dto.AgingHutangResponse{Tanggal: asOf}
+	rowIndex := make(map[uint]int)
+	for _, h := range hutangs {
+		idx, ok := rowIndex[h.IDPemasok]
+		if !ok {
+			result.PerPemasok = append(result.PerPemasok, dto.AgingPemasokRow{
+				IDPemasok:   h.IDPemasok,
+				NamaPemasok: h.Pemasok.Nama,
+			})
+			idx = len(result.PerPemasok) - 1
+			rowIndex[h.IDPemasok] = idx
+		}
+		row := &result.PerPemasok[idx]
+		row.JumlahNota++
+		addToAgingBucket(&row.Aging, h.SisaHutang, h.JatuhTempo, asOf)
+		addToAgingBucket(&result.Ringkasan, h.SisaHutang, h.JatuhTempo, asOf)
+	}
+
+	return result, nil
+}
+
+// hutangOutstandingAsOf menghitung ulang sisa hutang dari pembayaran sampai `asOf` dan membuang hutang yang sudah lunas
+func hutangOutstandingAsOf(hutangs []models.HutangPemasok, asOf time.Time) []models.HutangPemasok {
+	open := hutangs[:0]
+	for _, h := range hutangs {
+		var dibayar float64
+		for _, p := range h.Pembayaran {
+			if !p.TanggalBayar.After(asOf) {
+				dibayar += p.Jumlah
+			}
+		}
+		h.JumlahDibayar = math.Round(dibayar*100) / 100
+		h.SisaHutang = math.Round((h.Jumlah-dibayar)*100) / 100
+		if h.SisaHutang > 0.005 {
+			open = append(open, h)
+		}
+	}
+	return open
+}
+
+// ===========================
+// MAPPING HELPERS
+// ===========================
+
+func mapHutangToResponse(h *models.HutangPemasok, asOf time.Time) *dto.HutangResponse {
+	var pembayaran []dto.PembayaranHutangResponse
+	for _, p := range h.Pembayaran {
+		pembayaran = append(pembayaran, dto.PembayaranHutangResponse{
+			ID:               p.ID,
+			Jumlah:           p.Jumlah,
+			MetodePembayaran: p.MetodePembayaran,
+			TanggalBayar:     p.TanggalBayar,
+			BuktiPembayaran:  p.BuktiPembayaran,
+			Keterangan:       p.Keterangan,
+			NamaPembayar:     p.DibayarOlehPengguna.Nama,
+			DibuatPada:       p.DibuatPada,
+		})
+	}
+	hariTerlambat := 0
+	if h.Status != "paid" {
+		hariTerlambat = daysOverdue(h.JatuhTempo, asOf)
+	}
+	return &dto.HutangResponse{
+		ID:              h.ID,
+		IDPemasok:       h.IDPemasok,
+		NamaPemasok:     h.Pemasok.Nama,
+		IDPO:            h.IDPO,
+		IDBarangMasuk:   h.IDBarangMasuk,
+		Jumlah:          h.Jumlah,
+		JumlahDibayar:   h.JumlahDibayar,
+		SisaHutang:      h.SisaHutang,
+		JatuhTempo:      h.JatuhTempo,
+		HariTerlambat:   hariTerlambat,
+		Status:          h.Status,
+		BuktiPembayaran: h.BuktiPembayaran,
+		DibayarPada:     h.DibayarPada,
+		DibuatPada:      h.DibuatPada,
+		Pembayaran:      pembayaran,
+	}
+}
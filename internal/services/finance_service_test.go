@@ -0,0 +1,101 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"testing"
+	"time"
+)
+
+func TestAddToAgingBucket(t *testing.T) {
+	asOf := time.Date(2025, 6, 30, 10, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) *time.Time {
+		d := asOf.AddDate(0, 0, -n)
+		return &d
+	}
+
+	var bucket dto.AgingBucket
+	addToAgingBucket(&bucket, 100, nil, asOf)         // Tanpa jatuh tempo
+	addToAgingBucket(&bucket, 200, daysAgo(-5), asOf) // Jatuh tempo 5 hari lagi
+	addToAgingBucket(&bucket, 300, daysAgo(1), asOf)
+	addToAgingBucket(&bucket, 400, daysAgo(45), asOf)
+	addToAgingBucket(&bucket, 500, daysAgo(90), asOf)
+	addToAgingBucket(&bucket, 600, daysAgo(91), asOf)
+
+	if bucket.BelumJatuhTempo != 300 {
+		t.Errorf("Expected belum jatuh tempo 300, got %.2f", bucket.BelumJatuhTempo)
+	}
+	if bucket.Hari1Sampai30 != 300 {
+		t.Errorf("Expected 1-30 = 300, got %.2f", bucket.Hari1Sampai30)
+	}
+	if bucket.Hari31Sampai60 != 400 {
+		t.Errorf("Expected 31-60 = 400, got %.2f", bucket.Hari31Sampai60)
+	}
+	if bucket.Hari61Sampai90 != 500 {
+		t.Errorf("Expected 61-90 = 500, got %.2f", bucket.Hari61Sampai90)
+	}
+	if bucket.LebihDari90 != 600 {
+		t.Errorf("Expected >90 = 600, got %.2f", bucket.LebihDari90)
+	}
+	if bucket.Total != 2100 {
+		t.Errorf("Expected total 2100, got %.2f", bucket.Total)
+	}
+}
+
+func TestNewHutangFromBarangMasuk(t *testing.T) {
+	idPemasok := uint(7)
+	diterima := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	header := &models.BarangMasuk{
+		ID:           3,
+		IDPemasok:    &idPemasok,
+		DiterimaPada: diterima,
+		Items: []models.ItemBarangMasuk{
+			{Jumlah: 2, HargaSatuan: 150000},
+			{Jumlah: 5, HargaSatuan: 20000},
+		},
+	}
+
+	hutang := newHutangFromBarangMasuk(header, nil)
+
+	if hutang.Jumlah != 400000 || hutang.SisaHutang != 400000 {
+		t.Errorf("Expected jumlah & sisa 400000, got %.2f / %.2f", hutang.Jumlah, hutang.SisaHutang)
+	}
+	if hutang.Status != "unpaid" {
+		t.Errorf("Expected status unpaid, got %s", hutang.Status)
+	}
+	if hutang.JatuhTempo == nil || !hutang.JatuhTempo.Equal(diterima.AddDate(0, 0, 30)) {
+		t.Errorf("Expected default jatuh tempo +30 hari, got %v", hutang.JatuhTempo)
+	}
+	if hutang.IDBarangMasuk == nil || *hutang.IDBarangMasuk != 3 {
+		t.Error("Expected hutang terhubung ke barang masuk #3")
+	}
+}
+
+func TestHutangOutstandingAsOf(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 10, 0, 0, 0, time.UTC) }
+	asOf := time.Date(2025, 6, 10, 23, 59, 59, 0, time.UTC)
+	hutangs := []models.HutangPemasok{
+		// Lunas tanggal 20: per tanggal 10 baru dibayar cicilan pertama
+		{ID: 1, Jumlah: 1000000, JumlahDibayar: 1000000, SisaHutang: 0, Status: "paid", Pembayaran: []models.PembayaranHutang{
+			{Jumlah: 400000, TanggalBayar: day(5)},
+			{Jumlah: 600000, TanggalBayar: day(20)},
+		}},
+		// Belum ada pembayaran sama sekali
+		{ID: 2, Jumlah: 250000, SisaHutang: 250000, Status: "unpaid"},
+		// Sudah lunas sebelum tanggal acuan
+		{ID: 3, Jumlah: 300000, Status: "paid", Pembayaran: []models.PembayaranHutang{
+			{Jumlah: 300000, TanggalBayar: day(8)},
+		}},
+	}
+
+	open := hutangOutstandingAsOf(hutangs, asOf)
+	if len(open) != 2 {
+		t.Fatalf("Expected 2 open payables, got %d", len(open))
+	}
+	if open[0].ID != 1 || open[0].SisaHutang != 600000 || open[0].JumlahDibayar != 400000 {
+		t.Errorf("Hutang 1: expected sisa 600000 & dibayar 400000, got %.2f & %.2f", open[0].SisaHutang, open[0].JumlahDibayar)
+	}
+	if open[1].ID != 2 || open[1].SisaHutang != 250000 {
+		t.Errorf("Hutang 2: expected sisa 250000, got %.2f", open[1].SisaHutang)
+	}
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// pelangganHistoryLimit adalah jumlah penjualan / retur terakhir yang ditampilkan di detail pelanggan
+const pelangganHistoryLimit = 10
+
+// Nama pelanggan generik yang tidak dijadikan master pelanggan saat migrasi (kecuali ada nomor telepon)
+var genericPelangganNames = map[string]bool{
+	"-":              true,
+	"umum":           true,
+	"pelanggan umum": true,
+	"walk in":        true,
+	"walk-in":        true,
+}
+
+type PelangganService interface {
+	CreatePelanggan(req *dto.CreatePelangganRequest) (*dto.PelangganResponse, error)
+	GetPelangganByID(id uint) (*dto.PelangganDetailResponse, error)
+	ListPelanggan(req *dto.ListPelangganRequest) (*dto.ListPelangganResponse, error)
+	UpdatePelanggan(id uint, req *dto.UpdatePelangganRequest) (*dto.PelangganResponse, error)
+	DeletePelanggan(id uint) error
+	BackfillFromSales() (*dto.BackfillPelangganResult, error)
+}
+
+type pelangganService struct {
+	repo repositories.PelangganRepository
+}
+
+func NewPelangganService(repo repositories.PelangganRepository) PelangganService {
+	return &pelangganService{repo: repo}
+}
+
+func (s *pelangganService) CreatePelanggan(req *dto.CreatePelangganRequest) (*dto.PelangganResponse, error) {
+	if err := s.ensureTeleponUnique(req.Telepon, 0); err != nil {
+		return nil, err
+	}
+
+	pelanggan := &models.Pelanggan{
+		Nama:           strings.TrimSpace(req.Nama),
+		Telepon:        strings.TrimSpace(req.Telepon),
+		Email:          req.Email,
+		Alamat:         req.Alamat,
+		Catatan:        req.Catatan,
+		Aktif:          true,
+		DibuatPada:     time.Now(),
+		DiperbaruiPada: time.Now(),
+	}
+
+	if err := s.repo.Create(pelanggan); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(pelanggan), nil
+}
+
+func (s *pelangganService) GetPelangganByID(id uint) (*dto.PelangganDetailResponse, error) {
+	pelanggan, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pelanggan tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	summary, err := s.repo.PurchaseSummary(id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung riwayat belanja: %w", err)
+	}
+	sales, err := s.repo.RecentSales(id, pelangganHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil penjualan pelanggan: %w", err)
+	}
+	returns, err := s.repo.RecentReturns(id, pelangganHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil retur pelanggan: %w", err)
+	}
+
+	saleHistory := make([]dto.PelangganSaleHistory, len(sales))
+	for i, sale := range sales {
+		saleHistory[i] = dto.PelangganSaleHistory{
+			ID:             sale.ID,
+			NomorTransaksi: sale.NomorTransaksi,
+			NamaGudang:     sale.Gudang.Nama,
+			Total:          sale.Total,
+			Status:         sale.Status,
+			DibuatPada:     sale.DibuatPada,
+		}
+	}
+	returnHistory := make([]dto.PelangganReturnHistory, len(returns))
+	for i, r := range returns {
+		returnHistory[i] = dto.PelangganReturnHistory{
+			ID:             r.ID,
+			NomorRetur:     r.NomorRetur,
+			NomorTransaksi: r.Penjualan.NomorTransaksi,
+			Alasan:         r.Alasan,
+			Total:          r.Total,
+			Status:         r.Status,
+			DibuatPada:     r.DibuatPada,
+		}
+	}
+
+	return &dto.PelangganDetailResponse{
+		PelangganResponse: *s.toResponse(pelanggan),
+		TotalTransaksi:    summary.TotalTransaksi,
+		TotalBelanja:      math.Round(summary.TotalBelanja*100) / 100,
+		TotalRetur:        summary.TotalRetur,
+		NilaiRetur:        math.Round(summary.NilaiRetur*100) / 100,
+		BelanjaBersih:     math.Round((summary.TotalBelanja-summary.NilaiRetur)*100) / 100,
+		PembelianPertama:  summary.PembelianPertama,
+		PembelianTerakhir: summary.PembelianTerakhir,
+		PenjualanTerakhir: saleHistory,
+		ReturTerakhir:     returnHistory,
+	}, nil
+}
+
+func (s *pelangganService) ListPelanggan(req *dto.ListPelangganRequest) (*dto.ListPelangganResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	filters := make(map[string]interface{})
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+	if req.Aktif != nil {
+		filters["aktif"] = *req.Aktif
+	}
+
+	pelangganList, total, err := s.repo.List(filters, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PelangganResponse, len(pelangganList))
+	for i, p := range pelangganList {
+		responses[i] = *s.toResponse(&p)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
+
+	return &dto.ListPelangganResponse{
+		Pelanggan:  responses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *pelangganService) UpdatePelanggan(id uint, req *dto.UpdatePelangganRequest) (*dto.PelangganResponse, error) {
+	pelanggan, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pelanggan tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	if req.Nama != nil {
+		pelanggan.Nama = strings.TrimSpace(*req.Nama)
+	}
+	if req.Telepon != nil {
+		if err := s.ensureTeleponUnique(*req.Telepon, id); err != nil {
+			return nil, err
+		}
+		pelanggan.Telepon = strings.TrimSpace(*req.Telepon)
+	}
+	if req.Email != nil {
+		pelanggan.Email = *req.Email
+	}
+	if req.Alamat != nil {
+		pelanggan.Alamat = *req.Alamat
+	}
+	if req.Catatan != nil {
+		pelanggan.Catatan = *req.Catatan
+	}
+	if req.Aktif != nil {
+		pelanggan.Aktif = *req.Aktif
+	}
+
+	pelanggan.DiperbaruiPada = time.Now()
+
+	if err := s.repo.Update(pelanggan); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(pelanggan), nil
+}
+
+// DeletePelanggan melakukan soft delete; penjualan lama tetap menyimpan id_pelanggan dan snapshot nama
+func (s *pelangganService) DeletePelanggan(id uint) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("pelanggan tidak ditemukan")
+		}
+		return err
+	}
+
+	return s.repo.Delete(id)
+}
+
+// BackfillFromSales membuat master pelanggan dari nama/kontak free-text pada penjualan lama
+// lalu mengisi id_pelanggan di penjualan dan retur penjualan. Aman dijalankan berulang:
+// hanya transaksi yang belum ter-link yang diproses, dan pelanggan yang sudah ada dipakai ulang.
+func (s *pelangganService) BackfillFromSales() (*dto.BackfillPelangganResult, error) {
+	existing, err := s.repo.FindAllForMatching()
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pelanggan: %w", err)
+	}
+	rows, err := s.repo.UnlinkedSaleCustomers()
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nama pelanggan penjualan: %w", err)
+	}
+
+	// Pelanggan yang sudah ada bisa dicocokkan lewat telepon maupun nama
+	index := make(map[string]uint)
+	for _, p := range existing {
+		for _, key := range []string{pelangganKey("", p.Telepon), pelangganKey(p.Nama, "")} {
+			if _, ok := index[key]; key != "" && !ok {
+				index[key] = p.ID
+			}
+		}
+	}
+
+	result := &dto.BackfillPelangganResult{}
+
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	for _, row := range rows {
+		key := pelangganKey(row.NamaPelanggan, row.KontakPelanggan)
+		if key == "" {
+			result.NamaDilewati++
+			continue
+		}
+
+		id, ok := index[key]
+		if !ok {
+			pelanggan := &models.Pelanggan{
+				Nama:           backfillPelangganNama(row.NamaPelanggan, row.KontakPelanggan),
+				Catatan:        "Dibuat otomatis dari riwayat penjualan",
+				Aktif:          true,
+				DibuatPada:     now,
+				DiperbaruiPada: now,
+			}
+			if normalizeTelepon(row.KontakPelanggan) != "" {
+				pelanggan.Telepon = strings.TrimSpace(row.KontakPelanggan)
+			}
+			if err := s.repo.CreateTx(tx, pelanggan); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("gagal membuat pelanggan %q: %w", pelanggan.Nama, err)
+			}
+			id = pelanggan.ID
+			index[key] = id
+			result.PelangganDibuat++
+		}
+
+		linked, err := s.repo.LinkSales(tx, id, row.NamaPelanggan, row.KontakPelanggan)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal menghubungkan penjualan: %w", err)
+		}
+		result.PenjualanDilink += linked
+	}
+
+	linkedReturns, err := s.repo.LinkReturnsFromSales(tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal menghubungkan retur penjualan: %w", err)
+	}
+	result.ReturDilink = linkedReturns
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit migrasi pelanggan: %w", err)
+	}
+	return result, nil
+}
+
+// ensureTeleponUnique menolak nomor telepon yang sudah dipakai pelanggan lain
+func (s *pelangganService) ensureTeleponUnique(telepon string, excludeID uint) error {
+	normalized := normalizeTelepon(telepon)
+	if normalized == "" {
+		return nil
+	}
+	existing, err := s.repo.FindByTeleponDigits(teleponVariants(normalized), excludeID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.New("nomor telepon sudah dipakai pelanggan lain")
+	}
+	return nil
+}
+
+func (s *pelangganService) toResponse(p *models.Pelanggan) *dto.PelangganResponse {
+	return &dto.PelangganResponse{
+		ID:             p.ID,
+		Nama:           p.Nama,
+		Telepon:        p.Telepon,
+		Email:          p.Email,
+		Alamat:         p.Alamat,
+		Catatan:        p.Catatan,
+		Aktif:          p.Aktif,
+		DibuatPada:     p.DibuatPada,
+		DiperbaruiPada: p.DiperbaruiPada,
+	}
+}
+
+// normalizeTelepon mengambil digit nomor telepon dengan format lokal (0812...).
+// "+62 812-..." dan "812..." dianggap sama dengan "0812...". Nomor yang terlalu pendek diabaikan.
+func normalizeTelepon(telepon string) string {
+	var b strings.Builder
+	for _, r := range telepon {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	switch {
+	case strings.HasPrefix(digits, "62"):
+		digits = "0" + digits[2:]
+	case strings.HasPrefix(digits, "8"):
+		digits = "0" + digits
+	}
+	if len(digits) < 8 {
+		return ""
+	}
+	return digits
+}
+
+// teleponVariants mengembalikan bentuk digit yang mungkin tersimpan untuk nomor ter-normalisasi
+func teleponVariants(normalized string) []string {
+	return []string{normalized, "62" + normalized[1:], normalized[1:]}
+}
+
+// pelangganKey adalah kunci pengelompokan pelanggan saat migrasi: nomor telepon jika ada,
+// jika tidak nama (huruf kecil, spasi dirapikan). Nama generik tanpa telepon menghasilkan "".
+func pelangganKey(nama, kontak string) string {
+	if telepon := normalizeTelepon(kontak); telepon != "" {
+		return "telepon:" + telepon
+	}
+	normalized := strings.ToLower(strings.Join(strings.Fields(nama), " "))
+	if normalized == "" || genericPelangganNames[normalized] {
+		return ""
+	}
+	return "nama:" + normalized
+}
+
+// backfillPelangganNama memilih nama master pelanggan hasil migrasi
+func backfillPelangganNama(nama, kontak string) string {
+	nama = strings.Join(strings.Fields(nama), " ")
+	if nama == "" || genericPelangganNames[strings.ToLower(nama)] {
+		return "Pelanggan " + strings.TrimSpace(kontak)
+	}
+	return nama
+}
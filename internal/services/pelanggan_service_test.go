@@ -0,0 +1,38 @@
+package services
+
+import "testing"
+
+func TestNormalizeTelepon(t *testing.T) {
+	cases := map[string]string{
+		"0812-3456-7890":    "081234567890",
+		"+62 812 3456 7890": "081234567890",
+		"81234567890":       "081234567890",
+		"12345":             "", // terlalu pendek
+		"":                  "",
+	}
+	for input, expected := range cases {
+		if got := normalizeTelepon(input); got != expected {
+			t.Errorf("normalizeTelepon(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestPelangganKey(t *testing.T) {
+	// Telepon lebih diutamakan daripada nama (typo nama tetap satu pelanggan)
+	if pelangganKey("Budi Santoso", "0812-3456-7890") != pelangganKey("Budi Santosa", "+6281234567890") {
+		t.Error("Expected same key for same phone number")
+	}
+
+	// Tanpa telepon: nama dibandingkan tanpa memperhatikan huruf besar & spasi
+	if pelangganKey("  budi   SANTOSO ", "") != pelangganKey("Budi Santoso", "-") {
+		t.Error("Expected same key for normalized name")
+	}
+
+	// Nama generik tanpa telepon tidak dijadikan pelanggan
+	if key := pelangganKey("Umum", ""); key != "" {
+		t.Errorf("Expected empty key for generic name, got %q", key)
+	}
+	if key := pelangganKey("Umum", "081234567890"); key == "" {
+		t.Error("Expected generic name with phone number to get a key")
+	}
+}
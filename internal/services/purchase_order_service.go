@@ -0,0 +1,536 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(userID uint, req *dto.CreatePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error)
+	GetPurchaseOrderByID(id uint) (*dto.PurchaseOrderResponse, error)
+	ListPurchaseOrders(req *dto.ListPurchaseOrderRequest) ([]dto.PurchaseOrderResponse, int64, error)
+	UpdatePurchaseOrder(id uint, req *dto.UpdatePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error) // Hanya saat draft
+
+	// Status machine: draft → sent → approved → partially_received → completed / cancelled
+	SendPurchaseOrder(id uint) error
+	ApprovePurchaseOrder(id, approvedByUserID uint) error
+	CancelPurchaseOrder(id uint) error
+
+	// Penerimaan barang berdasarkan PO → BarangMasuk + batch FIFO di harga PO
+	ReceivePurchaseOrder(id, userID uint, req *dto.ReceivePurchaseOrderRequest) (*dto.ReceivePurchaseOrderResponse, error)
+}
+
+type purchaseOrderService struct {
+	repo        repositories.PurchaseOrderRepository
+	pemasokRepo repositories.PemasokRepository
+	productRepo repositories.ProductRepository
+	stockRepo   repositories.StockRepository
+	batchRepo   repositories.StockBatchRepository
+	hutangRepo  repositories.HutangRepository
+	publisher   events.Publisher
+}
+
+func NewPurchaseOrderService(
+	repo repositories.PurchaseOrderRepository,
+	pemasokRepo repositories.PemasokRepository,
+	productRepo repositories.ProductRepository,
+	stockRepo repositories.StockRepository,
+	batchRepo repositories.StockBatchRepository,
+	hutangRepo repositories.HutangRepository,
+	publisher events.Publisher,
+) PurchaseOrderService {
+	return &purchaseOrderService{
+		repo:        repo,
+		pemasokRepo: pemasokRepo,
+		productRepo: productRepo,
+		stockRepo:   stockRepo,
+		batchRepo:   batchRepo,
+		hutangRepo:  hutangRepo,
+		publisher:   publisher,
+	}
+}
+
+// poStatusTransitions mendefinisikan perpindahan status PO yang diizinkan.
+// partially_received & completed di-set otomatis oleh proses penerimaan barang.
+var poStatusTransitions = map[string][]string{
+	"draft":              {"sent", "cancelled"},
+	"sent":               {"approved", "cancelled"},
+	"approved":           {"partially_received", "completed", "cancelled"},
+	"partially_received": {"partially_received", "completed"},
+}
+
+// canTransitionPO mengecek apakah status PO boleh berpindah dari `from` ke `to`
+func canTransitionPO(from, to string) bool {
+	for _, next := range poStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePurchaseOrder membuat PO baru dengan status draft
+func (s *purchaseOrderService) CreatePurchaseOrder(userID uint, req *dto.CreatePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error) {
+	if _, err := s.pemasokRepo.FindByID(req.IDPemasok); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pemasok tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	items, total, err := s.buildItems(req.Items, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tanggalPesan := now
+	if req.TanggalPesan != nil {
+		tanggalPesan = *req.TanggalPesan
+	}
+
+	po := models.PesananPembelian{
+		NomorPO:           fmt.Sprintf("PO/%s/%d", now.Format("20060102150405"), userID),
+		IDPemasok:         req.IDPemasok,
+		TanggalPesan:      tanggalPesan,
+		TanggalJatuhTempo: req.TanggalJatuhTempo,
+		Status:            "draft",
+		Total:             total,
+		DibuatOleh:        userID,
+		DibuatPada:        now,
+		DiperbaruiPada:    now,
+		Items:             items,
+	}
+
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.repo.Create(tx, &po); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat purchase order: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetPurchaseOrderByID(po.ID)
+}
+
+func (s *purchaseOrderService) GetPurchaseOrderByID(id uint) (*dto.PurchaseOrderResponse, error) {
+	po, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase order tidak ditemukan")
+		}
+		return nil, err
+	}
+	return mapPurchaseOrderToResponse(po), nil
+}
+
+func (s *purchaseOrderService) ListPurchaseOrders(req *dto.ListPurchaseOrderRequest) ([]dto.PurchaseOrderResponse, int64, error) {
+	pos, total, err := s.repo.FindAll(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var responses []dto.PurchaseOrderResponse
+	for _, po := range pos {
+		responses = append(responses, *mapPurchaseOrderToResponse(&po))
+	}
+	return responses, total, nil
+}
+
+// UpdatePurchaseOrder mengubah header dan/atau item PO. Hanya PO berstatus draft yang bisa diubah.
+func (s *purchaseOrderService) UpdatePurchaseOrder(id uint, req *dto.UpdatePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error) {
+	po, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase order tidak ditemukan")
+		}
+		return nil, err
+	}
+	if po.Status != "draft" {
+		return nil, fmt.Errorf("purchase order sudah dalam status '%s', hanya draft yang dapat diubah", po.Status)
+	}
+
+	if req.IDPemasok != nil {
+		if _, err := s.pemasokRepo.FindByID(*req.IDPemasok); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("pemasok tidak ditemukan")
+			}
+			return nil, err
+		}
+		po.IDPemasok = *req.IDPemasok
+	}
+	if req.TanggalPesan != nil {
+		po.TanggalPesan = *req.TanggalPesan
+	}
+	if req.TanggalJatuhTempo != nil {
+		po.TanggalJatuhTempo = req.TanggalJatuhTempo
+	}
+
+	now := time.Now()
+	var newItems []models.ItemPesananPembelian
+	if len(req.Items) > 0 {
+		var total float64
+		newItems, total, err = s.buildItems(req.Items, now)
+		if err != nil {
+			return nil, err
+		}
+		po.Total = total
+	}
+	po.DiperbaruiPada = now
+
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.repo.Update(tx, po); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengupdate purchase order: %w", err)
+	}
+	if newItems != nil {
+		if err := s.repo.ReplaceItems(tx, po.ID, newItems); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal mengupdate item purchase order: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetPurchaseOrderByID(po.ID)
+}
+
+// SendPurchaseOrder menandai PO sudah dikirim ke supplier (draft → sent)
+func (s *purchaseOrderService) SendPurchaseOrder(id uint) error {
+	return s.changeStatus(id, "sent", nil, nil)
+}
+
+// ApprovePurchaseOrder menyetujui PO (sent → approved), mencatat penyetuju, dan
+// mencatat event purchase_order.approved untuk staf penerimaan barang & webhook
+func (s *purchaseOrderService) ApprovePurchaseOrder(id, approvedByUserID uint) error {
+	return s.changeStatus(id, "approved", map[string]interface{}{
+		"disetujui_oleh": approvedByUserID,
+		"disetujui_pada": time.Now(),
+	}, func(po *models.PesananPembelian) []events.Event {
+		return []events.Event{events.New(events.TypePurchaseOrderApproved, events.PurchaseOrderApproved{
+			ID:         po.ID,
+			NomorPO:    po.NomorPO,
+			SupplierID: po.IDPemasok,
+			Total:      po.Total,
+			ApprovedBy: approvedByUserID,
+		}, websocket.TopicUser(po.DibuatOleh), websocket.TopicPermission(middleware.PermPurchaseOrderReceive))}
+	})
+}
+
+// CancelPurchaseOrder membatalkan PO yang belum ada penerimaan barang
+func (s *purchaseOrderService) CancelPurchaseOrder(id uint) error {
+	return s.changeStatus(id, "cancelled", nil, nil)
+}
+
+// changeStatus memindahkan status PO; buildEvents (opsional) menghasilkan event yang dicatat di transaksi yang sama.
+// Update dijaga dengan status lama, jadi approve / cancel / penerimaan yang bersamaan hanya satu yang berhasil.
+func (s *purchaseOrderService) changeStatus(id uint, status string, extra map[string]interface{}, buildEvents func(po *models.PesananPembelian) []events.Event) error {
+	po, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("purchase order tidak ditemukan")
+		}
+		return err
+	}
+	if !canTransitionPO(po.Status, status) {
+		return fmt.Errorf("purchase order dalam status '%s', tidak dapat diubah menjadi '%s'", po.Status, status)
+	}
+
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.repo.UpdateStatus(tx, id, po.Status, status, extra); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal mengubah status purchase order: %w", err)
+	}
+
+	if buildEvents != nil {
+		if err := s.publisher.Publish(tx, buildEvents(po)...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("gagal mencatat event purchase order: %w", err)
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// resolveStatusAfterReceipt menentukan status PO setelah penerimaan:
+// completed jika semua item sudah diterima penuh, selain itu partially_received.
+func resolveStatusAfterReceipt(items []models.ItemPesananPembelian) string {
+	for _, item := range items {
+		if item.JumlahDiterima < item.Jumlah {
+			return "partially_received"
+		}
+	}
+	return "completed"
+}
+
+// ReceivePurchaseOrder mencatat penerimaan barang (sebagian/seluruhnya) dari PO yang sudah approved.
+// Setiap item yang diterima menjadi batch FIFO baru dengan HargaModal = harga PO,
+// sehingga HPP di ItemPenjualanBatch mengikuti harga beli aktual.
+// Penerimaan juga langsung membentuk hutang supplier senilai barang yang diterima.
+func (s *purchaseOrderService) ReceivePurchaseOrder(id, userID uint, req *dto.ReceivePurchaseOrderRequest) (*dto.ReceivePurchaseOrderResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock PO agar penerimaan paralel tidak melebihi qty pesanan
+	po, err := s.repo.FindByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("purchase order tidak ditemukan")
+		}
+		return nil, err
+	}
+	if po.Status != "approved" && po.Status != "partially_received" {
+		tx.Rollback()
+		return nil, fmt.Errorf("purchase order dalam status '%s', hanya PO approved yang dapat diterima", po.Status)
+	}
+
+	itemIndex := make(map[uint]int)
+	for i, item := range po.Items {
+		itemIndex[item.ID] = i
+	}
+
+	now := time.Now()
+	tanggalTerima := now
+	if req.TanggalTerima != nil {
+		tanggalTerima = *req.TanggalTerima
+	}
+
+	// 1. Validasi qty & susun item barang masuk
+	var itemsMasuk []models.ItemBarangMasuk
+	for _, itemReq := range req.Items {
+		idx, ok := itemIndex[itemReq.IDItemPO]
+		if !ok {
+			tx.Rollback()
+			return nil, fmt.Errorf("item PO ID %d tidak ditemukan pada purchase order ini", itemReq.IDItemPO)
+		}
+		poItem := &po.Items[idx]
+		sisa := poItem.Jumlah - poItem.JumlahDiterima
+		if itemReq.Jumlah > sisa {
+			tx.Rollback()
+			return nil, fmt.Errorf("jumlah diterima (%d) melebihi sisa PO (%d) untuk produk %s",
+				itemReq.Jumlah, sisa, poItem.Produk.SKU)
+		}
+		poItem.JumlahDiterima += itemReq.Jumlah
+
+		hargaPO := poItem.HargaSatuan
+		itemsMasuk = append(itemsMasuk, models.ItemBarangMasuk{
+			IDProduk:       poItem.IDProduk,
+			Jumlah:         itemReq.Jumlah,
+			HargaSatuan:    poItem.HargaSatuan,
+			HargaPO:        &hargaPO,
+			IDGudang:       req.IDGudang,
+			Lokasi:         itemReq.Lokasi,
+			DibuatPada:     now,
+			DiperbaruiPada: now,
+		})
+	}
+
+	// 2. Header barang masuk — langsung approved karena PO sudah disetujui
+	idPemasok := po.IDPemasok
+	header := models.BarangMasuk{
+		NomorTransaksi: fmt.Sprintf("IN/PO/%s/%d", now.Format("20060102150405"), userID),
+		IDPemasok:      &idPemasok,
+		IDPO:           &po.ID,
+		DiterimaOleh:   userID,
+		DiterimaPada:   tanggalTerima,
+		DisetujuiOleh:  po.DisetujuiOleh,
+		DisetujuiPada:  &now,
+		Status:         "approved",
+		Keterangan:     strings.TrimSpace(fmt.Sprintf("[PO] %s %s", po.NomorPO, req.Keterangan)),
+		DibuatPada:     now,
+		DiperbaruiPada: now,
+		Items:          itemsMasuk,
+	}
+	if err := s.stockRepo.CreateStockIn(tx, &header); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat dokumen barang masuk: %w", err)
+	}
+
+	// 3. Batch FIFO di harga PO + saldo stok + kartu stok
+	var stockChanges []stockChange
+	for i, itemReq := range req.Items {
+		item := header.Items[i]
+
+		batch := models.StokBatch{
+			IDProduk:       item.IDProduk,
+			IDGudang:       req.IDGudang,
+			TanggalMasuk:   tanggalTerima,
+			JumlahAwal:     item.Jumlah,
+			JumlahSaatIni:  item.Jumlah,
+			HargaModal:     item.HargaSatuan, // Harga beli aktual dari PO
+			IDReferensi:    &header.ID,
+			TipeReferensi:  "stock_in",
+			Aktif:          true,
+			Keterangan:     fmt.Sprintf("[PO] %s", po.NomorPO),
+			DibuatPada:     now,
+			DiperbaruiPada: now,
+		}
+		if err := s.batchRepo.Create(tx, &batch); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal membuat batch: %w", err)
+		}
+
+		if err := s.stockRepo.UpdateStockBalance(tx, item.IDProduk, req.IDGudang, item.Jumlah); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal update saldo stok: %w", err)
+		}
+		stockChanges = append(stockChanges, stockChange{ProductID: item.IDProduk, WarehouseID: req.IDGudang, Delta: item.Jumlah})
+
+		batchID := batch.ID
+		movement := models.PergerakanStok{
+			IDProduk:       item.IDProduk,
+			IDGudang:       req.IDGudang,
+			IDBatch:        &batchID,
+			TipePergerakan: "in",
+			TipeReferensi:  "po_in",
+			IDReferensi:    &header.ID,
+			Jumlah:         item.Jumlah,
+			IDPengguna:     userID,
+			Keterangan:     fmt.Sprintf("Penerimaan %s (Batch #%d)", po.NomorPO, batchID),
+			DibuatPada:     now,
+		}
+		if err := s.stockRepo.CreateStockMovement(tx, &movement); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal log pergerakan stok: %w", err)
+		}
+
+		if err := s.repo.IncrementReceived(tx, itemReq.IDItemPO, itemReq.Jumlah); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal update jumlah diterima: %w", err)
+		}
+	}
+
+	// 4. Hutang supplier senilai barang yang diterima (jatuh tempo ikut PO, default +30 hari)
+	hutang := newHutangFromBarangMasuk(&header, po.TanggalJatuhTempo)
+	if err := s.hutangRepo.Create(tx, hutang); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat hutang supplier: %w", err)
+	}
+
+	// 5. Status PO otomatis: partially_received / completed
+	if err := s.repo.UpdateStatus(tx, po.ID, po.Status, resolveStatusAfterReceipt(po.Items), nil); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengubah status purchase order: %w", err)
+	}
+
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "po_in", header.ID, stockChanges); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	poResponse, err := s.GetPurchaseOrderByID(po.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReceivePurchaseOrderResponse{
+		IDBarangMasuk:  header.ID,
+		NomorTransaksi: header.NomorTransaksi,
+		PurchaseOrder:  poResponse,
+	}, nil
+}
+
+// buildItems memvalidasi produk dan menghitung subtotal & total PO
+func (s *purchaseOrderService) buildItems(reqItems []dto.PurchaseOrderItemRequest, now time.Time) ([]models.ItemPesananPembelian, float64, error) {
+	var items []models.ItemPesananPembelian
+	var total float64
+	for _, itemReq := range reqItems {
+		if _, err := s.productRepo.FindByID(itemReq.IDProduk); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, fmt.Errorf("produk ID %d tidak ditemukan", itemReq.IDProduk)
+			}
+			return nil, 0, err
+		}
+		subtotal := itemReq.HargaSatuan * float64(itemReq.Jumlah)
+		total += subtotal
+		items = append(items, models.ItemPesananPembelian{
+			IDProduk:       itemReq.IDProduk,
+			Jumlah:         itemReq.Jumlah,
+			HargaSatuan:    itemReq.HargaSatuan,
+			Subtotal:       subtotal,
+			DibuatPada:     now,
+			DiperbaruiPada: now,
+		})
+	}
+	return items, total, nil
+}
+
+// ===========================
+// MAPPING HELPERS
+// ===========================
+
+func mapPurchaseOrderToResponse(po *models.PesananPembelian) *dto.PurchaseOrderResponse {
+	var items []dto.PurchaseOrderItemResponse
+	for _, item := range po.Items {
+		items = append(items, dto.PurchaseOrderItemResponse{
+			ID:             item.ID,
+			IDProduk:       item.IDProduk,
+			SKUProduk:      item.Produk.SKU,
+			NamaProduk:     item.Produk.Nama,
+			Jumlah:         item.Jumlah,
+			HargaSatuan:    item.HargaSatuan,
+			Subtotal:       item.Subtotal,
+			JumlahDiterima: item.JumlahDiterima,
+			SisaDiterima:   item.Jumlah - item.JumlahDiterima,
+		})
+	}
+	namaPenyetuju := ""
+	if po.DisetujuiOlehPengguna != nil {
+		namaPenyetuju = po.DisetujuiOlehPengguna.Nama
+	}
+	return &dto.PurchaseOrderResponse{
+		ID:                po.ID,
+		NomorPO:           po.NomorPO,
+		IDPemasok:         po.IDPemasok,
+		NamaPemasok:       po.Pemasok.Nama,
+		TanggalPesan:      po.TanggalPesan,
+		TanggalJatuhTempo: po.TanggalJatuhTempo,
+		Status:            po.Status,
+		Total:             po.Total,
+		NamaPembuat:       po.DibuatOlehPengguna.Nama,
+		NamaPenyetuju:     namaPenyetuju,
+		DisetujuiPada:     po.DisetujuiPada,
+		DibuatPada:        po.DibuatPada,
+		DiperbaruiPada:    po.DiperbaruiPada,
+		Items:             items,
+	}
+}
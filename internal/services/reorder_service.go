@@ -0,0 +1,357 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+)
+
+// Default parameter saran pemesanan ulang
+const (
+	defaultReorderPeriodDays = 30
+	defaultReorderLeadTime   = 7
+	defaultReorderTargetDays = 14
+)
+
+// ReorderService menghitung saran pemesanan ulang dari kecepatan penjualan dan membuat draft PO
+type ReorderService interface {
+	GetSuggestions(req *dto.ReorderSuggestionRequest) (*dto.ReorderSuggestionResponse, error)
+	GenerateDraftPurchaseOrders(userID uint, req *dto.GenerateReorderRequest) (*dto.GenerateReorderResponse, error)
+}
+
+type reorderService struct {
+	repo   repositories.ReorderRepository
+	poRepo repositories.PurchaseOrderRepository
+}
+
+func NewReorderService(repo repositories.ReorderRepository, poRepo repositories.PurchaseOrderRepository) ReorderService {
+	return &reorderService{
+		repo:   repo,
+		poRepo: poRepo,
+	}
+}
+
+// reorderLine adalah data mentah satu produk+gudang sebelum dihitung
+type reorderLine struct {
+	product   *models.Produk
+	warehouse uint
+	onHand    int
+	sold      int
+	avgDaily  float64
+	incoming  int
+}
+
+// GetSuggestions menghitung rata-rata permintaan harian, sisa hari stok dan saran qty
+// per produk+gudang lalu mengelompokkannya per pemasok.
+func (s *reorderService) GetSuggestions(req *dto.ReorderSuggestionRequest) (*dto.ReorderSuggestionResponse, error) {
+	period, leadTime, targetDays := reorderParams(req)
+	now := time.Now()
+
+	sales, err := s.repo.SalesQtySince(now.AddDate(0, 0, -period), req.IDGudang)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung penjualan: %w", err)
+	}
+	stocks, err := s.repo.StockLevels(req.IDGudang)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil stok: %w", err)
+	}
+	openPO, err := s.repo.OpenPurchaseQty()
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil PO terbuka: %w", err)
+	}
+
+	// Gabungkan stok & penjualan per produk+gudang
+	type key struct{ productID, warehouseID uint }
+	lines := make(map[key]*reorderLine)
+	line := func(productID, warehouseID uint) *reorderLine {
+		k := key{productID, warehouseID}
+		if lines[k] == nil {
+			lines[k] = &reorderLine{warehouse: warehouseID}
+		}
+		return lines[k]
+	}
+	for _, st := range stocks {
+		line(st.IDProduk, st.IDGudang).onHand = st.Jumlah
+	}
+	for _, row := range sales {
+		line(row.IDProduk, row.IDGudang).sold = row.Jumlah
+	}
+
+	productIDs := make([]uint, 0, len(lines))
+	warehouseIDs := make([]uint, 0)
+	seenProduct := make(map[uint]bool)
+	seenWarehouse := make(map[uint]bool)
+	for k := range lines {
+		if !seenProduct[k.productID] {
+			seenProduct[k.productID] = true
+			productIDs = append(productIDs, k.productID)
+		}
+		if !seenWarehouse[k.warehouseID] {
+			seenWarehouse[k.warehouseID] = true
+			warehouseIDs = append(warehouseIDs, k.warehouseID)
+		}
+	}
+
+	products, err := s.repo.FindProducts(productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil produk: %w", err)
+	}
+	productByID := make(map[uint]*models.Produk, len(products))
+	for i := range products {
+		productByID[products[i].ID] = &products[i]
+	}
+	warehouses, err := s.repo.FindWarehouses(warehouseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil gudang: %w", err)
+	}
+	warehouseNames := make(map[uint]string, len(warehouses))
+	for _, w := range warehouses {
+		warehouseNames[w.ID] = w.Nama
+	}
+
+	// Produk nonaktif/terhapus dan produk pemasok lain diabaikan
+	var candidates []*reorderLine
+	for k, l := range lines {
+		product := productByID[k.productID]
+		if product == nil {
+			continue
+		}
+		if req.IDPemasok != nil && (product.IDPemasok == nil || *product.IDPemasok != *req.IDPemasok) {
+			continue
+		}
+		l.product = product
+		l.avgDaily = float64(l.sold) / float64(period)
+		candidates = append(candidates, l)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].product.ID != candidates[j].product.ID {
+			return candidates[i].product.ID < candidates[j].product.ID
+		}
+		return candidates[i].warehouse < candidates[j].warehouse
+	})
+	allocateIncoming(candidates, openPO, leadTime, targetDays)
+
+	groups := make(map[uint]*dto.ReorderSupplierGroup)
+	noSupplier := &dto.ReorderSupplierGroup{NamaPemasok: "Tanpa pemasok"}
+	for _, l := range candidates {
+		reorderPoint, qty := reorderQuantity(l.onHand, l.incoming, l.product.StokMinimum, l.avgDaily, leadTime, targetDays)
+		if qty == 0 && !req.TampilkanSemua {
+			continue
+		}
+
+		item := dto.ReorderSuggestionItem{
+			IDProduk:        l.product.ID,
+			SKU:             l.product.SKU,
+			NamaProduk:      l.product.Nama,
+			IDGudang:        l.warehouse,
+			NamaGudang:      warehouseNames[l.warehouse],
+			StokSaatIni:     l.onHand,
+			StokMinimum:     l.product.StokMinimum,
+			TerjualPeriode:  l.sold,
+			RataRataHarian:  math.Round(l.avgDaily*100) / 100,
+			DalamPesanan:    l.incoming,
+			TitikPesanUlang: math.Round(reorderPoint*100) / 100,
+			SaranJumlah:     qty,
+			HargaSatuan:     l.product.HargaModal,
+			Subtotal:        l.product.HargaModal * float64(qty),
+		}
+		if l.avgDaily > 0 {
+			cover := math.Round(float64(l.onHand)/l.avgDaily*10) / 10
+			item.SisaHari = &cover
+		}
+
+		group := noSupplier
+		if l.product.Pemasok != nil {
+			group = groups[l.product.Pemasok.ID]
+			if group == nil {
+				supplierID := l.product.Pemasok.ID
+				group = &dto.ReorderSupplierGroup{IDPemasok: &supplierID, NamaPemasok: l.product.Pemasok.Nama}
+				groups[supplierID] = group
+			}
+		}
+		group.Items = append(group.Items, item)
+		group.TotalNilai += item.Subtotal
+	}
+
+	response := &dto.ReorderSuggestionResponse{
+		PeriodeHari:  period,
+		LeadTimeHari: leadTime,
+		TargetHari:   targetDays,
+		DihitungPada: now,
+		Pemasok:      []dto.ReorderSupplierGroup{},
+	}
+	for _, group := range groups {
+		response.Pemasok = append(response.Pemasok, *group)
+	}
+	sort.Slice(response.Pemasok, func(i, j int) bool {
+		return response.Pemasok[i].NamaPemasok < response.Pemasok[j].NamaPemasok
+	})
+	if len(noSupplier.Items) > 0 {
+		response.Pemasok = append(response.Pemasok, *noSupplier)
+	}
+	return response, nil
+}
+
+// GenerateDraftPurchaseOrders membuat satu draft PO per pemasok dari saran pemesanan ulang dalam satu transaksi.
+// Qty produk yang sama dari beberapa gudang dijumlahkan (gudang tujuan dipilih saat penerimaan).
+func (s *reorderService) GenerateDraftPurchaseOrders(userID uint, req *dto.GenerateReorderRequest) (*dto.GenerateReorderResponse, error) {
+	suggestionReq := req.ReorderSuggestionRequest
+	suggestionReq.TampilkanSemua = false
+	suggestions, err := s.GetSuggestions(&suggestionReq)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var orders []models.PesananPembelian
+	var skipped []string
+	for _, group := range suggestions.Pemasok {
+		if group.IDPemasok == nil {
+			for _, item := range group.Items {
+				skipped = append(skipped, fmt.Sprintf("%s - %s: produk belum punya pemasok", item.SKU, item.NamaProduk))
+			}
+			continue
+		}
+
+		var items []models.ItemPesananPembelian
+		index := make(map[uint]int)
+		var total float64
+		for _, item := range group.Items {
+			if item.HargaSatuan <= 0 {
+				skipped = append(skipped, fmt.Sprintf("%s - %s (gudang %s): harga modal produk belum diisi", item.SKU, item.NamaProduk, item.NamaGudang))
+				continue
+			}
+			total += item.Subtotal
+			if i, ok := index[item.IDProduk]; ok {
+				items[i].Jumlah += item.SaranJumlah
+				items[i].Subtotal += item.Subtotal
+				continue
+			}
+			index[item.IDProduk] = len(items)
+			items = append(items, models.ItemPesananPembelian{
+				IDProduk:       item.IDProduk,
+				Jumlah:         item.SaranJumlah,
+				HargaSatuan:    item.HargaSatuan,
+				Subtotal:       item.Subtotal,
+				DibuatPada:     now,
+				DiperbaruiPada: now,
+			})
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		orders = append(orders, models.PesananPembelian{
+			NomorPO:           fmt.Sprintf("PO/%s/%d-%d", now.Format("20060102150405"), userID, len(orders)+1),
+			IDPemasok:         *group.IDPemasok,
+			TanggalPesan:      now,
+			TanggalJatuhTempo: req.TanggalJatuhTempo,
+			Status:            "draft",
+			Total:             total,
+			DibuatOleh:        userID,
+			DibuatPada:        now,
+			DiperbaruiPada:    now,
+			Items:             items,
+		})
+	}
+	if len(orders) == 0 {
+		return nil, errors.New("tidak ada saran pemesanan yang bisa dibuat menjadi purchase order")
+	}
+
+	tx := s.poRepo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for i := range orders {
+		if err := s.poRepo.Create(tx, &orders[i]); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal membuat purchase order: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	response := &dto.GenerateReorderResponse{Dilewati: skipped}
+	for _, order := range orders {
+		po, err := s.poRepo.FindByID(order.ID)
+		if err != nil {
+			return nil, err
+		}
+		response.PurchaseOrders = append(response.PurchaseOrders, *mapPurchaseOrderToResponse(po))
+	}
+	return response, nil
+}
+
+// reorderParams mengisi parameter default
+func reorderParams(req *dto.ReorderSuggestionRequest) (period, leadTime, targetDays int) {
+	period, leadTime, targetDays = req.PeriodeHari, req.LeadTimeHari, req.TargetHari
+	if period <= 0 {
+		period = defaultReorderPeriodDays
+	}
+	if leadTime <= 0 {
+		leadTime = defaultReorderLeadTime
+	}
+	if targetDays <= 0 {
+		targetDays = defaultReorderTargetDays
+	}
+	return period, leadTime, targetDays
+}
+
+// reorderTarget adalah stok yang dibutuhkan sampai barang datang ditambah target hari, plus stok minimum sebagai safety stock
+func reorderTarget(minimum int, avgDaily float64, leadTime, targetDays int) float64 {
+	return avgDaily*float64(leadTime+targetDays) + float64(minimum)
+}
+
+// reorderQuantity menghitung titik pesan ulang (rata-rata × lead time + stok minimum) dan saran qty.
+// Posisi stok = stok saat ini + qty dalam PO terbuka. Pesan hanya jika posisi <= titik pesan ulang,
+// sebanyak target dikurangi posisi stok (dibulatkan ke atas).
+func reorderQuantity(onHand, incoming, minimum int, avgDaily float64, leadTime, targetDays int) (float64, int) {
+	reorderPoint := avgDaily*float64(leadTime) + float64(minimum)
+	position := float64(onHand + incoming)
+	if position > reorderPoint {
+		return reorderPoint, 0
+	}
+
+	qty := int(math.Ceil(reorderTarget(minimum, avgDaily, leadTime, targetDays) - position))
+	if qty < 0 {
+		qty = 0
+	}
+	return reorderPoint, qty
+}
+
+// allocateIncoming membagi qty PO terbuka (per produk, belum punya gudang tujuan) ke baris gudang
+// berurutan sesuai kebutuhan masing-masing, agar qty yang sudah dipesan tidak dihitung dua kali.
+// lines harus sudah urut per produk.
+func allocateIncoming(lines []*reorderLine, openPO map[uint]int, leadTime, targetDays int) {
+	remaining := make(map[uint]int, len(openPO))
+	for productID, qty := range openPO {
+		remaining[productID] = qty
+	}
+
+	for _, l := range lines {
+		available := remaining[l.product.ID]
+		if available <= 0 {
+			continue
+		}
+		need := int(math.Ceil(reorderTarget(l.product.StokMinimum, l.avgDaily, leadTime, targetDays))) - l.onHand
+		if need <= 0 {
+			continue
+		}
+		if need > available {
+			need = available
+		}
+		l.incoming = need
+		remaining[l.product.ID] = available - need
+	}
+}
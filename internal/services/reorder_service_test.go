@@ -0,0 +1,43 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"testing"
+)
+
+func TestReorderQuantity(t *testing.T) {
+	// 2/hari, lead time 7, target 14, minimum 5 → titik pesan 19, target 47
+	point, qty := reorderQuantity(10, 0, 5, 2, 7, 14)
+	if point != 19 || qty != 37 {
+		t.Errorf("Expected point 19 and qty 37, got %v and %d", point, qty)
+	}
+
+	// Qty dalam PO terbuka ikut dihitung sebagai posisi stok
+	if _, qty := reorderQuantity(10, 10, 5, 2, 7, 14); qty != 0 {
+		t.Errorf("Expected no reorder above reorder point, got %d", qty)
+	}
+
+	// Permintaan pecahan dibulatkan ke atas
+	if _, qty := reorderQuantity(0, 0, 0, 0.5, 7, 14); qty != 11 {
+		t.Errorf("Expected qty 11, got %d", qty)
+	}
+
+	// Tanpa penjualan dan tanpa minimum tidak perlu dipesan
+	if _, qty := reorderQuantity(0, 0, 0, 0, 7, 14); qty != 0 {
+		t.Errorf("Expected qty 0 without demand, got %d", qty)
+	}
+}
+
+func TestAllocateIncoming(t *testing.T) {
+	product := &models.Produk{ID: 1, StokMinimum: 0}
+	lines := []*reorderLine{
+		{product: product, warehouse: 1, onHand: 0, avgDaily: 1},  // butuh 21
+		{product: product, warehouse: 2, onHand: 20, avgDaily: 1}, // butuh 1
+		{product: product, warehouse: 3, onHand: 0, avgDaily: 1},  // butuh 21
+	}
+	allocateIncoming(lines, map[uint]int{1: 25}, 7, 14)
+
+	if lines[0].incoming != 21 || lines[1].incoming != 1 || lines[2].incoming != 3 {
+		t.Errorf("Unexpected allocation: %d, %d, %d", lines[0].incoming, lines[1].incoming, lines[2].incoming)
+	}
+}
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"real-erp-mebel/be/internal/dto"
 	"real-erp-mebel/be/internal/models"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,6 +19,11 @@ type ReportService interface {
 	GetSalesReportByCustomer(req *dto.SalesReportRequest) (*dto.SalesReportByCustomerResponse, error)
 	GetReturnReport(req *dto.ReturnReportRequest) (*dto.ReturnReportResponse, error)
 	GetStockReport(req *dto.StockReportRequest) (*dto.StockReportResponse, error)
+
+	// Piutang pelanggan (sisa tagihan pesanan)
+	GetPiutang(req *dto.PiutangReportRequest) (*dto.PiutangReportResponse, error)
+	GetAgingPiutang(req *dto.PiutangReportRequest) (*dto.AgingPiutangResponse, error)
+	GetCustomerStatement(idPelanggan uint, req *dto.CustomerStatementRequest) (*dto.CustomerStatementResponse, error)
 }
 
 type reportService struct {
@@ -26,17 +34,23 @@ func NewReportService(db *gorm.DB) ReportService {
 	return &reportService{db: db}
 }
 
-// buildBaseQuery membangun query dasar penjualan dalam rentang tanggal & gudang yang diminta.
-func (s *reportService) buildBaseQuery(req *dto.SalesReportRequest) *gorm.DB {
+// salesReportPeriod mengembalikan awal & akhir hari periode laporan penjualan
+func salesReportPeriod(req *dto.SalesReportRequest) (time.Time, time.Time) {
 	startOfDay := time.Date(req.TanggalDari.Year(), req.TanggalDari.Month(), req.TanggalDari.Day(), 0, 0, 0, 0, time.Local)
 	endOfDay := time.Date(req.TanggalSampai.Year(), req.TanggalSampai.Month(), req.TanggalSampai.Day(), 23, 59, 59, 999999999, time.Local)
+	return startOfDay, endOfDay
+}
+
+// buildBaseQuery membangun query dasar penjualan dalam rentang tanggal & gudang yang diminta.
+func (s *reportService) buildBaseQuery(req *dto.SalesReportRequest) *gorm.DB {
+	startOfDay, endOfDay := salesReportPeriod(req)
 
 	q := s.db.Model(&models.Penjualan{}).
-		Where("status = ?", "completed").
-		Where("dibuat_pada BETWEEN ? AND ?", startOfDay, endOfDay)
+		Where("penjualan.status IN ?", models.StatusPenjualanTerjual).
+		Where("penjualan.dibuat_pada BETWEEN ? AND ?", startOfDay, endOfDay)
 
 	if req.IDGudang != nil {
-		q = q.Where("id_gudang = ?", *req.IDGudang)
+		q = q.Where("penjualan.id_gudang = ?", *req.IDGudang)
 	}
 	return q
 }
@@ -101,16 +115,28 @@ func (s *reportService) GetSalesReportByPeriod(req *dto.SalesReportRequest) (*dt
 		})
 	}
 
-	// Breakdown per metode bayar
+	// Breakdown per metode bayar dari baris pembayaran yang diterima dalam periode (basis tanggal bayar,
+	// bukan tanggal transaksi): transaksi split tender dihitung di setiap metodenya, cash sudah dikurangi
+	// kembalian, dan DP / cicilan pesanan masuk ke periode saat uangnya diterima.
 	type MetodeRow struct {
 		Metode       string
 		TotalTrx     int64
 		TotalRevenue float64
 	}
 	var metodeRows []MetodeRow
-	if err := s.buildBaseQuery(req).Select(
-		"metode_pembayaran as metode, COUNT(*) as total_trx, SUM(total) as total_revenue",
-	).Group("metode").Scan(&metodeRows).Error; err != nil {
+	startOfDay, endOfDay := salesReportPeriod(req)
+	metodeQuery := s.db.Table("pembayaran_penjualan pp").
+		Joins("JOIN penjualan ON penjualan.id = pp.id_penjualan").
+		Where("penjualan.status IN ?", models.StatusPenjualanTerjual).
+		Where("pp.dibuat_pada BETWEEN ? AND ?", startOfDay, endOfDay)
+	if req.IDGudang != nil {
+		metodeQuery = metodeQuery.Where("penjualan.id_gudang = ?", *req.IDGudang)
+	}
+	if err := metodeQuery.
+		Select("pp.metode_pembayaran as metode, COUNT(DISTINCT penjualan.id) as total_trx, SUM(pp.jumlah) as total_revenue").
+		Group("pp.metode_pembayaran").
+		Order("total_revenue DESC").
+		Scan(&metodeRows).Error; err != nil {
 		return nil, err
 	}
 
@@ -157,7 +183,7 @@ func (s *reportService) GetSalesReportByProduct(req *dto.SalesReportRequest) (*d
 	baseQ := s.db.Table("item_penjualan ip").
 		Joins("JOIN penjualan p ON p.id = ip.id_penjualan").
 		Joins("JOIN produk pr ON pr.id = ip.id_produk").
-		Where("p.status = ?", "completed").
+		Where("p.status IN ?", models.StatusPenjualanTerjual).
 		Where("p.dibuat_pada BETWEEN ? AND ?", startOfDay, endOfDay)
 
 	if req.IDGudang != nil {
@@ -223,11 +249,14 @@ func (s *reportService) GetSalesReportByProduct(req *dto.SalesReportRequest) (*d
 	}, nil
 }
 
-// GetSalesReportByCustomer mengembalikan ringkasan penjualan per nama pelanggan.
+// GetSalesReportByCustomer mengembalikan ringkasan penjualan per pelanggan.
+// Penjualan yang ter-link ke master pelanggan dikelompokkan per id_pelanggan (nama dari master);
+// penjualan lama tanpa id_pelanggan tetap dikelompokkan per nama & kontak yang diketik.
 func (s *reportService) GetSalesReportByCustomer(req *dto.SalesReportRequest) (*dto.SalesReportByCustomerResponse, error) {
 	q := s.buildBaseQuery(req)
 
 	type CustomerRow struct {
+		IDPelanggan     *uint
 		NamaPelanggan   string
 		KontakPelanggan string
 		TotalTrx        int64
@@ -237,21 +266,49 @@ func (s *reportService) GetSalesReportByCustomer(req *dto.SalesReportRequest) (*
 	var rows []CustomerRow
 
 	err := q.Select(
-		"COALESCE(NULLIF(nama_pelanggan,''), 'Umum') as nama_pelanggan, " +
-			"kontak_pelanggan, " +
+		"id_pelanggan, " +
+			"MAX(COALESCE(NULLIF(nama_pelanggan,''), 'Umum')) as nama_pelanggan, " +
+			"MAX(COALESCE(kontak_pelanggan, '')) as kontak_pelanggan, " +
 			"COUNT(*) as total_trx, " +
 			"SUM(total) as total_belanja, " +
 			"SUM(total - total_harga_modal) as total_laba",
-	).Group("nama_pelanggan, kontak_pelanggan").
+	).Group("id_pelanggan, " +
+		"CASE WHEN id_pelanggan IS NULL THEN nama_pelanggan END, " +
+		"CASE WHEN id_pelanggan IS NULL THEN kontak_pelanggan END").
 		Order("total_belanja DESC").
 		Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
 
+	// Nama & telepon pelanggan ter-link diambil dari master (bukan snapshot transaksi)
+	var ids []uint
+	for _, row := range rows {
+		if row.IDPelanggan != nil {
+			ids = append(ids, *row.IDPelanggan)
+		}
+	}
+	master := make(map[uint]models.Pelanggan)
+	if len(ids) > 0 {
+		var pelanggan []models.Pelanggan
+		if err := s.db.Unscoped().Where("id IN ?", ids).Find(&pelanggan).Error; err != nil {
+			return nil, err
+		}
+		for _, p := range pelanggan {
+			master[p.ID] = p
+		}
+	}
+
 	var customers []dto.CustomerSalesSummary
 	for _, row := range rows {
+		if row.IDPelanggan != nil {
+			if p, ok := master[*row.IDPelanggan]; ok {
+				row.NamaPelanggan = p.Nama
+				row.KontakPelanggan = p.Telepon
+			}
+		}
 		customers = append(customers, dto.CustomerSalesSummary{
+			IDPelanggan:     row.IDPelanggan,
 			NamaPelanggan:   row.NamaPelanggan,
 			KontakPelanggan: row.KontakPelanggan,
 			TotalTransaksi:  row.TotalTrx,
@@ -519,3 +576,298 @@ func (s *reportService) GetStockReport(req *dto.StockReportRequest) (*dto.StockR
 		Data:         rows,
 	}, nil
 }
+
+// ===========================
+// PIUTANG PELANGGAN
+// ===========================
+
+// openReceivables mengambil pesanan yang belum lunas per akhir hari `asOf`, diurutkan dari jatuh tempo terdekat.
+// Hanya pesanan yang sudah dibuat dan belum dibatalkan / dilunasi pada tanggal tersebut yang dihitung,
+// dan sisa tagihannya dihitung dari pembayaran yang tercatat sampai tanggal tersebut.
+func (s *reportService) openReceivables(idPelanggan *uint, asOf time.Time) ([]models.Penjualan, error) {
+	endOfDay := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 23, 59, 59, 999999999, asOf.Location())
+	q := s.db.
+		Preload("Pelanggan", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("tipe_penjualan = ? AND dibuat_pada <= ?", "pesanan", endOfDay).
+		Where("status <> ? OR dibatalkan_pada > ?", "cancelled", endOfDay).
+		Where("lunas_pada IS NULL OR lunas_pada > ?", endOfDay)
+	if idPelanggan != nil {
+		q = q.Where("id_pelanggan = ?", *idPelanggan)
+	}
+
+	var sales []models.Penjualan
+	if err := q.Order("jatuh_tempo ASC NULLS LAST, dibuat_pada ASC").Find(&sales).Error; err != nil {
+		return nil, err
+	}
+	if len(sales) == 0 {
+		return sales, nil
+	}
+
+	ids := make([]uint, len(sales))
+	for i := range sales {
+		ids[i] = sales[i].ID
+	}
+	var rows []struct {
+		IDPenjualan uint
+		Total       float64
+	}
+	err := s.db.Model(&models.PembayaranPenjualan{}).
+		Select("id_penjualan, COALESCE(SUM(jumlah), 0) as total").
+		Where("id_penjualan IN ? AND dibuat_pada <= ?", ids, endOfDay).
+		Group("id_penjualan").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	paid := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		paid[row.IDPenjualan] = row.Total
+	}
+
+	return applyPaymentsAsOf(sales, paid, endOfDay), nil
+}
+
+// applyPaymentsAsOf menyusun keadaan pesanan per tanggal acuan: total dibayar dari pembayaran sampai tanggal
+// tersebut dan status dari timestamp tahapan (pesanan yang lunas / batal setelahnya masih terbuka).
+// Pesanan tanpa sisa tagihan dibuang.
+func applyPaymentsAsOf(sales []models.Penjualan, paid map[uint]float64, asOf time.Time) []models.Penjualan {
+	open := sales[:0]
+	for _, sale := range sales {
+		sale.TotalDibayar = math.Round(paid[sale.ID]*100) / 100
+		switch {
+		case sale.DikirimPada != nil && !sale.DikirimPada.After(asOf):
+			sale.Status = "delivered"
+		case sale.DisiapkanPada != nil && !sale.DisiapkanPada.After(asOf):
+			sale.Status = "ready"
+		default:
+			sale.Status = "ordered"
+		}
+		if saleBalance(&sale) > 0.005 {
+			open = append(open, sale)
+		}
+	}
+	return open
+}
+
+// GetPiutang mengembalikan buku piutang: semua pesanan dengan sisa tagihan per tanggal acuan
+func (s *reportService) GetPiutang(req *dto.PiutangReportRequest) (*dto.PiutangReportResponse, error) {
+	asOf := time.Now()
+	if req.Tanggal != nil {
+		asOf = *req.Tanggal
+	}
+
+	sales, err := s.openReceivables(req.IDPelanggan, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PiutangReportResponse{Tanggal: asOf, Items: []dto.PiutangItem{}}
+	for i := range sales {
+		item := mapPiutangItem(&sales[i], asOf)
+		if req.JatuhTempo && item.HariTerlambat == 0 {
+			continue
+		}
+		result.TotalPiutang += item.SisaTagihan
+		result.Items = append(result.Items, item)
+	}
+	result.TotalPiutang = math.Round(result.TotalPiutang*100) / 100
+
+	return result, nil
+}
+
+// GetAgingPiutang menyusun laporan umur piutang (belum jatuh tempo, 1-30, 31-60, 61-90, >90 hari) per pelanggan.
+// Pesanan tanpa master pelanggan dikelompokkan per nama yang diketik.
+func (s *reportService) GetAgingPiutang(req *dto.PiutangReportRequest) (*dto.AgingPiutangResponse, error) {
+	asOf := time.Now()
+	if req.Tanggal != nil {
+		asOf = *req.Tanggal
+	}
+
+	sales, err := s.openReceivables(req.IDPelanggan, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.AgingPiutangResponse{Tanggal: asOf, PerPelanggan: []dto.AgingPelangganRow{}}
+	rowIndex := make(map[string]int)
+	for i := range sales {
+		item := mapPiutangItem(&sales[i], asOf)
+		key := "nama:" + strings.ToLower(strings.TrimSpace(item.NamaPelanggan))
+		if item.IDPelanggan != nil {
+			key = fmt.Sprintf("id:%d", *item.IDPelanggan)
+		}
+
+		idx, ok := rowIndex[key]
+		if !ok {
+			result.PerPelanggan = append(result.PerPelanggan, dto.AgingPelangganRow{
+				IDPelanggan:   item.IDPelanggan,
+				NamaPelanggan: item.NamaPelanggan,
+			})
+			idx = len(result.PerPelanggan) - 1
+			rowIndex[key] = idx
+		}
+		row := &result.PerPelanggan[idx]
+		row.JumlahNota++
+		addToAgingBucket(&row.Aging, item.SisaTagihan, item.JatuhTempo, asOf)
+		addToAgingBucket(&result.Ringkasan, item.SisaTagihan, item.JatuhTempo, asOf)
+	}
+
+	return result, nil
+}
+
+// GetCustomerStatement menyusun rekening koran piutang satu pelanggan: saldo awal, mutasi tagihan
+// (pesanan) & pembayaran (DP / cicilan) dalam periode, saldo akhir, dan umur sisa tagihan.
+// Pesanan yang dibatalkan beserta DP-nya tidak dihitung.
+func (s *reportService) GetCustomerStatement(idPelanggan uint, req *dto.CustomerStatementRequest) (*dto.CustomerStatementResponse, error) {
+	var pelanggan models.Pelanggan
+	if err := s.db.Unscoped().First(&pelanggan, idPelanggan).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pelanggan tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	dari := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if req.TanggalDari != nil {
+		dari = time.Date(req.TanggalDari.Year(), req.TanggalDari.Month(), req.TanggalDari.Day(), 0, 0, 0, 0, time.Local)
+	}
+	sampai := now
+	if req.TanggalSampai != nil {
+		sampai = *req.TanggalSampai
+	}
+	endOfDay := time.Date(sampai.Year(), sampai.Month(), sampai.Day(), 23, 59, 59, 999999999, time.Local)
+	if endOfDay.Before(dari) {
+		return nil, errors.New("tanggal_sampai tidak boleh sebelum tanggal_dari")
+	}
+
+	var sales []models.Penjualan
+	err := s.db.
+		Where("tipe_penjualan = ? AND status <> ? AND id_pelanggan = ?", "pesanan", "cancelled", idPelanggan).
+		Where("dibuat_pada <= ?", endOfDay).
+		Find(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []models.PembayaranPenjualan
+	err = s.db.
+		Joins("JOIN penjualan p ON p.id = pembayaran_penjualan.id_penjualan").
+		Where("p.tipe_penjualan = ? AND p.status <> ? AND p.id_pelanggan = ?", "pesanan", "cancelled", idPelanggan).
+		Where("pembayaran_penjualan.dibuat_pada <= ?", endOfDay).
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	nomorByID := make(map[uint]string)
+	var entries []dto.CustomerStatementLine
+	for _, sale := range sales {
+		nomorByID[sale.ID] = sale.NomorTransaksi
+		entries = append(entries, dto.CustomerStatementLine{
+			Tanggal:        sale.DibuatPada,
+			Jenis:          "pesanan",
+			IDPenjualan:    sale.ID,
+			NomorTransaksi: sale.NomorTransaksi,
+			Keterangan:     fmt.Sprintf("Pesanan %s", sale.NomorTransaksi),
+			Debit:          sale.Total,
+		})
+	}
+	for _, p := range payments {
+		keterangan := fmt.Sprintf("Pembayaran (%s)", p.MetodePembayaran)
+		if p.Jenis == "dp" {
+			keterangan = fmt.Sprintf("DP (%s)", p.MetodePembayaran)
+		}
+		if p.Keterangan != "" {
+			keterangan += " — " + p.Keterangan
+		}
+		entries = append(entries, dto.CustomerStatementLine{
+			Tanggal:        p.DibuatPada,
+			Jenis:          p.Jenis,
+			IDPenjualan:    p.IDPenjualan,
+			NomorTransaksi: nomorByID[p.IDPenjualan],
+			Keterangan:     keterangan,
+			Kredit:         p.Jumlah,
+		})
+	}
+
+	result := &dto.CustomerStatementResponse{
+		IDPelanggan:   pelanggan.ID,
+		NamaPelanggan: pelanggan.Nama,
+		Telepon:       pelanggan.Telepon,
+		Alamat:        pelanggan.Alamat,
+		TanggalDari:   dari,
+		TanggalSampai: sampai,
+	}
+	result.SaldoAwal, result.Mutasi = buildStatementLines(entries, dari)
+	for _, line := range result.Mutasi {
+		result.TotalDebit += line.Debit
+		result.TotalKredit += line.Kredit
+	}
+	result.TotalDebit = math.Round(result.TotalDebit*100) / 100
+	result.TotalKredit = math.Round(result.TotalKredit*100) / 100
+	result.SaldoAkhir = math.Round((result.SaldoAwal+result.TotalDebit-result.TotalKredit)*100) / 100
+
+	open, err := s.openReceivables(&idPelanggan, sampai)
+	if err != nil {
+		return nil, err
+	}
+	result.PiutangTerbuka = []dto.PiutangItem{}
+	for i := range open {
+		item := mapPiutangItem(&open[i], sampai)
+		result.PiutangTerbuka = append(result.PiutangTerbuka, item)
+		addToAgingBucket(&result.Aging, item.SisaTagihan, item.JatuhTempo, sampai)
+	}
+
+	return result, nil
+}
+
+// buildStatementLines mengurutkan mutasi, memisahkan saldo awal (mutasi sebelum `dari`)
+// dan menghitung saldo berjalan. Pada waktu yang sama tagihan dicatat sebelum pembayaran.
+func buildStatementLines(entries []dto.CustomerStatementLine, dari time.Time) (float64, []dto.CustomerStatementLine) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Tanggal.Equal(entries[j].Tanggal) {
+			return entries[i].Tanggal.Before(entries[j].Tanggal)
+		}
+		return entries[i].Debit > entries[j].Debit
+	})
+
+	var saldoAwal float64
+	lines := []dto.CustomerStatementLine{}
+	for _, e := range entries {
+		if e.Tanggal.Before(dari) {
+			saldoAwal += e.Debit - e.Kredit
+			continue
+		}
+		lines = append(lines, e)
+	}
+
+	saldo := saldoAwal
+	for i := range lines {
+		saldo += lines[i].Debit - lines[i].Kredit
+		lines[i].Saldo = math.Round(saldo*100) / 100
+	}
+	return math.Round(saldoAwal*100) / 100, lines
+}
+
+// mapPiutangItem memetakan pesanan belum lunas ke baris piutang; nama diambil dari master jika ter-link
+func mapPiutangItem(sale *models.Penjualan, asOf time.Time) dto.PiutangItem {
+	nama, kontak := sale.NamaPelanggan, sale.KontakPelanggan
+	if sale.Pelanggan != nil {
+		nama, kontak = sale.Pelanggan.Nama, sale.Pelanggan.Telepon
+	}
+	return dto.PiutangItem{
+		IDPenjualan:     sale.ID,
+		NomorTransaksi:  sale.NomorTransaksi,
+		IDPelanggan:     sale.IDPelanggan,
+		NamaPelanggan:   nama,
+		KontakPelanggan: kontak,
+		Status:          sale.Status,
+		Total:           sale.Total,
+		TotalDibayar:    sale.TotalDibayar,
+		SisaTagihan:     saleBalance(sale),
+		JatuhTempo:      sale.JatuhTempo,
+		HariTerlambat:   daysOverdue(sale.JatuhTempo, asOf),
+		DibuatPada:      sale.DibuatPada,
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"testing"
+	"time"
+)
+
+func TestBuildStatementLines(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 10, 0, 0, 0, time.UTC) }
+	entries := []dto.CustomerStatementLine{
+		{Tanggal: day(20), Jenis: "pembayaran", Kredit: 300000},
+		{Tanggal: day(10), Jenis: "dp", Kredit: 500000},
+		{Tanggal: day(10), Jenis: "pesanan", Debit: 2000000}, // Waktu sama dengan DP, tagihan dicatat dulu
+		{Tanggal: day(15), Jenis: "pesanan", Debit: 1000000},
+		{Tanggal: day(2), Jenis: "pesanan", Debit: 750000}, // Sebelum periode → saldo awal
+	}
+
+	saldoAwal, lines := buildStatementLines(entries, day(5))
+	if saldoAwal != 750000 {
+		t.Errorf("Expected saldo awal 750000, got %.2f", saldoAwal)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d", len(lines))
+	}
+	if lines[0].Jenis != "pesanan" || lines[1].Jenis != "dp" {
+		t.Errorf("Expected pesanan before dp on same timestamp, got %s, %s", lines[0].Jenis, lines[1].Jenis)
+	}
+
+	expectedSaldo := []float64{2750000, 2250000, 3250000, 2950000}
+	for i, expected := range expectedSaldo {
+		if lines[i].Saldo != expected {
+			t.Errorf("Line %d: expected saldo %.2f, got %.2f", i, expected, lines[i].Saldo)
+		}
+	}
+}
+
+func TestMapPiutangItem(t *testing.T) {
+	asOf := time.Date(2025, 6, 30, 10, 0, 0, 0, time.UTC)
+	jatuhTempo := asOf.AddDate(0, 0, -10)
+	id := uint(7)
+	sale := &models.Penjualan{
+		TipePenjualan: "pesanan",
+		Status:        "delivered",
+		IDPelanggan:   &id,
+		NamaPelanggan: "budi",
+		Pelanggan:     &models.Pelanggan{ID: id, Nama: "Budi Santoso", Telepon: "081234567890"},
+		Total:         3000000,
+		TotalDibayar:  1000000,
+		JatuhTempo:    &jatuhTempo,
+	}
+
+	item := mapPiutangItem(sale, asOf)
+	if item.NamaPelanggan != "Budi Santoso" || item.KontakPelanggan != "081234567890" {
+		t.Errorf("Expected master customer name & phone, got %q %q", item.NamaPelanggan, item.KontakPelanggan)
+	}
+	if item.SisaTagihan != 2000000 {
+		t.Errorf("Expected sisa tagihan 2000000, got %.2f", item.SisaTagihan)
+	}
+	if item.HariTerlambat != 10 {
+		t.Errorf("Expected 10 days overdue, got %d", item.HariTerlambat)
+	}
+}
+
+func TestApplyPaymentsAsOf(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 10, 0, 0, 0, time.UTC) }
+	disiapkan, dikirim, lunas, batal := day(5), day(12), day(20), day(15)
+	sales := []models.Penjualan{
+		// Lunas tanggal 20: per tanggal 10 masih ready dengan sisa dari DP saja
+		{ID: 1, TipePenjualan: "pesanan", Status: "paid", Total: 2000000, TotalDibayar: 2000000, DisiapkanPada: &disiapkan, DikirimPada: &dikirim, LunasPada: &lunas},
+		// Dibatalkan tanggal 15: per tanggal 10 masih ordered
+		{ID: 2, TipePenjualan: "pesanan", Status: "cancelled", Total: 1000000, DibatalkanPada: &batal},
+		// Sudah lunas lewat pembayaran sampai tanggal acuan
+		{ID: 3, TipePenjualan: "pesanan", Status: "delivered", Total: 500000, TotalDibayar: 500000},
+	}
+	paid := map[uint]float64{1: 500000, 3: 500000}
+
+	open := applyPaymentsAsOf(sales, paid, day(10))
+	if len(open) != 2 {
+		t.Fatalf("Expected 2 open receivables, got %d", len(open))
+	}
+	if open[0].Status != "ready" || open[0].TotalDibayar != 500000 || saleBalance(&open[0]) != 1500000 {
+		t.Errorf("Order 1: expected ready with balance 1500000, got %s %.2f", open[0].Status, saleBalance(&open[0]))
+	}
+	if open[1].Status != "ordered" || saleBalance(&open[1]) != 1000000 {
+		t.Errorf("Order 2: expected ordered with balance 1000000, got %s %.2f", open[1].Status, saleBalance(&open[1]))
+	}
+}
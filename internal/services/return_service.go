@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
 	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,13 +19,40 @@ type ReturnService interface {
 	CreateReturPenjualan(userID uint, req *dto.CreateReturPenjualanRequest) (*dto.ReturPenjualanResponse, error)
 	GetReturPenjualanByID(id uint) (*dto.ReturPenjualanResponse, error)
 	ListReturPenjualan(req *dto.ListReturPenjualanRequest) ([]dto.ReturPenjualanResponse, int64, error)
-	ApproveReturPenjualan(id, approvedByUserID uint) error // Stok masuk kembali
+	ApproveReturPenjualan(id, approvedByUserID uint) error // Barang masuk stok karantina
+	RejectReturPenjualan(id, rejectedByUserID uint, alasan string) error
+	CompleteReturPenjualan(id, userID uint) error // Refund / tukar barang sudah diselesaikan
 
 	// Retur Pembelian (Toko → Vendor)
 	CreateReturPembelian(userID uint, req *dto.CreateReturPembelianRequest) (*dto.ReturPembelianResponse, error)
 	GetReturPembelianByID(id uint) (*dto.ReturPembelianResponse, error)
 	ListReturPembelian(req *dto.ListReturPembelianRequest) ([]dto.ReturPembelianResponse, int64, error)
 	ApproveReturPembelian(id, approvedByUserID uint) error // Stok keluar via FIFO
+	RejectReturPembelian(id, rejectedByUserID uint, alasan string) error
+	CompleteReturPembelian(id, userID uint) error // Refund / potong hutang / tukar barang sudah diselesaikan
+
+	// Stok Karantina (barang retur penjualan yang sudah diapprove)
+	ListStokKarantina(req *dto.ListStokKarantinaRequest) ([]dto.StokKarantinaBatchResponse, int64, error)
+	ListDisposisiKarantina(req *dto.ListDisposisiKarantinaRequest) ([]dto.DisposisiKarantinaResponse, int64, error)
+	ReleaseKarantina(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error)  // Kembali ke stok jual
+	WriteOffKarantina(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error) // Dihapus (rusak)
+	RepairKarantina(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error)   // Dikirim perbaikan
+}
+
+// returStatusTransitions mendefinisikan transisi status retur yang valid:
+// pending → approved / rejected, approved → completed (setelah refund / tukar barang beres).
+var returStatusTransitions = map[string][]string{
+	"pending":  {"approved", "rejected"},
+	"approved": {"completed"},
+}
+
+func canTransitionRetur(from, to string) bool {
+	for _, next := range returStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
 }
 
 type returnService struct {
@@ -30,6 +60,8 @@ type returnService struct {
 	stockRepo repositories.StockRepository
 	batchRepo repositories.StockBatchRepository
 	salesRepo repositories.SalesRepository
+	shiftRepo repositories.ShiftRepository
+	publisher events.Publisher
 }
 
 func NewReturnService(
@@ -37,12 +69,16 @@ func NewReturnService(
 	stockRepo repositories.StockRepository,
 	batchRepo repositories.StockBatchRepository,
 	salesRepo repositories.SalesRepository,
+	shiftRepo repositories.ShiftRepository,
+	publisher events.Publisher,
 ) ReturnService {
 	return &returnService{
 		repo:      repo,
 		stockRepo: stockRepo,
 		batchRepo: batchRepo,
 		salesRepo: salesRepo,
+		shiftRepo: shiftRepo,
+		publisher: publisher,
 	}
 }
 
@@ -61,6 +97,12 @@ func (s *returnService) CreateReturPenjualan(userID uint, req *dto.CreateReturPe
 		}
 		return nil, err
 	}
+	if sale.Status == "voided" {
+		return nil, errors.New("transaksi penjualan sudah di-void, tidak dapat diretur")
+	}
+	if !models.PenjualanTerjual(sale.Status) {
+		return nil, fmt.Errorf("pesanan dalam status '%s', hanya pesanan yang sudah dikirim yang dapat diretur", sale.Status)
+	}
 
 	now := time.Now()
 	nomorRetur := fmt.Sprintf("RETP/%s/%d", now.Format("20060102150405"), userID)
@@ -103,6 +145,7 @@ func (s *returnService) CreateReturPenjualan(userID uint, req *dto.CreateReturPe
 	retur := models.ReturPenjualan{
 		NomorRetur:         nomorRetur,
 		IDPenjualan:        req.IDPenjualan,
+		IDPelanggan:        sale.IDPelanggan,
 		NamaPelanggan:      sale.NamaPelanggan,
 		KontakPelanggan:    sale.KontakPelanggan,
 		Alasan:             req.Alasan,
@@ -131,6 +174,12 @@ func (s *returnService) CreateReturPenjualan(userID uint, req *dto.CreateReturPe
 		return nil, fmt.Errorf("gagal membuat retur penjualan: %w", err)
 	}
 
+	if err := s.publisher.Publish(tx, salesReturnEvent(events.TypeReturnPendingApproval, retur.Status, &retur,
+		websocket.TopicPermission(middleware.PermSalesReturnApprove))); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event retur: %w", err)
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -147,7 +196,7 @@ func (s *returnService) ApproveReturPenjualan(id, approvedByUserID uint) error {
 	if err != nil {
 		return errors.New("retur penjualan tidak ditemukan")
 	}
-	if retur.Status != "pending" {
+	if !canTransitionRetur(retur.Status, "approved") {
 		return fmt.Errorf("retur sudah dalam status '%s', tidak dapat diapprove", retur.Status)
 	}
 
@@ -218,12 +267,71 @@ func (s *returnService) ApproveReturPenjualan(id, approvedByUserID uint) error {
 		// ⚠️ UpdateStockBalance TIDAK dipanggil — stok_inventori tidak berubah
 	}
 
-	// Update status retur
-	if err := s.repo.UpdateStatusReturPenjualan(tx, id, "completed", approvedByUserID); err != nil {
+	// Update status retur (completed menyusul setelah refund / tukar barang diselesaikan)
+	if err := s.repo.UpdateStatusReturPenjualan(tx, id, retur.Status, "approved", approvedByUserID); err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	// Pembuat retur dan finance (refund) perlu tahu retur sudah disetujui
+	if err := s.publisher.Publish(tx, salesReturnEvent(events.TypeReturnApproved, "approved", retur,
+		websocket.TopicUser(retur.DiprosesOleh), websocket.TopicPermission(middleware.PermSalesReturnComplete))); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal mencatat event retur: %w", err)
+	}
+
+	return tx.Commit().Error
+}
+
+// RejectReturPenjualan menolak retur yang masih pending. Stok tidak berubah.
+func (s *returnService) RejectReturPenjualan(id, rejectedByUserID uint, alasan string) error {
+	retur, err := s.repo.FindReturPenjualanByID(id)
+	if err != nil {
+		return errors.New("retur penjualan tidak ditemukan")
+	}
+	if !canTransitionRetur(retur.Status, "rejected") {
+		return fmt.Errorf("retur sudah dalam status '%s', tidak dapat ditolak", retur.Status)
+	}
+
+	tx := s.repo.BeginTx()
+	if err := s.repo.RejectReturPenjualan(tx, id, rejectedByUserID, alasan); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal menolak retur penjualan: %w", err)
+	}
+	return tx.Commit().Error
+}
+
+// CompleteReturPenjualan menandai retur selesai setelah refund / tukar barang ke customer beres.
+// Refund tunai keluar dari laci kasir, jadi wajib ada shift yang sedang buka milik user yang menyelesaikan.
+func (s *returnService) CompleteReturPenjualan(id, userID uint) error {
+	retur, err := s.repo.FindReturPenjualanByID(id)
+	if err != nil {
+		return errors.New("retur penjualan tidak ditemukan")
+	}
+	if !canTransitionRetur(retur.Status, "completed") {
+		return fmt.Errorf("retur dalam status '%s', hanya retur approved yang dapat diselesaikan", retur.Status)
+	}
+
+	tx := s.repo.BeginTx()
+	if retur.MetodePengembalian == "cash" {
+		shift, err := s.shiftRepo.FindOpenByKasir(tx, userID)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("gagal mengecek shift kasir: %w", err)
+		}
+		if shift == nil {
+			tx.Rollback()
+			return errors.New("refund tunai memerlukan shift kasir yang sedang buka")
+		}
+		if err := s.repo.AssignShiftReturPenjualan(tx, id, shift.ID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("gagal mencatat shift retur penjualan: %w", err)
+		}
+	}
+	if err := s.repo.UpdateStatusReturPenjualan(tx, id, retur.Status, "completed", userID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal menyelesaikan retur penjualan: %w", err)
+	}
 	return tx.Commit().Error
 }
 
@@ -304,6 +412,12 @@ func (s *returnService) CreateReturPembelian(userID uint, req *dto.CreateReturPe
 		return nil, fmt.Errorf("gagal membuat retur pembelian: %w", err)
 	}
 
+	if err := s.publisher.Publish(tx, purchaseReturnEvent(events.TypeReturnPendingApproval, retur.Status, &retur,
+		websocket.TopicPermission(middleware.PermPurchaseReturnApprove))); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event retur: %w", err)
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
@@ -317,7 +431,7 @@ func (s *returnService) ApproveReturPembelian(id, approvedByUserID uint) error {
 	if err != nil {
 		return errors.New("retur pembelian tidak ditemukan")
 	}
-	if retur.Status != "pending" {
+	if !canTransitionRetur(retur.Status, "approved") {
 		return fmt.Errorf("retur sudah dalam status '%s', tidak dapat diapprove", retur.Status)
 	}
 
@@ -365,6 +479,7 @@ func (s *returnService) ApproveReturPembelian(id, approvedByUserID uint) error {
 	}
 
 	// Kurangi stok via FIFO untuk setiap item
+	var stockChanges []stockChange
 	for _, item := range retur.Items {
 		batches, _ := s.batchRepo.GetAvailableBatches(tx, item.IDProduk, item.IDGudang)
 		remaining := item.Jumlah
@@ -411,13 +526,57 @@ func (s *returnService) ApproveReturPembelian(id, approvedByUserID uint) error {
 			tx.Rollback()
 			return err
 		}
+		stockChanges = append(stockChanges, stockChange{ProductID: item.IDProduk, WarehouseID: item.IDGudang, Delta: -item.Jumlah})
 	}
 
-	if err := s.repo.UpdateStatusReturPembelian(tx, id, "completed", approvedByUserID); err != nil {
+	if err := s.repo.UpdateStatusReturPembelian(tx, id, retur.Status, "approved", approvedByUserID); err != nil {
 		tx.Rollback()
 		return err
 	}
 
+	approved := purchaseReturnEvent(events.TypeReturnApproved, "approved", retur,
+		websocket.TopicUser(retur.DibuatOleh), websocket.TopicPermission(middleware.PermPurchaseReturnComplete))
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "retur_pembelian", retur.ID, stockChanges, approved); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal mencatat event retur: %w", err)
+	}
+
+	return tx.Commit().Error
+}
+
+// RejectReturPembelian menolak retur yang masih pending. Stok tidak berubah.
+func (s *returnService) RejectReturPembelian(id, rejectedByUserID uint, alasan string) error {
+	retur, err := s.repo.FindReturPembelianByID(id)
+	if err != nil {
+		return errors.New("retur pembelian tidak ditemukan")
+	}
+	if !canTransitionRetur(retur.Status, "rejected") {
+		return fmt.Errorf("retur sudah dalam status '%s', tidak dapat ditolak", retur.Status)
+	}
+
+	tx := s.repo.BeginTx()
+	if err := s.repo.RejectReturPembelian(tx, id, rejectedByUserID, alasan); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal menolak retur pembelian: %w", err)
+	}
+	return tx.Commit().Error
+}
+
+// CompleteReturPembelian menandai retur selesai setelah refund / potong hutang / tukar barang dari supplier beres.
+func (s *returnService) CompleteReturPembelian(id, userID uint) error {
+	retur, err := s.repo.FindReturPembelianByID(id)
+	if err != nil {
+		return errors.New("retur pembelian tidak ditemukan")
+	}
+	if !canTransitionRetur(retur.Status, "completed") {
+		return fmt.Errorf("retur dalam status '%s', hanya retur approved yang dapat diselesaikan", retur.Status)
+	}
+
+	tx := s.repo.BeginTx()
+	if err := s.repo.UpdateStatusReturPembelian(tx, id, retur.Status, "completed", userID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("gagal menyelesaikan retur pembelian: %w", err)
+	}
 	return tx.Commit().Error
 }
 
@@ -444,6 +603,241 @@ func (s *returnService) ListReturPembelian(req *dto.ListReturPembelianRequest) (
 	return responses, total, nil
 }
 
+// ===========================
+// STOK KARANTINA
+// ===========================
+
+func (s *returnService) ListStokKarantina(req *dto.ListStokKarantinaRequest) ([]dto.StokKarantinaBatchResponse, int64, error) {
+	batches, total, err := s.repo.FindAllStokKarantina(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var responses []dto.StokKarantinaBatchResponse
+	for _, b := range batches {
+		responses = append(responses, mapStokKarantinaToResponse(&b))
+	}
+	return responses, total, nil
+}
+
+func (s *returnService) ListDisposisiKarantina(req *dto.ListDisposisiKarantinaRequest) ([]dto.DisposisiKarantinaResponse, int64, error) {
+	records, total, err := s.repo.FindAllDisposisiKarantina(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var responses []dto.DisposisiKarantinaResponse
+	for _, d := range records {
+		responses = append(responses, *mapDisposisiKarantinaToResponse(&d))
+	}
+	return responses, total, nil
+}
+
+// ReleaseKarantina mengembalikan unit karantina ke stok yang bisa dijual.
+// Jika seluruh sisa batch di-release, batch karantina langsung diaktifkan; jika sebagian,
+// batch dipecah dan unit yang di-release menjadi batch aktif baru. stok_inventori bertambah.
+func (s *returnService) ReleaseKarantina(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error) {
+	return s.disposeKarantina(batchID, userID, "release", req)
+}
+
+// WriteOffKarantina menghapus unit karantina yang rusak. stok_inventori tidak berubah
+// karena stok karantina memang tidak pernah dihitung sebagai stok jual.
+func (s *returnService) WriteOffKarantina(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error) {
+	return s.disposeKarantina(batchID, userID, "write_off", req)
+}
+
+// RepairKarantina memindahkan unit karantina ke batch perbaikan (TipeReferensi="perbaikan", tetap tidak aktif).
+// Setelah selesai diperbaiki, batch perbaikan dapat di-release atau di-write-off.
+func (s *returnService) RepairKarantina(batchID, userID uint, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error) {
+	return s.disposeKarantina(batchID, userID, "repair", req)
+}
+
+func (s *returnService) disposeKarantina(batchID, userID uint, aksi string, req *dto.DisposisiKarantinaRequest) (*dto.DisposisiKarantinaResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	batch, err := s.batchRepo.FindByIDForUpdate(tx, batchID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("batch karantina tidak ditemukan")
+		}
+		return nil, err
+	}
+	if err := validateDisposisiKarantina(batch, aksi, req.Jumlah); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	var hasil *models.StokBatch
+
+	switch aksi {
+	case "release":
+		if req.Jumlah == batch.JumlahSaatIni {
+			// Release penuh: aktifkan batch karantina di tempat
+			batch.Aktif = true
+			if err := s.batchRepo.Update(tx, batch); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("gagal mengaktifkan batch karantina: %w", err)
+			}
+			hasil = batch
+		} else {
+			hasil, err = s.splitBatchKarantina(tx, batch, req.Jumlah, batch.TipeReferensi, true, now)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		if err := s.stockRepo.UpdateStockBalance(tx, batch.IDProduk, batch.IDGudang, req.Jumlah); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal update stok: %w", err)
+		}
+	case "write_off":
+		batch.JumlahSaatIni -= req.Jumlah
+		if err := s.batchRepo.Update(tx, batch); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal update batch karantina: %w", err)
+		}
+	case "repair":
+		hasil, err = s.splitBatchKarantina(tx, batch, req.Jumlah, "perbaikan", false, now)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	disposisi := models.DisposisiKarantina{
+		IDBatch:      batch.ID,
+		IDProduk:     batch.IDProduk,
+		IDGudang:     batch.IDGudang,
+		Aksi:         aksi,
+		Jumlah:       req.Jumlah,
+		NilaiModal:   batch.HargaModal * float64(req.Jumlah),
+		Keterangan:   req.Keterangan,
+		DiprosesOleh: userID,
+		DibuatPada:   now,
+	}
+	if hasil != nil {
+		disposisi.IDBatchHasil = &hasil.ID
+	}
+	if err := s.repo.CreateDisposisiKarantina(tx, &disposisi); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat disposisi karantina: %w", err)
+	}
+
+	for _, movement := range buildDisposisiMovements(batch, hasil, &disposisi) {
+		if err := s.stockRepo.CreateStockMovement(tx, &movement); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal log pergerakan karantina: %w", err)
+		}
+	}
+
+	// Hanya release yang menambah stok jual
+	if aksi == "release" {
+		if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "karantina_release", disposisi.ID, []stockChange{
+			{ProductID: batch.IDProduk, WarehouseID: batch.IDGudang, Delta: req.Jumlah},
+		}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal mencatat event stok: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return mapDisposisiKarantinaToResponse(&disposisi), nil
+}
+
+// splitBatchKarantina mengurangi batch karantina dan membuat batch baru untuk qty yang dipisah.
+// TanggalMasuk & HargaModal ikut batch asal.
+func (s *returnService) splitBatchKarantina(tx *gorm.DB, batch *models.StokBatch, qty int, tipe string, aktif bool, now time.Time) (*models.StokBatch, error) {
+	batch.JumlahSaatIni -= qty
+	if err := s.batchRepo.Update(tx, batch); err != nil {
+		return nil, fmt.Errorf("gagal update batch karantina: %w", err)
+	}
+
+	baru := models.StokBatch{
+		IDProduk:          batch.IDProduk,
+		IDGudang:          batch.IDGudang,
+		TanggalMasuk:      batch.TanggalMasuk,
+		TanggalKadaluarsa: batch.TanggalKadaluarsa,
+		JumlahAwal:        qty,
+		JumlahSaatIni:     qty,
+		HargaModal:        batch.HargaModal,
+		IDReferensi:       batch.IDReferensi,
+		TipeReferensi:     tipe,
+		Aktif:             aktif,
+		Keterangan:        fmt.Sprintf("%s (dari batch karantina #%d)", batch.Keterangan, batch.ID),
+		DibuatPada:        now,
+		DiperbaruiPada:    now,
+	}
+	if err := s.batchRepo.Create(tx, &baru); err != nil {
+		return nil, fmt.Errorf("gagal membuat batch hasil disposisi: %w", err)
+	}
+	return &baru, nil
+}
+
+// validateDisposisiKarantina memastikan batch benar-benar stok karantina dan qty mencukupi
+func validateDisposisiKarantina(batch *models.StokBatch, aksi string, qty int) error {
+	if batch.Aktif || (batch.TipeReferensi != "retur_penjualan" && batch.TipeReferensi != "perbaikan") {
+		return fmt.Errorf("batch #%d bukan stok karantina", batch.ID)
+	}
+	if aksi == "repair" && batch.TipeReferensi == "perbaikan" {
+		return fmt.Errorf("batch #%d sudah dalam perbaikan", batch.ID)
+	}
+	if qty > batch.JumlahSaatIni {
+		return fmt.Errorf("jumlah (%d) melebihi sisa stok karantina batch #%d (%d)", qty, batch.ID, batch.JumlahSaatIni)
+	}
+	return nil
+}
+
+// buildDisposisiMovements menyusun log pergerakan stok untuk satu disposisi karantina.
+// release  → in (masuk stok jual), write_off → out, repair → transfer_out karantina + transfer_in batch perbaikan.
+func buildDisposisiMovements(batch, hasil *models.StokBatch, d *models.DisposisiKarantina) []models.PergerakanStok {
+	base := models.PergerakanStok{
+		IDProduk:      d.IDProduk,
+		IDGudang:      d.IDGudang,
+		TipeReferensi: "karantina_" + d.Aksi,
+		IDReferensi:   &d.ID,
+		IDPengguna:    d.DiprosesOleh,
+		DibuatPada:    d.DibuatPada,
+	}
+	batchID := batch.ID
+
+	switch d.Aksi {
+	case "release":
+		m := base
+		m.IDBatch = &hasil.ID
+		m.TipePergerakan = "in"
+		m.Jumlah = d.Jumlah
+		m.Keterangan = fmt.Sprintf("[KARANTINA] Release %d unit dari batch #%d ke stok jual (batch #%d). %s", d.Jumlah, batchID, hasil.ID, d.Keterangan)
+		return []models.PergerakanStok{m}
+	case "write_off":
+		m := base
+		m.IDBatch = &batchID
+		m.TipePergerakan = "out"
+		m.Jumlah = -d.Jumlah
+		m.Keterangan = fmt.Sprintf("[KARANTINA] Write-off %d unit rusak dari batch #%d. %s", d.Jumlah, batchID, d.Keterangan)
+		return []models.PergerakanStok{m}
+	case "repair":
+		out := base
+		out.IDBatch = &batchID
+		out.TipePergerakan = "transfer_out"
+		out.Jumlah = -d.Jumlah
+		out.Keterangan = fmt.Sprintf("[KARANTINA] %d unit dari batch #%d dikirim perbaikan. %s", d.Jumlah, batchID, d.Keterangan)
+		in := base
+		in.IDBatch = &hasil.ID
+		in.TipePergerakan = "transfer_in"
+		in.Jumlah = d.Jumlah
+		in.Keterangan = fmt.Sprintf("[PERBAIKAN] Batch perbaikan #%d dari batch karantina #%d", hasil.ID, batchID)
+		return []models.PergerakanStok{out, in}
+	}
+	return nil
+}
+
 // ===========================
 // MAPPING HELPERS
 // ===========================
@@ -470,6 +864,7 @@ func mapReturPenjualanToResponse(r *models.ReturPenjualan) *dto.ReturPenjualanRe
 		NomorRetur:         r.NomorRetur,
 		IDPenjualan:        r.IDPenjualan,
 		NomorTransaksiAsal: nomorAsal,
+		IDPelanggan:        r.IDPelanggan,
 		NamaPelanggan:      r.NamaPelanggan,
 		KontakPelanggan:    r.KontakPelanggan,
 		Alasan:             r.Alasan,
@@ -480,6 +875,10 @@ func mapReturPenjualanToResponse(r *models.ReturPenjualan) *dto.ReturPenjualanRe
 		Status:             r.Status,
 		Keterangan:         r.Keterangan,
 		NamaPetugas:        r.DiprosesOlehPengguna.Nama,
+		DisetujuiPada:      r.DisetujuiPada,
+		AlasanPenolakan:    r.AlasanPenolakan,
+		DitolakPada:        r.DitolakPada,
+		DiselesaikanPada:   r.DiselesaikanPada,
 		DibuatPada:         r.DibuatPada,
 		Items:              items,
 	}
@@ -510,7 +909,50 @@ func mapReturPembelianToResponse(r *models.ReturPembelian) *dto.ReturPembelianRe
 		Status:             r.Status,
 		Keterangan:         r.Keterangan,
 		NamaPembuat:        r.DibuatOlehPengguna.Nama,
+		DisetujuiPada:      r.DisetujuiPada,
+		AlasanPenolakan:    r.AlasanPenolakan,
+		DitolakPada:        r.DitolakPada,
+		DiselesaikanPada:   r.DiselesaikanPada,
 		DibuatPada:         r.DibuatPada,
 		Items:              items,
 	}
 }
+
+func mapStokKarantinaToResponse(b *models.StokBatch) dto.StokKarantinaBatchResponse {
+	status := "karantina"
+	if b.TipeReferensi == "perbaikan" {
+		status = "perbaikan"
+	}
+	return dto.StokKarantinaBatchResponse{
+		IDBatch:      b.ID,
+		IDProduk:     b.IDProduk,
+		SKUProduk:    b.Produk.SKU,
+		NamaProduk:   b.Produk.Nama,
+		IDGudang:     b.IDGudang,
+		NamaGudang:   b.Gudang.Nama,
+		Status:       status,
+		JumlahUnit:   b.JumlahSaatIni,
+		HargaModal:   b.HargaModal,
+		Keterangan:   b.Keterangan,
+		TanggalMasuk: b.TanggalMasuk,
+	}
+}
+
+func mapDisposisiKarantinaToResponse(d *models.DisposisiKarantina) *dto.DisposisiKarantinaResponse {
+	return &dto.DisposisiKarantinaResponse{
+		ID:           d.ID,
+		IDBatch:      d.IDBatch,
+		IDBatchHasil: d.IDBatchHasil,
+		IDProduk:     d.IDProduk,
+		SKUProduk:    d.Produk.SKU,
+		NamaProduk:   d.Produk.Nama,
+		IDGudang:     d.IDGudang,
+		NamaGudang:   d.Gudang.Nama,
+		Aksi:         d.Aksi,
+		Jumlah:       d.Jumlah,
+		NilaiModal:   d.NilaiModal,
+		Keterangan:   d.Keterangan,
+		NamaPetugas:  d.DiprosesOlehPengguna.Nama,
+		DibuatPada:   d.DibuatPada,
+	}
+}
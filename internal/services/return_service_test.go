@@ -0,0 +1,83 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"testing"
+)
+
+func TestValidateDisposisiKarantina(t *testing.T) {
+	karantina := &models.StokBatch{ID: 9, TipeReferensi: "retur_penjualan", JumlahSaatIni: 4}
+	perbaikan := &models.StokBatch{ID: 10, TipeReferensi: "perbaikan", JumlahSaatIni: 2}
+	aktif := &models.StokBatch{ID: 11, TipeReferensi: "retur_penjualan", Aktif: true, JumlahSaatIni: 4}
+	biasa := &models.StokBatch{ID: 12, TipeReferensi: "stock_in", JumlahSaatIni: 4}
+
+	tests := []struct {
+		name    string
+		batch   *models.StokBatch
+		aksi    string
+		qty     int
+		wantErr bool
+	}{
+		{"release sebagian", karantina, "release", 3, false},
+		{"write off penuh", karantina, "write_off", 4, false},
+		{"melebihi sisa", karantina, "release", 5, true},
+		{"repair dari karantina", karantina, "repair", 1, false},
+		{"repair batch perbaikan", perbaikan, "repair", 1, true},
+		{"release batch perbaikan", perbaikan, "release", 2, false},
+		{"batch sudah aktif", aktif, "write_off", 1, true},
+		{"bukan batch retur", biasa, "release", 1, true},
+	}
+
+	for _, tt := range tests {
+		err := validateDisposisiKarantina(tt.batch, tt.aksi, tt.qty)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error=%v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestBuildDisposisiMovements(t *testing.T) {
+	batch := &models.StokBatch{ID: 9, IDProduk: 4, IDGudang: 1}
+	hasil := &models.StokBatch{ID: 21}
+
+	release := buildDisposisiMovements(batch, hasil, &models.DisposisiKarantina{ID: 1, Aksi: "release", Jumlah: 3, IDProduk: 4, IDGudang: 1})
+	if len(release) != 1 || release[0].TipePergerakan != "in" || release[0].Jumlah != 3 || *release[0].IDBatch != 21 {
+		t.Errorf("Unexpected release movements: %+v", release)
+	}
+	if release[0].TipeReferensi != "karantina_release" {
+		t.Errorf("Expected TipeReferensi karantina_release, got %s", release[0].TipeReferensi)
+	}
+
+	writeOff := buildDisposisiMovements(batch, nil, &models.DisposisiKarantina{ID: 2, Aksi: "write_off", Jumlah: 2})
+	if len(writeOff) != 1 || writeOff[0].TipePergerakan != "out" || writeOff[0].Jumlah != -2 || *writeOff[0].IDBatch != 9 {
+		t.Errorf("Unexpected write-off movements: %+v", writeOff)
+	}
+
+	repair := buildDisposisiMovements(batch, hasil, &models.DisposisiKarantina{ID: 3, Aksi: "repair", Jumlah: 1})
+	if len(repair) != 2 || repair[0].Jumlah+repair[1].Jumlah != 0 {
+		t.Fatalf("Expected balanced repair movements, got %+v", repair)
+	}
+	if *repair[0].IDBatch != 9 || *repair[1].IDBatch != 21 {
+		t.Errorf("Expected repair out from #9 and in to #21, got #%d and #%d", *repair[0].IDBatch, *repair[1].IDBatch)
+	}
+}
+
+func TestCanTransitionRetur(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"pending", "approved", true},
+		{"pending", "rejected", true},
+		{"pending", "completed", false},
+		{"approved", "completed", true},
+		{"approved", "rejected", false},
+		{"rejected", "approved", false},
+		{"completed", "rejected", false},
+	}
+	for _, tt := range tests {
+		if got := canTransitionRetur(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransitionRetur(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ownerRoleKode adalah role bawaan dengan akses penuh; permission dan status aktifnya dikunci
+const ownerRoleKode = "owner"
+
+var (
+	errRoleNotFound = utils.NewAppError(utils.ErrCodeNotFound, "Role not found", nil)
+	roleKodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// RoleService adalah interface untuk role service
+type RoleService interface {
+	ListRoles() ([]dto.RoleResponse, error)
+	GetRoleByID(id uint) (*dto.RoleResponse, error)
+	ListPermissions() ([]dto.PermissionResponse, error)
+	CreateRole(req dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(id uint, req dto.UpdateRoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(id uint) error
+}
+
+type roleService struct {
+	roleRepo repositories.RoleRepository
+	logger   *zap.Logger
+}
+
+// NewRoleService membuat instance RoleService baru
+func NewRoleService() RoleService {
+	return &roleService{
+		roleRepo: repositories.NewRoleRepository(),
+		logger:   utils.GetLogger(),
+	}
+}
+
+func (s *roleService) ListRoles() ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepo.FindAll()
+	if err != nil {
+		s.logger.Error("Failed to list roles", zap.Error(err))
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to list roles", err)
+	}
+
+	responses := make([]dto.RoleResponse, len(roles))
+	for i := range roles {
+		responses[i] = *toRoleResponse(&roles[i])
+	}
+	return responses, nil
+}
+
+func (s *roleService) GetRoleByID(id uint) (*dto.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errRoleNotFound
+	}
+	return toRoleResponse(role), nil
+}
+
+func (s *roleService) ListPermissions() ([]dto.PermissionResponse, error) {
+	permissions, err := s.roleRepo.FindAllPermissions()
+	if err != nil {
+		s.logger.Error("Failed to list permissions", zap.Error(err))
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to list permissions", err)
+	}
+
+	responses := make([]dto.PermissionResponse, len(permissions))
+	for i, p := range permissions {
+		responses[i] = dto.PermissionResponse{
+			Kode:      p.Kode,
+			Modul:     p.Modul,
+			Deskripsi: p.Deskripsi,
+		}
+	}
+	return responses, nil
+}
+
+func (s *roleService) CreateRole(req dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	kode, err := normalizeRoleKode(req.Kode)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.roleRepo.FindByKode(kode); err == nil {
+		return nil, utils.NewAppError(utils.ErrCodeConflict, "Role already exists", nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to check role", err)
+	}
+
+	permissionIDs, err := s.resolvePermissions(req.Izin)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.PeranPengguna{
+		Kode:      kode,
+		Nama:      req.Nama,
+		Deskripsi: req.Deskripsi,
+		Aktif:     true,
+	}
+
+	tx := s.roleRepo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.roleRepo.Create(tx, role); err != nil {
+		tx.Rollback()
+		s.logger.Error("Failed to create role", zap.String("kode", kode), zap.Error(err))
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to create role", err)
+	}
+	if err := s.roleRepo.ReplacePermissions(tx, role.ID, permissionIDs); err != nil {
+		tx.Rollback()
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to assign permissions", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to create role", err)
+	}
+
+	s.logger.Info("Role created successfully",
+		zap.Uint("role_id", role.ID),
+		zap.String("kode", kode),
+	)
+
+	return s.GetRoleByID(role.ID)
+}
+
+func (s *roleService) UpdateRole(id uint, req dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errRoleNotFound
+	}
+
+	if role.Kode == ownerRoleKode && (req.Izin != nil || (req.Aktif != nil && !*req.Aktif)) {
+		return nil, utils.NewAppError(utils.ErrCodeValidationError, "Owner role permissions cannot be changed", nil)
+	}
+
+	if req.Nama != nil {
+		role.Nama = *req.Nama
+	}
+	if req.Deskripsi != nil {
+		role.Deskripsi = *req.Deskripsi
+	}
+	if req.Aktif != nil {
+		role.Aktif = *req.Aktif
+	}
+
+	var permissionIDs []uint
+	if req.Izin != nil {
+		if permissionIDs, err = s.resolvePermissions(req.Izin); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := s.roleRepo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := s.roleRepo.Update(tx, role); err != nil {
+		tx.Rollback()
+		s.logger.Error("Failed to update role", zap.Uint("role_id", id), zap.Error(err))
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to update role", err)
+	}
+	if req.Izin != nil {
+		if err := s.roleRepo.ReplacePermissions(tx, role.ID, permissionIDs); err != nil {
+			tx.Rollback()
+			return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to assign permissions", err)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to update role", err)
+	}
+
+	s.logger.Info("Role updated successfully",
+		zap.Uint("role_id", id),
+		zap.String("kode", role.Kode),
+	)
+
+	return s.GetRoleByID(id)
+}
+
+func (s *roleService) DeleteRole(id uint) error {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return errRoleNotFound
+	}
+
+	if role.Sistem {
+		return utils.NewAppError(utils.ErrCodeValidationError, "Cannot delete system role", nil)
+	}
+
+	users, err := s.roleRepo.CountUsers(role.Kode)
+	if err != nil {
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to check role usage", err)
+	}
+	if users > 0 {
+		return utils.NewAppError(utils.ErrCodeConflict, fmt.Sprintf("Role is still assigned to %d user(s)", users), nil)
+	}
+
+	tx := s.roleRepo.BeginTx()
+	if err := s.roleRepo.Delete(tx, id); err != nil {
+		tx.Rollback()
+		s.logger.Error("Failed to delete role", zap.Uint("role_id", id), zap.Error(err))
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to delete role", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to delete role", err)
+	}
+
+	s.logger.Info("Role deleted successfully",
+		zap.Uint("role_id", id),
+		zap.String("kode", role.Kode),
+	)
+
+	return nil
+}
+
+// resolvePermissions memetakan kode permission ke ID; kode yang tidak ada di katalog ditolak
+func (s *roleService) resolvePermissions(kode []string) ([]uint, error) {
+	kode = uniquePermissionCodes(kode)
+	if len(kode) == 0 {
+		return nil, utils.NewAppError(utils.ErrCodeValidationError, "At least one permission is required", nil)
+	}
+
+	permissions, err := s.roleRepo.FindPermissionsByKode(kode)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to load permissions", err)
+	}
+
+	found := make(map[string]uint, len(permissions))
+	for _, p := range permissions {
+		found[p.Kode] = p.ID
+	}
+
+	ids := make([]uint, 0, len(kode))
+	for _, k := range kode {
+		id, ok := found[k]
+		if !ok {
+			return nil, utils.NewAppError(utils.ErrCodeValidationError, fmt.Sprintf("Unknown permission: %s", k), nil)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// normalizeRoleKode merapikan kode role ("Supervisor Toko" → "supervisor_toko") dan memvalidasi formatnya
+func normalizeRoleKode(kode string) (string, error) {
+	kode = strings.ToLower(strings.Join(strings.Fields(kode), "_"))
+	if !roleKodePattern.MatchString(kode) {
+		return "", utils.NewAppError(utils.ErrCodeValidationError, "Role code may only contain lowercase letters, digits and underscores", nil)
+	}
+	return kode, nil
+}
+
+// uniquePermissionCodes membuang kode kosong dan duplikat dengan urutan tetap
+func uniquePermissionCodes(kode []string) []string {
+	seen := make(map[string]bool, len(kode))
+	result := make([]string, 0, len(kode))
+	for _, k := range kode {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, k)
+	}
+	return result
+}
+
+// toRoleResponse mengkonversi model Role ke DTO RoleResponse
+func toRoleResponse(role *models.PeranPengguna) *dto.RoleResponse {
+	izin := make([]string, 0, len(role.Izin))
+	for _, pi := range role.Izin {
+		izin = append(izin, pi.Izin.Kode)
+	}
+
+	return &dto.RoleResponse{
+		ID:        role.ID,
+		Kode:      role.Kode,
+		Nama:      role.Nama,
+		Deskripsi: role.Deskripsi,
+		Sistem:    role.Sistem,
+		Aktif:     role.Aktif,
+		Izin:      izin,
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeRoleKode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"supervisor_toko", "supervisor_toko", false},
+		{"Supervisor Toko", "supervisor_toko", false},
+		{"  kasir  cabang2 ", "kasir_cabang2", false},
+		{"2nd_branch", "", true},
+		{"kasir-cabang", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeRoleKode(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("normalizeRoleKode(%q): expected error=%v, got %v", tt.input, tt.wantErr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("normalizeRoleKode(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestUniquePermissionCodes(t *testing.T) {
+	got := uniquePermissionCodes([]string{"sales.read", " sales.void ", "", "sales.read"})
+	want := []string{"sales.read", "sales.void"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("uniquePermissionCodes = %v, want %v", got, want)
+	}
+}
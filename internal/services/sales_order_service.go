@@ -0,0 +1,561 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SalesOrderService menangani penjualan Mode 2: pesanan dengan DP lalu pelunasan.
+// Pesanan disimpan sebagai Penjualan dengan TipePenjualan = "pesanan" sehingga retur,
+// laporan dan riwayat pelanggan tetap memakai data yang sama dengan POS.
+type SalesOrderService interface {
+	CreateOrder(userID uint, req *dto.CreateSalesOrderRequest) (*dto.SalesDetailResponse, error)
+	GetOrder(id uint) (*dto.SalesDetailResponse, error)
+	ListOrders(req *dto.ListSalesRequest) (*dto.ListSalesResponse, error)
+
+	// Status machine: ordered → ready → delivered → paid, atau cancelled sebelum dikirim
+	MarkReady(id, userID uint) (*dto.SalesDetailResponse, error)
+	MarkDelivered(id, userID uint) (*dto.SalesDetailResponse, error)
+	CancelOrder(id, userID uint, alasan string) (*dto.SalesDetailResponse, error)
+
+	// Piutang: pembayaran (dengan bukti opsional) & jatuh tempo
+	AddPayment(id, userID uint, req *dto.SalesOrderPaymentRequest, buktiPath string) (*dto.SalesDetailResponse, error)
+	UpdateJatuhTempo(id uint, req *dto.UpdateJatuhTempoRequest) (*dto.SalesDetailResponse, error)
+}
+
+type salesOrderService struct {
+	repo      repositories.SalesRepository
+	stockRepo repositories.StockRepository
+	batchRepo repositories.StockBatchRepository
+	custRepo  repositories.PelangganRepository
+	shiftRepo repositories.ShiftRepository
+	sales     SalesService
+	publisher events.Publisher
+}
+
+func NewSalesOrderService(
+	repo repositories.SalesRepository,
+	stockRepo repositories.StockRepository,
+	batchRepo repositories.StockBatchRepository,
+	custRepo repositories.PelangganRepository,
+	shiftRepo repositories.ShiftRepository,
+	sales SalesService,
+	publisher events.Publisher,
+) SalesOrderService {
+	return &salesOrderService{
+		repo:      repo,
+		stockRepo: stockRepo,
+		batchRepo: batchRepo,
+		custRepo:  custRepo,
+		shiftRepo: shiftRepo,
+		sales:     sales,
+		publisher: publisher,
+	}
+}
+
+// salesOrderTransitions mendefinisikan perpindahan status pesanan yang diizinkan.
+// paid di-set otomatis saat pesanan sudah dikirim dan sisa tagihan 0.
+var salesOrderTransitions = map[string][]string{
+	"ordered":   {"ready", "cancelled"},
+	"ready":     {"delivered", "cancelled"},
+	"delivered": {"paid"},
+}
+
+// canTransitionSalesOrder mengecek apakah status pesanan boleh berpindah dari `from` ke `to`
+func canTransitionSalesOrder(from, to string) bool {
+	for _, next := range salesOrderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// settledOrderStatus mengembalikan "paid" jika pesanan sudah dikirim dan lunas, selain itu status tetap
+func settledOrderStatus(status string, total, totalDibayar float64) string {
+	if status == "delivered" && math.Round((total-totalDibayar)*100) <= 0 {
+		return "paid"
+	}
+	return status
+}
+
+// CreateOrder mencatat pesanan beserta DP. Stok belum dikurangi — barang bisa saja masih dipesan ke pemasok.
+func (s *salesOrderService) CreateOrder(userID uint, req *dto.CreateSalesOrderRequest) (*dto.SalesDetailResponse, error) {
+	if err := resolvePelanggan(s.custRepo, req.IDPelanggan, &req.NamaPelanggan, &req.KontakPelanggan); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.NamaPelanggan) == "" {
+		return nil, errors.New("nama pelanggan wajib diisi untuk pesanan")
+	}
+	if req.JumlahDP > 0 && req.MetodePembayaran == "" {
+		return nil, errors.New("metode_pembayaran wajib diisi jika ada DP")
+	}
+
+	now := time.Now()
+	sale := models.Penjualan{
+		NomorTransaksi:    fmt.Sprintf("SO/%s/%d", now.Format("20060102150405"), userID),
+		TipePenjualan:     "pesanan",
+		IDGudang:          req.IDGudang,
+		IDPelanggan:       req.IDPelanggan,
+		NamaPelanggan:     req.NamaPelanggan,
+		KontakPelanggan:   req.KontakPelanggan,
+		AlamatPengiriman:  req.AlamatPengiriman,
+		TanggalPengiriman: req.TanggalPengiriman,
+		JatuhTempo:        req.JatuhTempo,
+		MetodePembayaran:  req.MetodePembayaran,
+		JumlahPembayaran:  req.JumlahDP,
+		TotalDibayar:      req.JumlahDP,
+		Status:            "ordered",
+		CatatanInternal:   req.CatatanInternal,
+		IDKasir:           userID,
+		DibuatPada:        now,
+		DiperbaruiPada:    now,
+	}
+
+	// HPP item diisi saat pesanan siap (stok keluar FIFO)
+	var grandSubtotal, grandDiskon, grandTotal float64
+	for _, itemReq := range req.Items {
+		jumlahDiskon, subtotalItem := saleItemAmounts(itemReq)
+		sale.Items = append(sale.Items, models.ItemPenjualan{
+			IDProduk:     itemReq.IDProduk,
+			IDGudang:     req.IDGudang,
+			Jumlah:       itemReq.Jumlah,
+			HargaSatuan:  itemReq.HargaSatuan,
+			PersenDiskon: itemReq.PersenDiskon,
+			JumlahDiskon: jumlahDiskon,
+			Subtotal:     subtotalItem,
+			DibuatPada:   now,
+		})
+		grandSubtotal += itemReq.HargaSatuan * float64(itemReq.Jumlah)
+		grandDiskon += jumlahDiskon
+		grandTotal += subtotalItem
+	}
+	sale.Subtotal = math.Round(grandSubtotal*100) / 100
+	sale.JumlahDiskon = math.Round(grandDiskon*100) / 100
+	sale.Total = math.Round(grandTotal*100) / 100
+
+	if req.JumlahDP > sale.Total {
+		return nil, fmt.Errorf("DP (%.2f) melebihi total pesanan (%.2f)", req.JumlahDP, sale.Total)
+	}
+	if req.JumlahDP > 0 {
+		sale.Pembayaran = []models.PembayaranPenjualan{{
+			Jenis:            "dp",
+			MetodePembayaran: req.MetodePembayaran,
+			Jumlah:           req.JumlahDP,
+			DiterimaOleh:     userID,
+			DibuatPada:       now,
+		}}
+	}
+
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Pesanan yang dibuat di dalam shift ikut tercatat di shift tersebut
+	var metodeDP string
+	if len(sale.Pembayaran) > 0 {
+		metodeDP = req.MetodePembayaran
+	}
+	shiftID, err := s.receiverShiftID(tx, userID, metodeDP)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	sale.IDShift = shiftID
+	if len(sale.Pembayaran) > 0 {
+		sale.Pembayaran[0].IDShift = shiftID
+	}
+
+	if err := s.repo.Create(tx, &sale); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal menyimpan pesanan: %w", err)
+	}
+
+	if err := s.publisher.Publish(tx, salesOrderEvent(&sale)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event pesanan: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetOrder(sale.ID)
+}
+
+func (s *salesOrderService) GetOrder(id uint) (*dto.SalesDetailResponse, error) {
+	sale, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pesanan penjualan tidak ditemukan")
+		}
+		return nil, err
+	}
+	if sale.TipePenjualan != "pesanan" {
+		return nil, errors.New("pesanan penjualan tidak ditemukan")
+	}
+	return mapSaleToDetailResponse(sale), nil
+}
+
+func (s *salesOrderService) ListOrders(req *dto.ListSalesRequest) (*dto.ListSalesResponse, error) {
+	req.TipePenjualan = "pesanan"
+	return s.sales.ListSales(req)
+}
+
+// MarkReady menyiapkan barang pesanan: stok keluar dengan FIFO yang sama seperti POS
+// sehingga barang tidak lagi bisa terjual di kasir, dan HPP item tercatat.
+func (s *salesOrderService) MarkReady(id, userID uint) (*dto.SalesDetailResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sale, err := s.findOrderForUpdate(tx, id, "ready")
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	headerKeluar := models.BarangKeluar{
+		NomorTransaksi: fmt.Sprintf("OUT/SO/%s/%d", now.Format("20060102150405"), userID),
+		Alasan:         "penjualan",
+		IDReferensi:    &sale.ID,
+		TipeReferensi:  "sales_order",
+		DibuatOleh:     userID,
+		DibuatPada:     now,
+		DiperbaruiPada: now,
+	}
+	if err := s.stockRepo.CreateStockOut(tx, &headerKeluar); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuat barang keluar: %w", err)
+	}
+
+	var totalHargaModal float64
+	var stockChanges []stockChange
+	for i := range sale.Items {
+		item := &sale.Items[i]
+		usages, totalCOGS, err := consumeSaleItemFIFO(tx, s.stockRepo, s.batchRepo,
+			item.IDProduk, item.IDGudang, item.Jumlah, userID, "sales_order", headerKeluar.ID,
+			fmt.Sprintf("Pesanan %s", sale.NomorTransaksi), now)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		for j := range usages {
+			usages[j].IDItemPenjualan = item.ID
+		}
+		if err := s.repo.CreateBatchUsage(tx, usages); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal mencatat batch item pesanan: %w", err)
+		}
+
+		item.TotalModal = totalCOGS
+		item.HargaModal = math.Round(totalCOGS/float64(item.Jumlah)*100) / 100
+		if err := s.repo.UpdateItemCOGS(tx, item); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal update HPP item pesanan: %w", err)
+		}
+
+		totalHargaModal += totalCOGS
+		stockChanges = append(stockChanges, stockChange{ProductID: item.IDProduk, WarehouseID: item.IDGudang, Delta: -item.Jumlah})
+	}
+
+	sale.Status = "ready"
+	sale.TotalHargaModal = math.Round(totalHargaModal*100) / 100
+	if err := s.repo.UpdateFields(tx, sale.ID, map[string]interface{}{
+		"status":            sale.Status,
+		"total_harga_modal": sale.TotalHargaModal,
+		"disiapkan_pada":    now,
+		"diperbarui_pada":   now,
+	}); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update pesanan: %w", err)
+	}
+
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "sales_order", sale.ID, stockChanges, salesOrderEvent(sale)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event pesanan: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetOrder(sale.ID)
+}
+
+// MarkDelivered menandai pesanan sudah diterima pelanggan; langsung paid jika sudah lunas.
+// Pesanan tanpa jatuh tempo dianggap harus lunas saat barang diterima.
+func (s *salesOrderService) MarkDelivered(id, userID uint) (*dto.SalesDetailResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sale, err := s.findOrderForUpdate(tx, id, "delivered")
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	sale.Status = settledOrderStatus("delivered", sale.Total, sale.TotalDibayar)
+	fields := map[string]interface{}{
+		"status":          sale.Status,
+		"dikirim_pada":    now,
+		"diperbarui_pada": now,
+	}
+	if sale.Status == "paid" {
+		fields["lunas_pada"] = now
+	}
+	if sale.JatuhTempo == nil {
+		fields["jatuh_tempo"] = now
+	}
+	if err := s.repo.UpdateFields(tx, sale.ID, fields); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update pesanan: %w", err)
+	}
+
+	if err := s.publisher.Publish(tx, salesOrderEvent(sale)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event pesanan: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetOrder(sale.ID)
+}
+
+// AddPayment mencatat cicilan / pelunasan. Pembayaran tidak boleh melebihi sisa tagihan.
+func (s *salesOrderService) AddPayment(id, userID uint, req *dto.SalesOrderPaymentRequest, buktiPath string) (*dto.SalesDetailResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sale, err := s.findOrderForUpdate(tx, id, "")
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if sale.Status == "paid" || sale.Status == "cancelled" {
+		tx.Rollback()
+		return nil, fmt.Errorf("pesanan dalam status '%s', tidak dapat menerima pembayaran", sale.Status)
+	}
+
+	sisa := saleBalance(sale)
+	if math.Round(req.Jumlah*100) > math.Round(sisa*100) {
+		tx.Rollback()
+		return nil, fmt.Errorf("pembayaran (%.2f) melebihi sisa tagihan (%.2f)", req.Jumlah, sisa)
+	}
+
+	shiftID, err := s.receiverShiftID(tx, userID, req.MetodePembayaran)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	payment := models.PembayaranPenjualan{
+		IDPenjualan:      sale.ID,
+		IDShift:          shiftID,
+		Jenis:            "pembayaran",
+		MetodePembayaran: req.MetodePembayaran,
+		Jumlah:           req.Jumlah,
+		Keterangan:       req.Keterangan,
+		BuktiPembayaran:  buktiPath,
+		DiterimaOleh:     userID,
+		DibuatPada:       now,
+	}
+	if err := s.repo.CreatePayment(tx, &payment); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat pembayaran: %w", err)
+	}
+
+	previousStatus := sale.Status
+	sale.TotalDibayar = math.Round((sale.TotalDibayar+req.Jumlah)*100) / 100
+	sale.Status = settledOrderStatus(sale.Status, sale.Total, sale.TotalDibayar)
+	fields := map[string]interface{}{
+		"total_dibayar":   sale.TotalDibayar,
+		"status":          sale.Status,
+		"diperbarui_pada": now,
+	}
+	if sale.Status == "paid" {
+		fields["lunas_pada"] = now
+	}
+	if err := s.repo.UpdateFields(tx, sale.ID, fields); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update pesanan: %w", err)
+	}
+
+	if sale.Status != previousStatus {
+		if err := s.publisher.Publish(tx, salesOrderEvent(sale)); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("gagal mencatat event pesanan: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetOrder(sale.ID)
+}
+
+// UpdateJatuhTempo mengubah batas pelunasan piutang (mis. hasil negosiasi penagihan)
+func (s *salesOrderService) UpdateJatuhTempo(id uint, req *dto.UpdateJatuhTempoRequest) (*dto.SalesDetailResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sale, err := s.findOrderForUpdate(tx, id, "")
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if sale.Status == "paid" || sale.Status == "cancelled" {
+		tx.Rollback()
+		return nil, fmt.Errorf("pesanan dalam status '%s', tidak memiliki piutang", sale.Status)
+	}
+
+	if err := s.repo.UpdateFields(tx, sale.ID, map[string]interface{}{
+		"jatuh_tempo":     req.JatuhTempo,
+		"diperbarui_pada": time.Now(),
+	}); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal update jatuh tempo: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetOrder(sale.ID)
+}
+
+// CancelOrder membatalkan pesanan yang belum dikirim. Jika stok sudah keluar (ready),
+// qty dikembalikan ke batch asal. DP yang sudah diterima tetap tercatat untuk dikembalikan manual.
+func (s *salesOrderService) CancelOrder(id, userID uint, alasan string) (*dto.SalesDetailResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sale, err := s.findOrderForUpdate(tx, id, "cancelled")
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	var stockChanges []stockChange
+	for _, item := range sale.Items {
+		changes, err := restoreSaleItemBatches(tx, s.stockRepo, s.batchRepo, item, userID, "sales_order_cancel", sale.ID,
+			fmt.Sprintf("Batal pesanan %s", sale.NomorTransaksi), alasan, now)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		stockChanges = append(stockChanges, changes...)
+	}
+
+	sale.Status = "cancelled"
+	if err := s.repo.UpdateFields(tx, sale.ID, map[string]interface{}{
+		"status":            sale.Status,
+		"alasan_pembatalan": alasan,
+		"dibatalkan_oleh":   userID,
+		"dibatalkan_pada":   now,
+		"diperbarui_pada":   now,
+	}); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membatalkan pesanan: %w", err)
+	}
+
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "sales_order_cancel", sale.ID, stockChanges, salesOrderEvent(sale)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event pesanan: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetOrder(sale.ID)
+}
+
+// findOrderForUpdate mengunci pesanan dan (jika `next` diisi) memvalidasi transisi status
+func (s *salesOrderService) findOrderForUpdate(tx *gorm.DB, id uint, next string) (*models.Penjualan, error) {
+	sale, err := s.repo.FindByIDForUpdate(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pesanan penjualan tidak ditemukan")
+		}
+		return nil, err
+	}
+	if sale.TipePenjualan != "pesanan" {
+		return nil, errors.New("pesanan penjualan tidak ditemukan")
+	}
+	if next != "" && !canTransitionSalesOrder(sale.Status, next) {
+		return nil, fmt.Errorf("pesanan dalam status '%s', tidak dapat diubah ke '%s'", sale.Status, next)
+	}
+	return sale, nil
+}
+
+// receiverShiftID mengembalikan shift yang sedang buka milik user.
+// Uang tunai masuk ke laci kasir, jadi metode cash wajib punya shift; metode lain
+// (mis. transfer yang dicatat finance) boleh tanpa shift dan mendapat nil.
+func (s *salesOrderService) receiverShiftID(tx *gorm.DB, userID uint, metode string) (*uint, error) {
+	shift, err := s.shiftRepo.FindOpenByKasir(tx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengecek shift kasir: %w", err)
+	}
+	if shift == nil {
+		if metode == "cash" {
+			return nil, errors.New("pembayaran tunai memerlukan shift kasir yang sedang buka")
+		}
+		return nil, nil
+	}
+	return &shift.ID, nil
+}
+
+// salesOrderEvent membuat event sales_order.status_changed untuk gudang & pengguna yang bisa melihat penjualan
+func salesOrderEvent(sale *models.Penjualan) events.Event {
+	return events.New(events.TypeSalesOrderStatus, events.SalesOrderStatus{
+		ID:             sale.ID,
+		NomorTransaksi: sale.NomorTransaksi,
+		WarehouseID:    sale.IDGudang,
+		CustomerID:     sale.IDPelanggan,
+		Status:         sale.Status,
+		Total:          sale.Total,
+		TotalPaid:      sale.TotalDibayar,
+		Balance:        saleBalance(sale),
+	}, websocket.TopicWarehouse(sale.IDGudang), websocket.TopicPermission(middleware.PermSalesRead))
+}
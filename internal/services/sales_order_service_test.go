@@ -0,0 +1,77 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/models"
+	"testing"
+)
+
+func TestCanTransitionSalesOrder(t *testing.T) {
+	allowed := [][2]string{
+		{"ordered", "ready"},
+		{"ordered", "cancelled"},
+		{"ready", "delivered"},
+		{"ready", "cancelled"},
+		{"delivered", "paid"},
+	}
+	for _, tr := range allowed {
+		if !canTransitionSalesOrder(tr[0], tr[1]) {
+			t.Errorf("Expected %s -> %s to be allowed", tr[0], tr[1])
+		}
+	}
+
+	denied := [][2]string{
+		{"ordered", "delivered"}, // harus siap (stok keluar) dulu
+		{"delivered", "cancelled"},
+		{"paid", "cancelled"},
+		{"cancelled", "ready"},
+	}
+	for _, tr := range denied {
+		if canTransitionSalesOrder(tr[0], tr[1]) {
+			t.Errorf("Expected %s -> %s to be denied", tr[0], tr[1])
+		}
+	}
+}
+
+func TestSettledOrderStatus(t *testing.T) {
+	if got := settledOrderStatus("delivered", 1000000, 1000000); got != "paid" {
+		t.Errorf("Expected paid, got %s", got)
+	}
+	if got := settledOrderStatus("delivered", 1000000, 400000); got != "delivered" {
+		t.Errorf("Expected delivered while balance remains, got %s", got)
+	}
+	// Lunas sebelum dikirim tetap menunggu pengiriman
+	if got := settledOrderStatus("ready", 1000000, 1000000); got != "ready" {
+		t.Errorf("Expected ready, got %s", got)
+	}
+}
+
+func TestSaleBalance(t *testing.T) {
+	order := &models.Penjualan{TipePenjualan: "pesanan", Status: "delivered", Total: 1500000, TotalDibayar: 500000}
+	if got := saleBalance(order); got != 1000000 {
+		t.Errorf("Expected balance 1000000, got %.2f", got)
+	}
+
+	order.Status = "cancelled"
+	if got := saleBalance(order); got != 0 {
+		t.Errorf("Expected no balance for cancelled order, got %.2f", got)
+	}
+
+	pos := &models.Penjualan{TipePenjualan: "pos", Status: "completed", Total: 1500000}
+	if got := saleBalance(pos); got != 0 {
+		t.Errorf("Expected no balance for POS sale, got %.2f", got)
+	}
+}
+
+func TestSaleItemAmounts(t *testing.T) {
+	persen := 10.0
+	diskon, subtotal := saleItemAmounts(dto.SalesItemRequest{Jumlah: 2, HargaSatuan: 150000, PersenDiskon: &persen})
+	if diskon != 30000 || subtotal != 270000 {
+		t.Errorf("Expected diskon 30000 subtotal 270000, got %.2f %.2f", diskon, subtotal)
+	}
+
+	diskon, subtotal = saleItemAmounts(dto.SalesItemRequest{Jumlah: 3, HargaSatuan: 100000})
+	if diskon != 0 || subtotal != 300000 {
+		t.Errorf("Expected no discount subtotal 300000, got %.2f %.2f", diskon, subtotal)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"math"
 	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
 	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
 	"time"
 
 	"gorm.io/gorm"
@@ -18,38 +21,55 @@ type SalesService interface {
 	GetInvoice(id uint) (*dto.InvoiceResponse, error)
 	ListSales(req *dto.ListSalesRequest) (*dto.ListSalesResponse, error)
 	UpdateBuktiBayar(id uint, filePath string) error
+	VoidSale(id, userID uint, alasan string) (*dto.SalesDetailResponse, error)
 }
 
 type salesService struct {
 	repo      repositories.SalesRepository
 	stockRepo repositories.StockRepository
 	batchRepo repositories.StockBatchRepository
+	custRepo  repositories.PelangganRepository
+	shiftRepo repositories.ShiftRepository
+	publisher events.Publisher
 }
 
 func NewSalesService(
 	repo repositories.SalesRepository,
 	stockRepo repositories.StockRepository,
 	batchRepo repositories.StockBatchRepository,
+	custRepo repositories.PelangganRepository,
+	shiftRepo repositories.ShiftRepository,
+	publisher events.Publisher,
 ) SalesService {
 	return &salesService{
 		repo:      repo,
 		stockRepo: stockRepo,
 		batchRepo: batchRepo,
+		custRepo:  custRepo,
+		shiftRepo: shiftRepo,
+		publisher: publisher,
 	}
 }
 
 // CreateSale adalah fungsi utama yang memproses transaksi penjualan POS.
 // Semua operasi dijalankan dalam 1 DB transaction untuk menjamin atomicity.
 //
+// Kasir wajib punya shift yang sedang buka di gudang yang sama; transaksi & baris pembayarannya
+// dicatat ke shift tersebut untuk rekonsiliasi kas saat tutup shift.
+//
 // Alur FIFO:
 //  1. Validasi stok tersedia per item
 //  2. Untuk setiap item: ambil batches FIFO (terlama dulu), deduct, catat breakdown
 //  3. Hitung COGS per item dari batch yang terpakai
 //  4. Log pergerakan stok (tipe_referensi = "sales")
 //  5. Buat barang_keluar header
-//  6. Buat penjualan + item_penjualan + item_penjualan_batch
-//  7. Commit
+//  6. Buat penjualan + item_penjualan + item_penjualan_batch + pembayaran_penjualan (split tender)
+//  7. Catat event sale.created & stock.changed ke outbox, lalu commit
 func (s *salesService) CreateSale(userID uint, req *dto.CreateSalesRequest) (*dto.SalesDetailResponse, error) {
+	if err := s.applyPelanggan(req); err != nil {
+		return nil, err
+	}
+
 	tx := s.repo.BeginTx()
 	defer func() {
 		if r := recover(); r != nil {
@@ -57,6 +77,20 @@ func (s *salesService) CreateSale(userID uint, req *dto.CreateSalesRequest) (*dt
 		}
 	}()
 
+	shift, err := s.shiftRepo.FindOpenByKasir(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengecek shift kasir: %w", err)
+	}
+	if shift == nil {
+		tx.Rollback()
+		return nil, errors.New("shift kasir belum dibuka, buka shift terlebih dahulu")
+	}
+	if shift.IDGudang != req.IDGudang {
+		tx.Rollback()
+		return nil, fmt.Errorf("shift kasir %s dibuka untuk gudang lain", shift.NomorShift)
+	}
+
 	now := time.Now()
 
 	// Nomor transaksi: TRX/YYYYMMDD/HHMMSS/userID
@@ -96,94 +130,38 @@ func (s *salesService) CreateSale(userID uint, req *dto.CreateSalesRequest) (*dt
 
 	// 3. Build header Penjualan
 	sale := models.Penjualan{
-		NomorTransaksi:   nomorTransaksi,
-		IDGudang:         req.IDGudang,
-		NamaPelanggan:    req.NamaPelanggan,
-		KontakPelanggan:  req.KontakPelanggan,
-		MetodePembayaran: req.MetodePembayaran,
-		JumlahPembayaran: req.JumlahPembayaran,
-		Status:           "completed",
-		CatatanInternal:  req.CatatanInternal,
-		IDKasir:          userID,
-		DibuatPada:       now,
-		DiperbaruiPada:   now,
+		NomorTransaksi:  nomorTransaksi,
+		TipePenjualan:   "pos",
+		IDGudang:        req.IDGudang,
+		IDPelanggan:     req.IDPelanggan,
+		NamaPelanggan:   req.NamaPelanggan,
+		KontakPelanggan: req.KontakPelanggan,
+		Status:          "completed",
+		CatatanInternal: req.CatatanInternal,
+		IDKasir:         userID,
+		IDShift:         &shift.ID,
+		DibuatPada:      now,
+		DiperbaruiPada:  now,
 	}
 
 	// 4. Proses setiap item: FIFO deduct, hitung COGS, buat item
 	var grandSubtotal, grandDiskon, grandTotal, grandHargaModal float64
 	var saleItems []models.ItemPenjualan
+	var stockChanges []stockChange
 
 	for _, itemReq := range req.Items {
 		// Hitung diskon item
-		var jumlahDiskon float64
-		if itemReq.PersenDiskon != nil && *itemReq.PersenDiskon > 0 {
-			jumlahDiskon = math.Round(itemReq.HargaSatuan*float64(itemReq.Jumlah)*(*itemReq.PersenDiskon)/100*100) / 100
-		}
-		subtotalItem := math.Round(itemReq.HargaSatuan*float64(itemReq.Jumlah)*100)/100 - jumlahDiskon
-
-		// FIFO: Ambil dan deduct batches
-		batches, _ := s.batchRepo.GetAvailableBatches(tx, itemReq.IDProduk, req.IDGudang)
-		remainingQty := itemReq.Jumlah
-		var totalCOGSItem float64
-		var batchUsageRecords []models.ItemPenjualanBatch
-
-		for i := range batches {
-			if remainingQty == 0 {
-				break
-			}
-			batch := &batches[i]
-			deductQty := 0
-			if batch.JumlahSaatIni >= remainingQty {
-				deductQty = remainingQty
-				remainingQty = 0
-			} else {
-				deductQty = batch.JumlahSaatIni
-				remainingQty -= batch.JumlahSaatIni
-			}
-
-			totalCOGSBatch := math.Round(batch.HargaModal*float64(deductQty)*100) / 100
-			totalCOGSItem += totalCOGSBatch
-
-			// Catat breakdown batch untuk item ini
-			batchUsageRecords = append(batchUsageRecords, models.ItemPenjualanBatch{
-				IDBatch:    batch.ID,
-				Jumlah:     deductQty,
-				HargaModal: batch.HargaModal,
-				TotalModal: totalCOGSBatch,
-				DibuatPada: now,
-			})
-
-			// Deduct batch
-			batch.JumlahSaatIni -= deductQty
-			if err := s.batchRepo.Update(tx, batch); err != nil {
-				tx.Rollback()
-				return nil, fmt.Errorf("gagal update batch #%d: %w", batch.ID, err)
-			}
-
-			// Log pergerakan stok per batch
-			movement := models.PergerakanStok{
-				IDProduk:       itemReq.IDProduk,
-				IDGudang:       req.IDGudang,
-				IDBatch:        &batch.ID,
-				TipePergerakan: "out",
-				TipeReferensi:  "sales",
-				IDReferensi:    &headerKeluar.ID,
-				Jumlah:         -deductQty,
-				IDPengguna:     userID,
-				Keterangan:     fmt.Sprintf("Penjualan %s (Batch #%d, HPP: %.2f)", nomorTransaksi, batch.ID, batch.HargaModal),
-				DibuatPada:     now,
-			}
-			if err := s.stockRepo.CreateStockMovement(tx, &movement); err != nil {
-				tx.Rollback()
-				return nil, fmt.Errorf("gagal log pergerakan stok: %w", err)
-			}
-		}
+		jumlahDiskon, subtotalItem := saleItemAmounts(itemReq)
 
-		// Update total stok inventori
-		if err := s.stockRepo.UpdateStockBalance(tx, itemReq.IDProduk, req.IDGudang, -itemReq.Jumlah); err != nil {
+		// FIFO: deduct batch, log pergerakan stok per batch, update saldo
+		batchUsageRecords, totalCOGSItem, err := consumeSaleItemFIFO(tx, s.stockRepo, s.batchRepo,
+			itemReq.IDProduk, req.IDGudang, itemReq.Jumlah, userID, "sales", headerKeluar.ID,
+			fmt.Sprintf("Penjualan %s", nomorTransaksi), now)
+		if err != nil {
 			tx.Rollback()
-			return nil, fmt.Errorf("gagal update stok inventori produk %d: %w", itemReq.IDProduk, err)
+			return nil, err
 		}
+		stockChanges = append(stockChanges, stockChange{ProductID: itemReq.IDProduk, WarehouseID: req.IDGudang, Delta: -itemReq.Jumlah})
 
 		// COGS per unit (rata-rata tertimbang)
 		hargaModalPerUnit := 0.0
@@ -213,19 +191,28 @@ func (s *salesService) CreateSale(userID uint, req *dto.CreateSalesRequest) (*dt
 		grandHargaModal += totalCOGSItem
 	}
 
-	// 5. Hitung kembalian
-	kembalian := 0.0
-	if req.MetodePembayaran == "cash" && req.JumlahPembayaran >= grandTotal {
-		kembalian = math.Round((req.JumlahPembayaran-grandTotal)*100) / 100
-	}
-
 	sale.Subtotal = math.Round(grandSubtotal*100) / 100
 	sale.JumlahDiskon = math.Round(grandDiskon*100) / 100
 	sale.Total = math.Round(grandTotal*100) / 100
 	sale.TotalHargaModal = math.Round(grandHargaModal*100) / 100
-	sale.JumlahKembalian = kembalian
 	sale.Items = saleItems
 
+	// 5. Baris pembayaran & kembalian (hanya dari porsi cash)
+	tenders, err := resolveSaleTenders(req, sale.Total)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for i := range tenders.Lines {
+		tenders.Lines[i].DiterimaOleh = userID
+		tenders.Lines[i].IDShift = &shift.ID
+		tenders.Lines[i].DibuatPada = now
+	}
+	sale.MetodePembayaran = tenders.Metode
+	sale.JumlahPembayaran = tenders.Diterima
+	sale.JumlahKembalian = tenders.Kembalian
+	sale.Pembayaran = tenders.Lines
+
 	// Update IDReferensi di barang_keluar setelah sale dibuat (chicken-egg, skip untuk simplicity)
 	// Sudah ada nomor_transaksi di keterangan movement
 
@@ -241,6 +228,21 @@ func (s *salesService) CreateSale(userID uint, req *dto.CreateSalesRequest) (*dt
 		return nil, fmt.Errorf("gagal link barang_keluar ke penjualan: %w", err)
 	}
 
+	// 8. Catat event ke outbox (ikut commit / rollback)
+	saleCreated := events.New(events.TypeSaleCreated, events.SaleCreated{
+		ID:             sale.ID,
+		NomorTransaksi: sale.NomorTransaksi,
+		WarehouseID:    sale.IDGudang,
+		CashierID:      sale.IDKasir,
+		PaymentMethod:  sale.MetodePembayaran,
+		Total:          sale.Total,
+		ItemCount:      len(sale.Items),
+	}, websocket.TopicWarehouse(sale.IDGudang), websocket.TopicPermission(middleware.PermSalesRead))
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "sales", sale.ID, stockChanges, saleCreated); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event penjualan: %w", err)
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
 	}
@@ -293,12 +295,16 @@ func (s *salesService) ListSales(req *dto.ListSalesRequest) (*dto.ListSalesRespo
 		responses = append(responses, dto.SalesResponse{
 			ID:               sale.ID,
 			NomorTransaksi:   sale.NomorTransaksi,
+			TipePenjualan:    sale.TipePenjualan,
 			NamaGudang:       sale.Gudang.Nama,
+			IDPelanggan:      sale.IDPelanggan,
 			NamaPelanggan:    sale.NamaPelanggan,
 			KontakPelanggan:  sale.KontakPelanggan,
 			Total:            sale.Total,
 			TotalHargaModal:  sale.TotalHargaModal,
 			Laba:             math.Round(laba*100) / 100,
+			TotalDibayar:     sale.TotalDibayar,
+			SisaTagihan:      saleBalance(&sale),
 			MetodePembayaran: sale.MetodePembayaran,
 			Status:           sale.Status,
 			NamaKasir:        sale.Kasir.Nama,
@@ -315,6 +321,127 @@ func (s *salesService) ListSales(req *dto.ListSalesRequest) (*dto.ListSalesRespo
 	}, nil
 }
 
+// applyPelanggan memvalidasi pelanggan (jika dipilih) dan mengisi snapshot nama & kontak yang kosong
+func (s *salesService) applyPelanggan(req *dto.CreateSalesRequest) error {
+	return resolvePelanggan(s.custRepo, req.IDPelanggan, &req.NamaPelanggan, &req.KontakPelanggan)
+}
+
+// resolvePelanggan dipakai penjualan POS dan pesanan: pelanggan harus ada & aktif,
+// nama/kontak yang kosong diisi dari master sebagai snapshot transaksi
+func resolvePelanggan(custRepo repositories.PelangganRepository, id *uint, nama, kontak *string) error {
+	if id == nil {
+		return nil
+	}
+	pelanggan, err := custRepo.FindByID(*id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("pelanggan tidak ditemukan")
+		}
+		return err
+	}
+	if !pelanggan.Aktif {
+		return fmt.Errorf("pelanggan %s tidak aktif", pelanggan.Nama)
+	}
+	if *nama == "" {
+		*nama = pelanggan.Nama
+	}
+	if *kontak == "" {
+		*kontak = pelanggan.Telepon
+	}
+	return nil
+}
+
+// saleItemAmounts menghitung nominal diskon dan subtotal (setelah diskon) satu item penjualan
+func saleItemAmounts(item dto.SalesItemRequest) (float64, float64) {
+	var jumlahDiskon float64
+	if item.PersenDiskon != nil && *item.PersenDiskon > 0 {
+		jumlahDiskon = math.Round(item.HargaSatuan*float64(item.Jumlah)*(*item.PersenDiskon)/100*100) / 100
+	}
+	subtotal := math.Round(item.HargaSatuan*float64(item.Jumlah)*100)/100 - jumlahDiskon
+	return jumlahDiskon, subtotal
+}
+
+// saleTenders adalah hasil perhitungan pembayaran POS
+type saleTenders struct {
+	Lines     []models.PembayaranPenjualan
+	Metode    string  // metode tunggal, atau "split" jika lebih dari satu metode
+	Diterima  float64 // total uang diterima (cash sebelum kembalian)
+	Kembalian float64
+}
+
+// resolveSaleTenders menyusun baris pembayaran POS dari request (split tender atau satu metode).
+// Non-cash tidak boleh melebihi total karena tidak bisa diberi kembalian; cash menutup sisanya
+// dan kelebihannya menjadi kembalian. Jumlah baris cash disimpan setelah dikurangi kembalian.
+func resolveSaleTenders(req *dto.CreateSalesRequest, total float64) (*saleTenders, error) {
+	lines := req.Pembayaran
+	if len(lines) == 0 {
+		if req.MetodePembayaran == "" || req.JumlahPembayaran <= 0 {
+			return nil, errors.New("pembayaran wajib diisi (metode_pembayaran & jumlah_pembayaran, atau daftar pembayaran)")
+		}
+		lines = []dto.SalesPaymentLineRequest{{MetodePembayaran: req.MetodePembayaran, Jumlah: req.JumlahPembayaran}}
+	}
+
+	var cash, nonCash float64
+	metode := ""
+	for _, l := range lines {
+		if l.Jumlah <= 0 {
+			return nil, errors.New("jumlah setiap pembayaran harus lebih dari 0")
+		}
+		if l.MetodePembayaran == "cash" {
+			cash += l.Jumlah
+		} else {
+			nonCash += l.Jumlah
+		}
+		if metode == "" {
+			metode = l.MetodePembayaran
+		} else if metode != l.MetodePembayaran {
+			metode = "split"
+		}
+	}
+
+	totalCents := math.Round(total * 100)
+	if math.Round(nonCash*100) > totalCents {
+		return nil, fmt.Errorf("pembayaran non-tunai (%.2f) melebihi total transaksi (%.2f)", nonCash, total)
+	}
+	if math.Round((cash+nonCash)*100) < totalCents {
+		return nil, fmt.Errorf("jumlah pembayaran (%.2f) kurang dari total transaksi (%.2f)", cash+nonCash, total)
+	}
+
+	result := &saleTenders{
+		Metode:    metode,
+		Diterima:  math.Round((cash+nonCash)*100) / 100,
+		Kembalian: math.Round((cash+nonCash-total)*100) / 100,
+	}
+	sisaKembalian := result.Kembalian
+	for _, l := range lines {
+		jumlah := l.Jumlah
+		if l.MetodePembayaran == "cash" && sisaKembalian > 0 {
+			potong := math.Min(jumlah, sisaKembalian)
+			jumlah = math.Round((jumlah-potong)*100) / 100
+			sisaKembalian = math.Round((sisaKembalian-potong)*100) / 100
+		}
+		if jumlah <= 0 {
+			continue
+		}
+		result.Lines = append(result.Lines, models.PembayaranPenjualan{
+			Jenis:            "pos",
+			MetodePembayaran: l.MetodePembayaran,
+			Jumlah:           jumlah,
+			NomorReferensi:   l.NomorReferensi,
+			BuktiPembayaran:  l.BuktiPembayaran,
+		})
+	}
+	return result, nil
+}
+
+// saleBalance mengembalikan sisa tagihan penjualan. POS selalu lunas; pesanan batal tidak ditagih.
+func saleBalance(sale *models.Penjualan) float64 {
+	if sale.TipePenjualan != "pesanan" || sale.Status == "cancelled" {
+		return 0
+	}
+	return math.Round((sale.Total-sale.TotalDibayar)*100) / 100
+}
+
 func (s *salesService) UpdateBuktiBayar(id uint, filePath string) error {
 	// Pastikan penjualan ada
 	_, err := s.repo.FindByID(id)
@@ -327,6 +454,71 @@ func (s *salesService) UpdateBuktiBayar(id uint, filePath string) error {
 	return s.repo.UpdateBuktiBayar(id, filePath)
 }
 
+// VoidSale membatalkan transaksi penjualan yang sudah completed.
+// Setiap qty di ItemPenjualanBatch dikembalikan ke batch asalnya (batch diaktifkan kembali),
+// stok_inventori dinaikkan, dan pergerakan stok kompensasi dicatat (tipe_referensi = "sales_void").
+// Transaksi yang sudah punya retur (selain yang ditolak) tidak dapat di-void.
+func (s *salesService) VoidSale(id, userID uint, alasan string) (*dto.SalesDetailResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	sale, err := s.repo.FindByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaksi penjualan tidak ditemukan")
+		}
+		return nil, err
+	}
+	if sale.Status != "completed" {
+		tx.Rollback()
+		return nil, fmt.Errorf("transaksi dalam status '%s', tidak dapat di-void", sale.Status)
+	}
+
+	activeReturns, err := s.repo.CountActiveReturns(tx, sale.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengecek retur penjualan: %w", err)
+	}
+	if activeReturns > 0 {
+		tx.Rollback()
+		return nil, errors.New("transaksi sudah memiliki retur, tidak dapat di-void")
+	}
+
+	now := time.Now()
+	var stockChanges []stockChange
+
+	for _, item := range sale.Items {
+		changes, err := restoreSaleItemBatches(tx, s.stockRepo, s.batchRepo, item, userID, "sales_void", sale.ID,
+			fmt.Sprintf("Void %s", sale.NomorTransaksi), alasan, now)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		stockChanges = append(stockChanges, changes...)
+	}
+
+	if err := s.repo.MarkVoided(tx, sale.ID, userID, alasan, now); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membatalkan transaksi: %w", err)
+	}
+
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "sales_void", sale.ID, stockChanges); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event void: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetSaleByID(sale.ID)
+}
+
 // ===========================
 // MAPPING HELPERS
 // ===========================
@@ -362,11 +554,17 @@ func mapSaleToDetailResponse(sale *models.Penjualan) *dto.SalesDetailResponse {
 	}
 
 	laba := sale.Total - sale.TotalHargaModal
+	namaPembatal := ""
+	if sale.DibatalkanOlehPengguna != nil {
+		namaPembatal = sale.DibatalkanOlehPengguna.Nama
+	}
 	return &dto.SalesDetailResponse{
 		ID:               sale.ID,
 		NomorTransaksi:   sale.NomorTransaksi,
+		TipePenjualan:    sale.TipePenjualan,
 		IDGudang:         sale.IDGudang,
 		NamaGudang:       sale.Gudang.Nama,
+		IDPelanggan:      sale.IDPelanggan,
 		NamaPelanggan:    sale.NamaPelanggan,
 		KontakPelanggan:  sale.KontakPelanggan,
 		Subtotal:         sale.Subtotal,
@@ -377,14 +575,45 @@ func mapSaleToDetailResponse(sale *models.Penjualan) *dto.SalesDetailResponse {
 		MetodePembayaran: sale.MetodePembayaran,
 		JumlahPembayaran: sale.JumlahPembayaran,
 		JumlahKembalian:  sale.JumlahKembalian,
+		TotalDibayar:     sale.TotalDibayar,
+		SisaTagihan:      saleBalance(sale),
 		BuktiBayar:       sale.BuktiBayar,
 		Status:           sale.Status,
 		CatatanInternal:  sale.CatatanInternal,
 		IDKasir:          sale.IDKasir,
 		NamaKasir:        sale.Kasir.Nama,
 		DibuatPada:       sale.DibuatPada,
+		AlasanPembatalan: sale.AlasanPembatalan,
+		NamaPembatal:     namaPembatal,
+		DibatalkanPada:   sale.DibatalkanPada,
 		Items:            items,
+
+		AlamatPengiriman:  sale.AlamatPengiriman,
+		TanggalPengiriman: sale.TanggalPengiriman,
+		DisiapkanPada:     sale.DisiapkanPada,
+		DikirimPada:       sale.DikirimPada,
+		LunasPada:         sale.LunasPada,
+		JatuhTempo:        sale.JatuhTempo,
+		Pembayaran:        mapSalePayments(sale),
+	}
+}
+
+func mapSalePayments(sale *models.Penjualan) []dto.SalesPaymentResponse {
+	var payments []dto.SalesPaymentResponse
+	for _, p := range sale.Pembayaran {
+		payments = append(payments, dto.SalesPaymentResponse{
+			ID:               p.ID,
+			Jenis:            p.Jenis,
+			MetodePembayaran: p.MetodePembayaran,
+			Jumlah:           p.Jumlah,
+			NomorReferensi:   p.NomorReferensi,
+			Keterangan:       p.Keterangan,
+			BuktiPembayaran:  p.BuktiPembayaran,
+			NamaPenerima:     p.DiterimaOlehPengguna.Nama,
+			DibuatPada:       p.DibuatPada,
+		})
 	}
+	return payments
 }
 
 func mapSaleToInvoice(sale *models.Penjualan) *dto.InvoiceResponse {
@@ -414,6 +643,7 @@ func mapSaleToInvoice(sale *models.Penjualan) *dto.InvoiceResponse {
 		MetodePembayaran: sale.MetodePembayaran,
 		JumlahPembayaran: sale.JumlahPembayaran,
 		JumlahKembalian:  sale.JumlahKembalian,
+		Pembayaran:       mapSalePayments(sale),
 		Status:           sale.Status,
 	}
 }
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/dto"
+	"testing"
+)
+
+func TestResolveSaleTendersSplit(t *testing.T) {
+	req := &dto.CreateSalesRequest{Pembayaran: []dto.SalesPaymentLineRequest{
+		{MetodePembayaran: "qris", Jumlah: 600000, NomorReferensi: "QR-001"},
+		{MetodePembayaran: "cash", Jumlah: 500000},
+	}}
+
+	tenders, err := resolveSaleTenders(req, 1000000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tenders.Metode != "split" {
+		t.Errorf("Expected metode split, got %s", tenders.Metode)
+	}
+	if tenders.Diterima != 1100000 || tenders.Kembalian != 100000 {
+		t.Errorf("Expected diterima 1100000 kembalian 100000, got %.2f %.2f", tenders.Diterima, tenders.Kembalian)
+	}
+	// Baris cash disimpan setelah dikurangi kembalian
+	if len(tenders.Lines) != 2 || tenders.Lines[1].Jumlah != 400000 {
+		t.Fatalf("Expected cash line 400000, got %+v", tenders.Lines)
+	}
+	if tenders.Lines[0].NomorReferensi != "QR-001" || tenders.Lines[0].Jenis != "pos" {
+		t.Errorf("Expected qris line with reference, got %+v", tenders.Lines[0])
+	}
+}
+
+func TestResolveSaleTendersSingleMethod(t *testing.T) {
+	req := &dto.CreateSalesRequest{MetodePembayaran: "cash", JumlahPembayaran: 200000}
+	tenders, err := resolveSaleTenders(req, 175000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tenders.Metode != "cash" || tenders.Kembalian != 25000 {
+		t.Errorf("Expected cash with kembalian 25000, got %s %.2f", tenders.Metode, tenders.Kembalian)
+	}
+	if len(tenders.Lines) != 1 || tenders.Lines[0].Jumlah != 175000 {
+		t.Errorf("Expected one line of 175000, got %+v", tenders.Lines)
+	}
+}
+
+func TestResolveSaleTendersRejected(t *testing.T) {
+	cases := map[string]*dto.CreateSalesRequest{
+		// Non-tunai tidak bisa diberi kembalian
+		"non-cash overpay": {Pembayaran: []dto.SalesPaymentLineRequest{
+			{MetodePembayaran: "debit", Jumlah: 1200000},
+		}},
+		"underpaid": {Pembayaran: []dto.SalesPaymentLineRequest{
+			{MetodePembayaran: "transfer", Jumlah: 500000},
+			{MetodePembayaran: "cash", Jumlah: 400000},
+		}},
+		"no payment": {},
+	}
+	for name, req := range cases {
+		if _, err := resolveSaleTenders(req, 1000000); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
+
+	"gorm.io/gorm"
+)
+
+type ShiftService interface {
+	OpenShift(userID uint, req *dto.OpenShiftRequest) (*dto.ShiftResponse, error)
+	GetCurrentShift(userID uint) (*dto.ShiftResponse, error)
+	// manage = true jika user boleh melihat / menutup shift kasir lain
+	GetShift(id, userID uint, manage bool) (*dto.ShiftResponse, error)
+	ListShifts(userID uint, manage bool, req *dto.ListShiftRequest) (*dto.ListShiftResponse, error)
+	CloseShift(id, userID uint, manage bool, req *dto.CloseShiftRequest) (*dto.ShiftReportResponse, error)
+	GetShiftReport(id, userID uint, manage bool) (*dto.ShiftReportResponse, error)
+}
+
+type shiftService struct {
+	repo      repositories.ShiftRepository
+	publisher events.Publisher
+}
+
+func NewShiftService(repo repositories.ShiftRepository, publisher events.Publisher) ShiftService {
+	return &shiftService{
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+// OpenShift membuka shift baru dengan modal kas awal. Satu kasir hanya boleh punya satu shift open.
+func (s *shiftService) OpenShift(userID uint, req *dto.OpenShiftRequest) (*dto.ShiftResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	existing, err := s.repo.FindOpenByKasir(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mengecek shift kasir: %w", err)
+	}
+	if existing != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("kasir masih memiliki shift %s yang belum ditutup", existing.NomorShift)
+	}
+
+	now := time.Now()
+	shift := models.ShiftKasir{
+		NomorShift:     fmt.Sprintf("SHIFT/%s/%d", now.Format("20060102150405"), userID),
+		IDKasir:        userID,
+		IDGudang:       req.IDGudang,
+		Status:         "open",
+		KasAwal:        math.Round(req.KasAwal*100) / 100,
+		KasDiharapkan:  math.Round(req.KasAwal*100) / 100,
+		CatatanBuka:    req.Catatan,
+		DibukaPada:     now,
+		DibuatPada:     now,
+		DiperbaruiPada: now,
+	}
+	if err := s.repo.Create(tx, &shift); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal membuka shift: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetShift(shift.ID, userID, false)
+}
+
+func (s *shiftService) GetCurrentShift(userID uint) (*dto.ShiftResponse, error) {
+	shift, err := s.repo.FindOpenByKasir(nil, userID)
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil {
+		return nil, errors.New("shift kasir belum dibuka")
+	}
+	return s.GetShift(shift.ID, userID, false)
+}
+
+func (s *shiftService) GetShift(id, userID uint, manage bool) (*dto.ShiftResponse, error) {
+	shift, err := s.findShift(id, userID, manage)
+	if err != nil {
+		return nil, err
+	}
+	return mapShiftToResponse(shift), nil
+}
+
+func (s *shiftService) ListShifts(userID uint, manage bool, req *dto.ListShiftRequest) (*dto.ListShiftResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	filters := map[string]interface{}{
+		"id_kasir":       req.IDKasir,
+		"id_gudang":      req.IDGudang,
+		"status":         req.Status,
+		"tanggal_dari":   req.TanggalDari,
+		"tanggal_sampai": req.TanggalSampai,
+	}
+	// Tanpa shift.manage kasir hanya melihat shift miliknya sendiri
+	if !manage {
+		filters["id_kasir"] = userID
+	}
+
+	shifts, total, err := s.repo.List(filters, req.Page, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ShiftResponse, len(shifts))
+	for i := range shifts {
+		responses[i] = *mapShiftToResponse(&shifts[i])
+	}
+
+	return &dto.ListShiftResponse{
+		Shifts:     responses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}, nil
+}
+
+// CloseShift menutup shift: menghitung kas yang diharapkan dari transaksi shift, membandingkan
+// dengan kas fisik (dan hitungan non-tunai jika ada), lalu menyimpan rekap per metode sebagai Z report.
+// Row lock pada shift menunggu transaksi penjualan / refund yang sedang berjalan di shift ini selesai.
+func (s *shiftService) CloseShift(id, userID uint, manage bool, req *dto.CloseShiftRequest) (*dto.ShiftReportResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	shift, err := s.repo.FindByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("shift kasir tidak ditemukan")
+		}
+		return nil, err
+	}
+	if shift.IDKasir != userID && !manage {
+		tx.Rollback()
+		return nil, errors.New("tidak berhak mengakses shift kasir lain")
+	}
+	if shift.Status != "open" {
+		tx.Rollback()
+		return nil, fmt.Errorf("shift dalam status '%s', hanya shift open yang dapat ditutup", shift.Status)
+	}
+
+	payments, err := s.repo.PaymentTotals(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal menghitung pembayaran shift: %w", err)
+	}
+	refund, err := s.repo.CashRefundTotal(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal menghitung refund shift: %w", err)
+	}
+	summary, err := s.repo.SalesSummary(tx, shift.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal menghitung penjualan shift: %w", err)
+	}
+
+	dihitung := make(map[string]float64, len(req.Dihitung))
+	for _, d := range req.Dihitung {
+		if _, dup := dihitung[d.MetodePembayaran]; dup {
+			tx.Rollback()
+			return nil, fmt.Errorf("metode %s diisi lebih dari sekali", d.MetodePembayaran)
+		}
+		dihitung[d.MetodePembayaran] = d.Jumlah
+	}
+
+	rekap := buildShiftRekap(shift.KasAwal, payments, refund.Total, *req.KasDihitung, dihitung)
+	for i := range rekap {
+		rekap[i].IDShift = shift.ID
+	}
+	cash := rekap[0] // Baris cash selalu pertama
+
+	now := time.Now()
+	shift.Status = "closed"
+	shift.KasDiharapkan = cash.Diharapkan
+	shift.KasDihitung = cash.Dihitung
+	shift.SelisihKas = cash.Selisih
+	shift.JumlahTransaksi = summary.JumlahTransaksi
+	shift.TotalPenjualan = summary.TotalPenjualan
+	shift.TotalDiskon = summary.TotalDiskon
+	shift.JumlahVoid = summary.JumlahVoid
+	shift.NilaiVoid = summary.NilaiVoid
+	shift.JumlahRefund = refund.JumlahRetur
+	shift.RefundTunai = refund.Total
+	shift.CatatanTutup = req.Catatan
+	shift.DitutupPada = &now
+	shift.DitutupOleh = &userID
+	shift.DiperbaruiPada = now
+
+	if err := s.repo.Close(tx, shift, rekap); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal menutup shift: %w", err)
+	}
+
+	event := events.New(events.TypeShiftClosed, events.ShiftClosed{
+		ID:           shift.ID,
+		NomorShift:   shift.NomorShift,
+		WarehouseID:  shift.IDGudang,
+		CashierID:    shift.IDKasir,
+		ExpectedCash: shift.KasDiharapkan,
+		CountedCash:  *shift.KasDihitung,
+		Variance:     shift.SelisihKas,
+	}, websocket.TopicWarehouse(shift.IDGudang), websocket.TopicPermission(middleware.PermShiftManage))
+	if err := s.publisher.Publish(tx, event); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("gagal mencatat event shift: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("gagal commit transaksi: %w", err)
+	}
+
+	return s.GetShiftReport(shift.ID, userID, true)
+}
+
+// GetShiftReport menyusun laporan shift siap cetak: Z report untuk shift closed (ringkasan & rekap
+// yang disimpan saat tutup), X report untuk shift yang masih open (rekap berjalan tanpa hitungan fisik).
+func (s *shiftService) GetShiftReport(id, userID uint, manage bool) (*dto.ShiftReportResponse, error) {
+	shift, err := s.findShift(id, userID, manage)
+	if err != nil {
+		return nil, err
+	}
+
+	rekap := shift.Rekap
+	jenis := "Z"
+	summary := &repositories.ShiftSalesSummary{
+		JumlahTransaksi: shift.JumlahTransaksi,
+		TotalPenjualan:  shift.TotalPenjualan,
+		TotalDiskon:     shift.TotalDiskon,
+		JumlahVoid:      shift.JumlahVoid,
+		NilaiVoid:       shift.NilaiVoid,
+	}
+	refund := &repositories.ShiftRefundTotal{JumlahRetur: shift.JumlahRefund, Total: shift.RefundTunai}
+	if shift.Status == "open" {
+		jenis = "X"
+		summary, err = s.repo.SalesSummary(nil, shift.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menghitung penjualan shift: %w", err)
+		}
+		refund, err = s.repo.CashRefundTotal(nil, shift.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menghitung refund shift: %w", err)
+		}
+		payments, err := s.repo.PaymentTotals(nil, shift.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal menghitung pembayaran shift: %w", err)
+		}
+		rekap = buildShiftRekap(shift.KasAwal, payments, refund.Total, 0, nil)
+		rekap[0].Dihitung = nil
+		rekap[0].Selisih = 0
+	}
+
+	report := &dto.ShiftReportResponse{
+		JenisLaporan:    jenis,
+		NomorShift:      shift.NomorShift,
+		NamaGudang:      shift.Gudang.Nama,
+		NamaKasir:       shift.Kasir.Nama,
+		Status:          shift.Status,
+		DibukaPada:      shift.DibukaPada,
+		DitutupPada:     shift.DitutupPada,
+		DicetakPada:     time.Now(),
+		JumlahTransaksi: summary.JumlahTransaksi,
+		TotalPenjualan:  summary.TotalPenjualan,
+		TotalDiskon:     summary.TotalDiskon,
+		JumlahVoid:      summary.JumlahVoid,
+		NilaiVoid:       summary.NilaiVoid,
+		KasAwal:         shift.KasAwal,
+		JumlahRefund:    refund.JumlahRetur,
+		RefundTunai:     refund.Total,
+		KasDihitung:     shift.KasDihitung,
+		SelisihKas:      shift.SelisihKas,
+		CatatanBuka:     shift.CatatanBuka,
+		CatatanTutup:    shift.CatatanTutup,
+	}
+	if shift.DitutupOlehUser != nil {
+		report.DitutupOleh = shift.DitutupOlehUser.Nama
+	}
+
+	for _, r := range rekap {
+		report.Rekap = append(report.Rekap, dto.ShiftRekapResponse{
+			MetodePembayaran: r.MetodePembayaran,
+			JumlahTransaksi:  r.JumlahTransaksi,
+			Diharapkan:       r.Diharapkan,
+			Dihitung:         r.Dihitung,
+			Selisih:          r.Selisih,
+		})
+		report.TotalSelisih += r.Selisih
+		if r.MetodePembayaran == "cash" {
+			report.KasDiharapkan = r.Diharapkan
+			report.PenerimaanTunai = math.Round((r.Diharapkan-shift.KasAwal+refund.Total)*100) / 100
+			report.TotalDiterima += report.PenerimaanTunai
+			continue
+		}
+		report.TotalDiterima += r.Diharapkan
+	}
+	report.TotalDiterima = math.Round(report.TotalDiterima*100) / 100
+	report.TotalSelisih = math.Round(report.TotalSelisih*100) / 100
+
+	return report, nil
+}
+
+func (s *shiftService) findShift(id, userID uint, manage bool) (*models.ShiftKasir, error) {
+	shift, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("shift kasir tidak ditemukan")
+		}
+		return nil, err
+	}
+	if shift.IDKasir != userID && !manage {
+		return nil, errors.New("tidak berhak mengakses shift kasir lain")
+	}
+	return shift, nil
+}
+
+// buildShiftRekap menyusun rekonsiliasi per metode. Baris cash selalu ada dan selalu pertama:
+// diharapkan = kas awal + penerimaan tunai - refund tunai, dihitung = kas fisik di laci.
+// Metode non-tunai hanya punya selisih jika jumlahnya ikut dihitung (dihitung[metode]).
+func buildShiftRekap(kasAwal float64, payments []repositories.ShiftPaymentTotal, refundTunai, kasDihitung float64, dihitung map[string]float64) []models.RekapShiftKasir {
+	cash := models.RekapShiftKasir{MetodePembayaran: "cash", Diharapkan: kasAwal - refundTunai}
+	var others []models.RekapShiftKasir
+	seen := make(map[string]bool)
+
+	for _, p := range payments {
+		if p.MetodePembayaran == "cash" {
+			cash.JumlahTransaksi += p.JumlahTransaksi
+			cash.Diharapkan += p.Total
+			continue
+		}
+		seen[p.MetodePembayaran] = true
+		others = append(others, models.RekapShiftKasir{
+			MetodePembayaran: p.MetodePembayaran,
+			JumlahTransaksi:  p.JumlahTransaksi,
+			Diharapkan:       math.Round(p.Total*100) / 100,
+		})
+	}
+	// Metode yang dihitung tapi tidak ada transaksinya tetap dicatat (selisih = jumlah dihitung)
+	for metode := range dihitung {
+		if !seen[metode] {
+			others = append(others, models.RekapShiftKasir{MetodePembayaran: metode})
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].MetodePembayaran < others[j].MetodePembayaran })
+
+	cash.Diharapkan = math.Round(cash.Diharapkan*100) / 100
+	counted := math.Round(kasDihitung*100) / 100
+	cash.Dihitung = &counted
+	cash.Selisih = math.Round((counted-cash.Diharapkan)*100) / 100
+
+	for i := range others {
+		jumlah, ok := dihitung[others[i].MetodePembayaran]
+		if !ok {
+			continue
+		}
+		jumlah = math.Round(jumlah*100) / 100
+		others[i].Dihitung = &jumlah
+		others[i].Selisih = math.Round((jumlah-others[i].Diharapkan)*100) / 100
+	}
+
+	return append([]models.RekapShiftKasir{cash}, others...)
+}
+
+func mapShiftToResponse(shift *models.ShiftKasir) *dto.ShiftResponse {
+	return &dto.ShiftResponse{
+		ID:            shift.ID,
+		NomorShift:    shift.NomorShift,
+		IDKasir:       shift.IDKasir,
+		NamaKasir:     shift.Kasir.Nama,
+		IDGudang:      shift.IDGudang,
+		NamaGudang:    shift.Gudang.Nama,
+		Status:        shift.Status,
+		KasAwal:       shift.KasAwal,
+		KasDiharapkan: shift.KasDiharapkan,
+		KasDihitung:   shift.KasDihitung,
+		SelisihKas:    shift.SelisihKas,
+		CatatanBuka:   shift.CatatanBuka,
+		CatatanTutup:  shift.CatatanTutup,
+		DibukaPada:    shift.DibukaPada,
+		DitutupPada:   shift.DitutupPada,
+		DitutupOleh:   shift.DitutupOleh,
+	}
+}
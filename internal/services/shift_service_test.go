@@ -0,0 +1,59 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/repositories"
+	"testing"
+)
+
+func TestBuildShiftRekap(t *testing.T) {
+	payments := []repositories.ShiftPaymentTotal{
+		{MetodePembayaran: "cash", JumlahTransaksi: 3, Total: 750000},
+		{MetodePembayaran: "qris", JumlahTransaksi: 2, Total: 300000},
+		{MetodePembayaran: "transfer", JumlahTransaksi: 1, Total: 1200000},
+	}
+	// Kas awal 500rb, refund tunai 100rb, laci kurang 20rb; qris cocok, debit dihitung tanpa transaksi
+	rekap := buildShiftRekap(500000, payments, 100000, 1130000, map[string]float64{"qris": 300000, "debit": 50000})
+
+	if len(rekap) != 4 {
+		t.Fatalf("Expected 4 rows, got %d", len(rekap))
+	}
+
+	cash := rekap[0]
+	if cash.MetodePembayaran != "cash" || cash.JumlahTransaksi != 3 {
+		t.Errorf("Expected cash row first with 3 transactions, got %+v", cash)
+	}
+	if cash.Diharapkan != 1150000 {
+		t.Errorf("Expected cash 1150000 (500000 + 750000 - 100000), got %.2f", cash.Diharapkan)
+	}
+	if cash.Dihitung == nil || *cash.Dihitung != 1130000 || cash.Selisih != -20000 {
+		t.Errorf("Expected cash counted 1130000 with variance -20000, got %+v", cash)
+	}
+
+	byMetode := map[string]int{}
+	for i, r := range rekap {
+		byMetode[r.MetodePembayaran] = i
+	}
+	if debit := rekap[byMetode["debit"]]; debit.Diharapkan != 0 || debit.Selisih != 50000 {
+		t.Errorf("Expected debit variance 50000 without transactions, got %+v", debit)
+	}
+	if qris := rekap[byMetode["qris"]]; qris.Dihitung == nil || qris.Selisih != 0 {
+		t.Errorf("Expected qris counted with no variance, got %+v", qris)
+	}
+	if transfer := rekap[byMetode["transfer"]]; transfer.Dihitung != nil || transfer.Selisih != 0 {
+		t.Errorf("Expected transfer not counted, got %+v", transfer)
+	}
+	if rekap[1].MetodePembayaran != "debit" || rekap[3].MetodePembayaran != "transfer" {
+		t.Errorf("Expected non-cash rows sorted by method, got %s..%s", rekap[1].MetodePembayaran, rekap[3].MetodePembayaran)
+	}
+}
+
+func TestBuildShiftRekapWithoutTransactions(t *testing.T) {
+	rekap := buildShiftRekap(250000, nil, 0, 250000, nil)
+
+	if len(rekap) != 1 {
+		t.Fatalf("Expected only cash row, got %d rows", len(rekap))
+	}
+	if rekap[0].Diharapkan != 250000 || rekap[0].Selisih != 0 {
+		t.Errorf("Expected opening float to balance, got %+v", rekap[0])
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
+
+	"gorm.io/gorm"
+)
+
+// StockAlertService mengelola peringatan stok menipis (open → acknowledged → resolved).
+// Peringatan dibuka dan diselesaikan otomatis dari setiap perubahan saldo (lihat evaluateStockAlert).
+type StockAlertService interface {
+	ListAlerts(req *dto.ListStockAlertRequest) ([]dto.StockAlertResponse, int64, error)
+	GetAlertByID(id uint) (*dto.StockAlertResponse, error)
+	AcknowledgeAlert(id, userID uint, req dto.AcknowledgeStockAlertRequest) (*dto.StockAlertResponse, error)
+}
+
+type stockAlertService struct {
+	repo      repositories.StockRepository
+	publisher events.Publisher
+}
+
+func NewStockAlertService(repo repositories.StockRepository, publisher events.Publisher) StockAlertService {
+	return &stockAlertService{
+		repo:      repo,
+		publisher: publisher,
+	}
+}
+
+func (s *stockAlertService) ListAlerts(req *dto.ListStockAlertRequest) ([]dto.StockAlertResponse, int64, error) {
+	alerts, total, err := s.repo.FindAllStockAlerts(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := []dto.StockAlertResponse{}
+	for i := range alerts {
+		responses = append(responses, *mapStockAlertToResponse(&alerts[i]))
+	}
+	return responses, total, nil
+}
+
+func (s *stockAlertService) GetAlertByID(id uint) (*dto.StockAlertResponse, error) {
+	alert, err := s.repo.FindStockAlertByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stock alert not found")
+		}
+		return nil, err
+	}
+	return mapStockAlertToResponse(alert), nil
+}
+
+// AcknowledgeAlert menandai peringatan open sudah ditangani (misalnya PO sudah dibuat).
+// Peringatan tetap aktif sampai stok kembali di atas minimum.
+func (s *stockAlertService) AcknowledgeAlert(id, userID uint, req dto.AcknowledgeStockAlertRequest) (*dto.StockAlertResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	alert, err := s.repo.FindStockAlertByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stock alert not found")
+		}
+		return nil, err
+	}
+	if alert.Status != "open" {
+		tx.Rollback()
+		return nil, fmt.Errorf("stock alert is already '%s', only open alerts can be acknowledged", alert.Status)
+	}
+
+	now := time.Now()
+	alert.Status = "acknowledged"
+	alert.DikonfirmasiOleh = &userID
+	alert.DikonfirmasiPada = &now
+	alert.CatatanKonfirmasi = strings.TrimSpace(req.Notes)
+	alert.DiperbaruiPada = now
+	if err := s.repo.SaveStockAlert(tx, alert); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to acknowledge stock alert: %w", err)
+	}
+
+	stock, err := s.repo.FindStockBalance(tx, alert.IDProduk, alert.IDGudang)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.publisher.Publish(tx, stockAlertEvent(events.TypeStockAlertAcknowledged, alert, &stock.Produk)); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record events: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.GetAlertByID(id)
+}
+
+// evaluateStockAlert membandingkan saldo terbaru dengan stok minimum produk lalu membuka,
+// memperbarui, atau menyelesaikan peringatan di dalam tx. Event dikembalikan hanya saat status berubah.
+func evaluateStockAlert(tx *gorm.DB, repo repositories.StockRepository, stock *models.StokInventori, now time.Time) ([]events.Event, error) {
+	active, err := repo.FindActiveStockAlert(tx, stock.IDProduk, stock.IDGudang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stock alert: %w", err)
+	}
+
+	alert, eventType := nextStockAlert(active, stock, now)
+	if alert == nil {
+		return nil, nil
+	}
+	if err := repo.SaveStockAlert(tx, alert); err != nil {
+		return nil, fmt.Errorf("failed to save stock alert: %w", err)
+	}
+	if eventType == "" {
+		return nil, nil
+	}
+	return []events.Event{stockAlertEvent(eventType, alert, &stock.Produk)}, nil
+}
+
+// nextStockAlert menghitung perubahan peringatan dari saldo terbaru:
+//   - tidak ada peringatan aktif & saldo <= minimum → peringatan baru (stock.low)
+//   - peringatan aktif & saldo masih <= minimum → jumlah terakhir diperbarui (tanpa event)
+//   - peringatan aktif & saldo > minimum (atau minimum dihapus) → resolved (stock_alert.resolved)
+//
+// Mengembalikan nil jika tidak ada yang perlu disimpan.
+func nextStockAlert(active *models.PeringatanStok, stock *models.StokInventori, now time.Time) (*models.PeringatanStok, string) {
+	minimum := stock.Produk.StokMinimum
+	low := minimum > 0 && stock.Jumlah <= minimum
+
+	switch {
+	case active == nil && low:
+		return &models.PeringatanStok{
+			IDProduk:         stock.IDProduk,
+			IDGudang:         stock.IDGudang,
+			Status:           "open",
+			StokMinimum:      minimum,
+			JumlahSaatDibuka: stock.Jumlah,
+			JumlahTerakhir:   stock.Jumlah,
+			DibukaPada:       now,
+			DibuatPada:       now,
+			DiperbaruiPada:   now,
+		}, events.TypeStockLow
+	case active == nil:
+		return nil, ""
+	case low:
+		active.StokMinimum = minimum
+		active.JumlahTerakhir = stock.Jumlah
+		active.DiperbaruiPada = now
+		return active, ""
+	default:
+		active.Status = "resolved"
+		active.JumlahTerakhir = stock.Jumlah
+		active.DiselesaikanPada = &now
+		active.DiperbaruiPada = now
+		return active, events.TypeStockAlertResolved
+	}
+}
+
+// stockAlertEvent membangun event peringatan stok untuk gudang terkait dan pemegang permission konfirmasi
+func stockAlertEvent(eventType string, alert *models.PeringatanStok, product *models.Produk) events.Event {
+	return events.New(eventType, events.StockAlert{
+		AlertID:     alert.ID,
+		Status:      alert.Status,
+		ProductID:   alert.IDProduk,
+		ProductSKU:  product.SKU,
+		ProductName: product.Nama,
+		WarehouseID: alert.IDGudang,
+		Quantity:    alert.JumlahTerakhir,
+		Minimum:     alert.StokMinimum,
+	}, websocket.TopicWarehouse(alert.IDGudang), websocket.TopicPermission(middleware.PermStockAlertAck))
+}
+
+func mapStockAlertToResponse(a *models.PeringatanStok) *dto.StockAlertResponse {
+	resp := &dto.StockAlertResponse{
+		ID:               a.ID,
+		ProductID:        a.IDProduk,
+		ProductSKU:       a.Produk.SKU,
+		ProductName:      a.Produk.Nama,
+		WarehouseID:      a.IDGudang,
+		WarehouseName:    a.Gudang.Nama,
+		Status:           a.Status,
+		Minimum:          a.StokMinimum,
+		QuantityAtOpen:   a.JumlahSaatDibuka,
+		CurrentQuantity:  a.JumlahTerakhir,
+		OpenedAt:         a.DibukaPada,
+		AcknowledgedAt:   a.DikonfirmasiPada,
+		AcknowledgeNotes: a.CatatanKonfirmasi,
+		ResolvedAt:       a.DiselesaikanPada,
+	}
+	if a.DikonfirmasiOlehPengguna != nil {
+		resp.AcknowledgedBy = a.DikonfirmasiOlehPengguna.Nama
+	}
+	return resp
+}
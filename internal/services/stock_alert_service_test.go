@@ -0,0 +1,45 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/models"
+	"testing"
+	"time"
+)
+
+func TestNextStockAlert(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	stock := func(qty, minimum int) *models.StokInventori {
+		return &models.StokInventori{IDProduk: 7, IDGudang: 3, Jumlah: qty, Produk: models.Produk{StokMinimum: minimum}}
+	}
+
+	// Saldo turun sampai minimum → peringatan baru
+	opened, eventType := nextStockAlert(nil, stock(5, 5), now)
+	if opened == nil || eventType != events.TypeStockLow {
+		t.Fatalf("Expected new alert with stock.low, got %+v %q", opened, eventType)
+	}
+	if opened.Status != "open" || opened.JumlahSaatDibuka != 5 || opened.StokMinimum != 5 {
+		t.Errorf("Unexpected opened alert: %+v", opened)
+	}
+
+	// Di atas minimum, atau produk tanpa minimum → tidak ada peringatan
+	if alert, _ := nextStockAlert(nil, stock(6, 5), now); alert != nil {
+		t.Errorf("Expected no alert above minimum, got %+v", alert)
+	}
+	if alert, _ := nextStockAlert(nil, stock(0, 0), now); alert != nil {
+		t.Errorf("Expected no alert without minimum, got %+v", alert)
+	}
+
+	// Masih di bawah minimum → hanya jumlah terakhir yang diperbarui, status konfirmasi dipertahankan
+	active := &models.PeringatanStok{ID: 1, Status: "acknowledged", JumlahSaatDibuka: 5, JumlahTerakhir: 5}
+	updated, eventType := nextStockAlert(active, stock(2, 5), now)
+	if updated == nil || eventType != "" || updated.Status != "acknowledged" || updated.JumlahTerakhir != 2 {
+		t.Errorf("Expected silent update, got %+v %q", updated, eventType)
+	}
+
+	// Kembali di atas minimum → resolved
+	resolved, eventType := nextStockAlert(active, stock(12, 5), now)
+	if resolved == nil || eventType != events.TypeStockAlertResolved || resolved.Status != "resolved" || resolved.DiselesaikanPada == nil {
+		t.Errorf("Expected resolved alert, got %+v %q", resolved, eventType)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
 	"real-erp-mebel/be/internal/repositories"
+	"real-erp-mebel/be/internal/websocket"
 	"strconv"
 	"strings"
 	"time"
@@ -18,15 +21,21 @@ type StockService interface {
 	GetStockHistory(warehouseID, productID uint, refType string, limit, page int) ([]dto.StockMovementResponse, int64, error)
 	GetStockBatches(productID, warehouseID uint, limit, page int) ([]dto.BatchResponse, int64, error)
 
-	CreateStockIn(userID uint, req dto.CreateStockInRequest) error
-	CreateStockOut(userID uint, req dto.CreateStockOutRequest) error
+	CreateStockIn(userID uint, req dto.CreateStockInRequest) (*dto.StockInResponse, error)
+	GetStockInByID(id uint) (*dto.StockInResponse, error)
+	ListStockIn(req *dto.ListStockInRequest) ([]dto.StockInResponse, int64, error)
+	ApproveStockIn(id, userID uint) error
+	RejectStockIn(id, userID uint, reason string) error
+	CreateStockOut(userID uint, req dto.CreateStockOutRequest) (*dto.StockOutResponse, error)
+	GetStockOutByID(id uint) (*dto.StockOutResponse, error)
 	CreateStockOpname(userID uint, req dto.CreateStockOpnameRequest) error
-	CreateStockTransfer(userID uint, req dto.CreateStockTransferRequest) error
 }
 
 type stockService struct {
-	repo      repositories.StockRepository
-	batchRepo repositories.StockBatchRepository
+	repo       repositories.StockRepository
+	batchRepo  repositories.StockBatchRepository
+	hutangRepo repositories.HutangRepository
+	publisher  events.Publisher
 }
 
 func parseOpnameQtyFromNote(note string) (int, bool) {
@@ -50,10 +59,12 @@ func parseOpnameQtyFromNote(note string) (int, bool) {
 	return v, true
 }
 
-func NewStockService(repo repositories.StockRepository, batchRepo repositories.StockBatchRepository) StockService {
+func NewStockService(repo repositories.StockRepository, batchRepo repositories.StockBatchRepository, hutangRepo repositories.HutangRepository, publisher events.Publisher) StockService {
 	return &stockService{
-		repo:      repo,
-		batchRepo: batchRepo,
+		repo:       repo,
+		batchRepo:  batchRepo,
+		hutangRepo: hutangRepo,
+		publisher:  publisher,
 	}
 }
 
@@ -194,7 +205,9 @@ func (s *stockService) GetStockBatches(productID, warehouseID uint, limit, page
 	return responses, total, nil
 }
 
-func (s *stockService) CreateStockIn(userID uint, req dto.CreateStockInRequest) (err error) {
+// CreateStockIn mencatat dokumen barang masuk berstatus pending.
+// Batch, stok_inventori dan pergerakan stok baru dibuat saat ApproveStockIn.
+func (s *stockService) CreateStockIn(userID uint, req dto.CreateStockInRequest) (result *dto.StockInResponse, err error) {
 	tx := s.repo.BeginTx()
 	defer func() {
 		if r := recover(); r != nil {
@@ -203,49 +216,140 @@ func (s *stockService) CreateStockIn(userID uint, req dto.CreateStockInRequest)
 		}
 	}()
 
-	// 1. Create Header BarangMasuk
 	now := time.Now()
 	if !req.Date.IsZero() {
 		now = req.Date
 	}
 
+	if req.SupplierID != nil {
+		if err := tx.Select("id").First(&models.Pemasok{}, *req.SupplierID).Error; err != nil {
+			tx.Rollback()
+			return nil, errors.New("supplier not found")
+		}
+	}
+
+	var items []models.ItemBarangMasuk
+	for _, item := range req.Items {
+		// Harga dari request, atau snapshot harga modal produk sebagai HPP batch saat nanti di-approve
+		var p models.Produk
+		hargaModal := 0.0
+		if err := tx.Select("harga_modal").First(&p, item.ProductID).Error; err == nil {
+			hargaModal = p.HargaModal
+		}
+
+		if item.UnitPrice != nil {
+			hargaModal = *item.UnitPrice
+		}
+
+		items = append(items, models.ItemBarangMasuk{
+			IDProduk:       item.ProductID,
+			Jumlah:         item.Quantity,
+			HargaSatuan:    hargaModal,
+			IDGudang:       req.WarehouseID,
+			Lokasi:         item.Location,
+			DibuatPada:     now,
+			DiperbaruiPada: now,
+		})
+	}
+
 	header := models.BarangMasuk{
 		NomorTransaksi: fmt.Sprintf("IN/MANUAL/%d/%d", now.Unix(), userID), // Simple logic
+		IDPemasok:      req.SupplierID,
 		DiterimaOleh:   userID,
 		DiterimaPada:   now,
-		Status:         "approved", // Direct approved for manual stock in
+		Status:         "pending", // Menunggu approval owner
 		Keterangan:     req.Notes,
 		DibuatPada:     now,
 		DiperbaruiPada: now,
+		Items:          items,
 	}
 
 	if err := s.repo.CreateStockIn(tx, &header); err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
-	// 2. Process Items - Buat Batch untuk setiap item (FIFO)
-	for _, item := range req.Items {
-		// Ambil harga modal produk untuk HPP batch ini
-		var p models.Produk
-		hargaModal := 0.0
-		if err := tx.Select("harga_modal").First(&p, item.ProductID).Error; err == nil {
-			hargaModal = p.HargaModal
+	// Approver (owner) perlu tahu ada barang masuk yang menunggu persetujuan
+	if err := s.publisher.Publish(tx, events.New(events.TypeStockInPendingApproval, events.StockInPending{
+		ID:                header.ID,
+		TransactionNumber: header.NomorTransaksi,
+		WarehouseID:       req.WarehouseID,
+		SupplierID:        header.IDPemasok,
+		ItemCount:         len(header.Items),
+	}, websocket.TopicPermission(middleware.PermStockInApprove), websocket.TopicWarehouse(req.WarehouseID))); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record events: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.GetStockInByID(header.ID)
+}
+
+func (s *stockService) GetStockInByID(id uint) (*dto.StockInResponse, error) {
+	header, err := s.repo.FindStockInByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stock in not found")
 		}
+		return nil, err
+	}
+	return mapStockInToResponse(header), nil
+}
 
+func (s *stockService) ListStockIn(req *dto.ListStockInRequest) ([]dto.StockInResponse, int64, error) {
+	headers, total, err := s.repo.FindAllStockIn(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var responses []dto.StockInResponse
+	for _, h := range headers {
+		responses = append(responses, *mapStockInToResponse(&h))
+	}
+	return responses, total, nil
+}
+
+// ApproveStockIn membukukan dokumen barang masuk pending: batch FIFO baru per item,
+// stok_inventori bertambah, pergerakan stok dicatat, dan hutang supplier dibuat jika ada pemasok.
+func (s *stockService) ApproveStockIn(id, userID uint) (err error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			err = fmt.Errorf("panic occurred: %v", r)
+		}
+	}()
+
+	header, err := s.repo.FindStockInByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("stock in not found")
+		}
+		return err
+	}
+	if header.Status != "pending" {
+		tx.Rollback()
+		return fmt.Errorf("stock in is already '%s', only pending stock in can be approved", header.Status)
+	}
+
+	now := time.Now()
+	var stockChanges []stockChange
+	for _, item := range header.Items {
 		// Buat batch baru untuk barang masuk ini
 		batch := models.StokBatch{
-			IDProduk:      item.ProductID,
-			IDGudang:      req.WarehouseID,
-			TanggalMasuk:  now,
-			JumlahAwal:    item.Quantity,
-			JumlahSaatIni: item.Quantity,
-			HargaModal:    hargaModal,
-			IDReferensi:   &header.ID,
-			TipeReferensi: "stock_in",
-			Aktif:         true,
-			Keterangan:    req.Notes,
-			DibuatPada:    now,
+			IDProduk:       item.IDProduk,
+			IDGudang:       item.IDGudang,
+			TanggalMasuk:   header.DiterimaPada,
+			JumlahAwal:     item.Jumlah,
+			JumlahSaatIni:  item.Jumlah,
+			HargaModal:     item.HargaSatuan,
+			IDReferensi:    &header.ID,
+			TipeReferensi:  "stock_in",
+			Aktif:          true,
+			Keterangan:     header.Keterangan,
+			DibuatPada:     now,
 			DiperbaruiPada: now,
 		}
 		if err := s.batchRepo.Create(tx, &batch); err != nil {
@@ -254,22 +358,23 @@ func (s *stockService) CreateStockIn(userID uint, req dto.CreateStockInRequest)
 		}
 
 		// Update Stock Balance (Totalan)
-		if err := s.repo.UpdateStockBalance(tx, item.ProductID, req.WarehouseID, item.Quantity); err != nil {
+		if err := s.repo.UpdateStockBalance(tx, item.IDProduk, item.IDGudang, item.Jumlah); err != nil {
 			tx.Rollback()
 			return fmt.Errorf("failed to update stock balance: %w", err)
 		}
+		stockChanges = append(stockChanges, stockChange{ProductID: item.IDProduk, WarehouseID: item.IDGudang, Delta: item.Jumlah})
 
 		// Create Movement Log dengan link ke batch
 		movement := models.PergerakanStok{
-			IDProduk:       item.ProductID,
-			IDGudang:       req.WarehouseID,
-			IDBatch:        &batch.ID, // Link ke batch yang baru dibuat
+			IDProduk:       item.IDProduk,
+			IDGudang:       item.IDGudang,
+			IDBatch:        &batch.ID,
 			TipePergerakan: "in",
 			TipeReferensi:  "manual_in",
 			IDReferensi:    &header.ID,
-			Jumlah:         item.Quantity,
+			Jumlah:         item.Jumlah,
 			IDPengguna:     userID,
-			Keterangan:     fmt.Sprintf("%s (New Batch #%d)", req.Notes, batch.ID),
+			Keterangan:     fmt.Sprintf("%s (%s approved, New Batch #%d)", header.Keterangan, header.NomorTransaksi, batch.ID),
 			DibuatPada:     now,
 		}
 		if err := s.repo.CreateStockMovement(tx, &movement); err != nil {
@@ -278,10 +383,70 @@ func (s *stockService) CreateStockIn(userID uint, req dto.CreateStockInRequest)
 		}
 	}
 
+	// Hutang supplier hanya untuk barang masuk dari pemasok
+	if header.IDPemasok != nil {
+		hutang := newHutangFromBarangMasuk(header, nil)
+		hutang.DibuatPada = now
+		hutang.DiperbaruiPada = now
+		if err := s.hutangRepo.Create(tx, hutang); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create supplier debt: %w", err)
+		}
+	}
+
+	if err := s.repo.UpdateStockInStatus(tx, id, "approved", map[string]interface{}{
+		"disetujui_oleh": userID,
+		"disetujui_pada": now,
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := publishWithStockEvents(tx, s.publisher, s.repo, "manual_in", header.ID, stockChanges); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record events: %w", err)
+	}
+
 	return tx.Commit().Error
 }
 
-func (s *stockService) CreateStockOut(userID uint, req dto.CreateStockOutRequest) (err error) {
+// RejectStockIn menolak dokumen barang masuk pending. Stok tidak berubah.
+func (s *stockService) RejectStockIn(id, userID uint, reason string) error {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	header, err := s.repo.FindStockInByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("stock in not found")
+		}
+		return err
+	}
+	if header.Status != "pending" {
+		tx.Rollback()
+		return fmt.Errorf("stock in is already '%s', only pending stock in can be rejected", header.Status)
+	}
+
+	if err := s.repo.UpdateStockInStatus(tx, id, "rejected", map[string]interface{}{
+		"alasan_penolakan": reason,
+		"ditolak_oleh":     userID,
+		"ditolak_pada":     time.Now(),
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CreateStockOut mengurangi stok manual via FIFO dan mencatat satu ItemBarangKeluar per batch yang terpakai,
+// lengkap dengan HPP batch dan link ke baris barang masuk asalnya.
+func (s *stockService) CreateStockOut(userID uint, req dto.CreateStockOutRequest) (result *dto.StockOutResponse, err error) {
 	tx := s.repo.BeginTx()
 	defer func() {
 		if r := recover(); r != nil {
@@ -305,84 +470,88 @@ func (s *stockService) CreateStockOut(userID uint, req dto.CreateStockOutRequest
 
 	if err := s.repo.CreateStockOut(tx, &header); err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
-
+	var stockChanges []stockChange
 	for _, item := range req.Items {
 		// === FIFO LOGIC: Ambil batch terlama sampai qty terpenuhi ===
-		batches, err := s.batchRepo.GetAvailableBatches(tx, item.ProductID, req.WarehouseID)
+		usages, err := deductFIFO(tx, s.batchRepo, item.ProductID, req.WarehouseID, item.Quantity)
 		if err != nil {
 			tx.Rollback()
-			return fmt.Errorf("failed to get batches: %w", err)
+			return nil, err
 		}
 
-		remainingQty := item.Quantity
-		
-		// Check total available
-		totalAvailable := 0
-		for _, b := range batches {
-			totalAvailable += b.JumlahSaatIni
-		}
-		if totalAvailable < item.Quantity {
-			tx.Rollback()
-			return fmt.Errorf("insufficient stock for product %d (need %d, available %d)", 
-				item.ProductID, item.Quantity, totalAvailable)
-		}
+		for _, usage := range usages {
+			batchID := usage.Batch.ID
 
-		// Deduct dari batch terlama (FIFO)
-		for i := range batches {
-			if remainingQty == 0 {
-				break
+			line := models.ItemBarangKeluar{
+				IDBarangKeluar: header.ID,
+				IDProduk:       item.ProductID,
+				Jumlah:         usage.Jumlah,
+				IDGudang:       req.WarehouseID,
+				IDBatch:        &batchID,
+				HargaModal:     usage.Batch.HargaModal,
+				DibuatPada:     now,
+				DiperbaruiPada: now,
 			}
-
-			batch := &batches[i]
-			deductQty := 0
-			
-			if batch.JumlahSaatIni >= remainingQty {
-				// Batch ini cukup
-				deductQty = remainingQty
-				remainingQty = 0
-			} else {
-				// Batch habis, ambil semua
-				deductQty = batch.JumlahSaatIni
-				remainingQty -= batch.JumlahSaatIni
+			if usage.Batch.TipeReferensi == "stock_in" && usage.Batch.IDReferensi != nil {
+				if src, err := s.repo.FindStockInItemForBatch(tx, *usage.Batch.IDReferensi, item.ProductID); err == nil {
+					line.IDItemBarangMasuk = &src.ID
+				}
 			}
-
-			// Update batch
-			batch.JumlahSaatIni -= deductQty
-			if err := s.batchRepo.Update(tx, batch); err != nil {
+			if err := s.repo.CreateStockOutItem(tx, &line); err != nil {
 				tx.Rollback()
-				return fmt.Errorf("failed to update batch: %w", err)
+				return nil, fmt.Errorf("failed to create stock out item: %w", err)
 			}
 
 			// Log pergerakan dengan link ke batch
 			movement := models.PergerakanStok{
 				IDProduk:       item.ProductID,
 				IDGudang:       req.WarehouseID,
-				IDBatch:        &batch.ID, // Link ke batch FIFO
+				IDBatch:        &batchID, // Link ke batch FIFO
 				TipePergerakan: "out",
 				TipeReferensi:  "manual_out",
 				IDReferensi:    &header.ID,
-				Jumlah:         -deductQty,
+				Jumlah:         -usage.Jumlah,
 				IDPengguna:     userID,
-				Keterangan:     fmt.Sprintf("%s (Batch #%d, HPP: %.2f)", req.Reason, batch.ID, batch.HargaModal),
+				Keterangan:     fmt.Sprintf("%s (Batch #%d, HPP: %.2f)", req.Reason, batchID, usage.Batch.HargaModal),
 				DibuatPada:     now,
 			}
 			if err := s.repo.CreateStockMovement(tx, &movement); err != nil {
 				tx.Rollback()
-				return fmt.Errorf("failed to create movement: %w", err)
+				return nil, fmt.Errorf("failed to create movement: %w", err)
 			}
 		}
 
 		// Update StokInventori (totalan)
 		if err := s.repo.UpdateStockBalance(tx, item.ProductID, req.WarehouseID, -item.Quantity); err != nil {
 			tx.Rollback()
-			return fmt.Errorf("failed to update stock balance: %w", err)
+			return nil, fmt.Errorf("failed to update stock balance: %w", err)
 		}
+		stockChanges = append(stockChanges, stockChange{ProductID: item.ProductID, WarehouseID: req.WarehouseID, Delta: -item.Quantity})
 	}
 
-	return tx.Commit().Error
+	if err := publishWithStockEvents(tx, s.publisher, s.repo, "manual_out", header.ID, stockChanges); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record events: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.GetStockOutByID(header.ID)
+}
+
+func (s *stockService) GetStockOutByID(id uint) (*dto.StockOutResponse, error) {
+	header, err := s.repo.FindStockOutByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stock out not found")
+		}
+		return nil, err
+	}
+	return mapStockOutToResponse(header), nil
 }
 
 func (s *stockService) CreateStockOpname(userID uint, req dto.CreateStockOpnameRequest) (err error) {
@@ -399,6 +568,7 @@ func (s *stockService) CreateStockOpname(userID uint, req dto.CreateStockOpnameR
 		now = req.Date
 	}
 
+	var stockChanges []stockChange
 	for _, item := range req.Items {
 		var diff int
 		var systemQty int
@@ -435,6 +605,7 @@ func (s *stockService) CreateStockOpname(userID uint, req dto.CreateStockOpnameR
 				tx.Rollback()
 				return err
 			}
+			stockChanges = append(stockChanges, stockChange{ProductID: item.ProductID, WarehouseID: req.WarehouseID, Delta: diff})
 
 			// Record Movement
 			movement := models.PergerakanStok{
@@ -489,6 +660,7 @@ func (s *stockService) CreateStockOpname(userID uint, req dto.CreateStockOpnameR
 				tx.Rollback()
 				return err
 			}
+			stockChanges = append(stockChanges, stockChange{ProductID: item.ProductID, WarehouseID: req.WarehouseID, Delta: diff})
 
 			movement := models.PergerakanStok{
 				IDProduk:       item.ProductID,
@@ -508,88 +680,91 @@ func (s *stockService) CreateStockOpname(userID uint, req dto.CreateStockOpnameR
 		}
 	}
 
+	if err := publishWithStockEvents(tx, s.publisher, s.repo, "opname", 0, stockChanges); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record events: %w", err)
+	}
+
 	return tx.Commit().Error
 }
 
-func (s *stockService) CreateStockTransfer(userID uint, req dto.CreateStockTransferRequest) (err error) {
-	tx := s.repo.BeginTx()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			err = fmt.Errorf("panic occurred: %v", r)
-		}
-	}()
-
-	now := time.Now()
-	if req.Date != nil && !req.Date.IsZero() {
-		now = *req.Date
+func mapStockInToResponse(h *models.BarangMasuk) *dto.StockInResponse {
+	var items []dto.StockInItemResponse
+	var total float64
+	for _, item := range h.Items {
+		subtotal := float64(item.Jumlah) * item.HargaSatuan
+		total += subtotal
+		items = append(items, dto.StockInItemResponse{
+			ID:            item.ID,
+			ProductID:     item.IDProduk,
+			ProductSKU:    item.Produk.SKU,
+			ProductName:   item.Produk.Nama,
+			WarehouseID:   item.IDGudang,
+			WarehouseName: item.Gudang.Nama,
+			Quantity:      item.Jumlah,
+			UnitPrice:     item.HargaSatuan,
+			Subtotal:      subtotal,
+			Location:      item.Lokasi,
+		})
 	}
 
-	transferID := fmt.Sprintf("TRF/%d/%d/%d", req.SourceWarehouseID, req.TargetWarehouseID, now.Unix())
-
-	for _, item := range req.Items {
-		// 1. Check Source Stock
-		sourceStock, err := s.repo.GetStockByProductAndWarehouse(item.ProductID, req.SourceWarehouseID)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			tx.Rollback()
-			return err
-		}
-		if sourceStock == nil || sourceStock.Jumlah < item.Quantity {
-			tx.Rollback()
-			return fmt.Errorf("insufficient stock for product %d in source warehouse", item.ProductID)
-		}
-
-		// 2. Decrement Source Stock
-		if err := s.repo.UpdateStockBalance(tx, item.ProductID, req.SourceWarehouseID, -item.Quantity); err != nil {
-			tx.Rollback()
-			return err
-		}
-
-		// 3. Log Source Movement (Transfer Out)
-		outMovement := models.PergerakanStok{
-			IDProduk:       item.ProductID,
-			IDGudang:       req.SourceWarehouseID,
-			TipePergerakan: "transfer_out",
-			TipeReferensi:  "transfer",
-			Keterangan:     fmt.Sprintf("Transfer to Warehouse %d. %s", req.TargetWarehouseID, req.Notes), // Store Target ID in notes or Reference?
-			IDPengguna:     userID,
-			Jumlah:         -item.Quantity,
-			DibuatPada:     now,
-			// Kita bisa simpan Transfer ID di Keterangan atau field lain jika ada
-			// Untuk sekarang string formatting di Keterangan cukup
-		}
-		// Hack to store Transfer Ref. actually Request doesn't map to a table.
-		// We use Keterangan to store Transfer ID for tracing.
-		outMovement.Keterangan += fmt.Sprintf(" (Ref: %s)", transferID)
-
-		if err := s.repo.CreateStockMovement(tx, &outMovement); err != nil {
-			tx.Rollback()
-			return err
-		}
-
-		// 4. Increment Target Stock
-		if err := s.repo.UpdateStockBalance(tx, item.ProductID, req.TargetWarehouseID, item.Quantity); err != nil {
-			tx.Rollback()
-			return err
-		}
-
-		// 5. Log Target Movement (Transfer In)
-		inMovement := models.PergerakanStok{
-			IDProduk:       item.ProductID,
-			IDGudang:       req.TargetWarehouseID,
-			TipePergerakan: "transfer_in",
-			TipeReferensi:  "transfer",
-			Keterangan:     fmt.Sprintf("Transfer from Warehouse %d. %s (Ref: %s)", req.SourceWarehouseID, req.Notes, transferID),
-			IDPengguna:     userID,
-			Jumlah:         item.Quantity,
-			DibuatPada:     now,
-		}
-
-		if err := s.repo.CreateStockMovement(tx, &inMovement); err != nil {
-			tx.Rollback()
-			return err
-		}
+	resp := &dto.StockInResponse{
+		ID:                h.ID,
+		TransactionNumber: h.NomorTransaksi,
+		SupplierID:        h.IDPemasok,
+		POID:              h.IDPO,
+		Status:            h.Status,
+		Notes:             h.Keterangan,
+		ReceivedBy:        h.DiterimaOlehPengguna.Nama,
+		ReceivedAt:        h.DiterimaPada,
+		ApprovedAt:        h.DisetujuiPada,
+		RejectedAt:        h.DitolakPada,
+		RejectionReason:   h.AlasanPenolakan,
+		TotalValue:        total,
+		CreatedAt:         h.DibuatPada,
+		Items:             items,
+	}
+	if h.Pemasok != nil {
+		resp.SupplierName = h.Pemasok.Nama
+	}
+	if h.DisetujuiOlehPengguna != nil {
+		resp.ApprovedBy = h.DisetujuiOlehPengguna.Nama
+	}
+	if h.DitolakOlehPengguna != nil {
+		resp.RejectedBy = h.DitolakOlehPengguna.Nama
 	}
+	return resp
+}
 
-	return tx.Commit().Error
+func mapStockOutToResponse(h *models.BarangKeluar) *dto.StockOutResponse {
+	var items []dto.StockOutItemResponse
+	var total float64
+	for _, item := range h.Items {
+		subtotal := float64(item.Jumlah) * item.HargaModal
+		total += subtotal
+		items = append(items, dto.StockOutItemResponse{
+			ID:            item.ID,
+			ProductID:     item.IDProduk,
+			ProductSKU:    item.Produk.SKU,
+			ProductName:   item.Produk.Nama,
+			WarehouseID:   item.IDGudang,
+			WarehouseName: item.Gudang.Nama,
+			Quantity:      item.Jumlah,
+			BatchID:       item.IDBatch,
+			StockInItemID: item.IDItemBarangMasuk,
+			CostPrice:     item.HargaModal,
+			Subtotal:      subtotal,
+		})
+	}
+	return &dto.StockOutResponse{
+		ID:                h.ID,
+		TransactionNumber: h.NomorTransaksi,
+		Reason:            h.Alasan,
+		ReferenceType:     h.TipeReferensi,
+		ReferenceID:       h.IDReferensi,
+		CreatedBy:         h.DibuatOlehPengguna.Nama,
+		TotalCost:         total,
+		CreatedAt:         h.DibuatPada,
+		Items:             items,
+	}
 }
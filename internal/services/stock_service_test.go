@@ -0,0 +1,58 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"testing"
+)
+
+func TestMapStockInToResponse(t *testing.T) {
+	supplierID := uint(3)
+	header := &models.BarangMasuk{
+		ID:             8,
+		NomorTransaksi: "IN/MANUAL/1730534400/2",
+		IDPemasok:      &supplierID,
+		Pemasok:        &models.Pemasok{Nama: "CV Jati Makmur"},
+		Status:         "pending",
+		Items: []models.ItemBarangMasuk{
+			{IDProduk: 4, Jumlah: 3, HargaSatuan: 875000},
+			{IDProduk: 5, Jumlah: 2, HargaSatuan: 1250000},
+		},
+	}
+
+	resp := mapStockInToResponse(header)
+
+	if resp.SupplierName != "CV Jati Makmur" {
+		t.Errorf("Expected supplier name, got %q", resp.SupplierName)
+	}
+	if resp.TotalValue != 5125000 {
+		t.Errorf("Expected total value 5125000, got %.2f", resp.TotalValue)
+	}
+	if len(resp.Items) != 2 || resp.Items[1].Subtotal != 2500000 {
+		t.Errorf("Unexpected items: %+v", resp.Items)
+	}
+	if resp.ApprovedBy != "" || resp.ApprovedAt != nil {
+		t.Errorf("Expected pending stock in without approver, got %q", resp.ApprovedBy)
+	}
+}
+
+func TestMapStockOutToResponse(t *testing.T) {
+	batchA, batchB := uint(12), uint(15)
+	header := &models.BarangKeluar{
+		ID:             9,
+		NomorTransaksi: "OUT/MANUAL/1730534400/2",
+		Alasan:         "rusak",
+		Items: []models.ItemBarangKeluar{
+			{IDProduk: 4, Jumlah: 2, IDBatch: &batchA, HargaModal: 875000},
+			{IDProduk: 4, Jumlah: 1, IDBatch: &batchB, HargaModal: 900000},
+		},
+	}
+
+	resp := mapStockOutToResponse(header)
+
+	if resp.TotalCost != 2650000 {
+		t.Errorf("Expected total cost 2650000, got %.2f", resp.TotalCost)
+	}
+	if len(resp.Items) != 2 || *resp.Items[1].BatchID != 15 || resp.Items[1].Subtotal != 900000 {
+		t.Errorf("Unexpected FIFO lines: %+v", resp.Items)
+	}
+}
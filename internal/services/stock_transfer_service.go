@@ -0,0 +1,457 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type StockTransferService interface {
+	ListTransfers(req *dto.ListStockTransferRequest) ([]dto.StockTransferResponse, int64, error)
+	GetTransferByID(id uint) (*dto.StockTransferResponse, error)
+
+	// Dokumen transfer dua langkah: draft → shipped (in-transit) → received / cancelled
+	CreateTransfer(userID uint, req dto.CreateStockTransferRequest) (*dto.StockTransferResponse, error)
+	ShipTransfer(id, userID uint) error
+	ReceiveTransfer(id, userID uint) error
+	CancelTransfer(id, userID uint) error
+
+	// Transfer langsung (kirim + terima sekaligus), dipakai oleh POST /stocks/transfer
+	CreateDirectTransfer(userID uint, req dto.CreateStockTransferRequest) (*dto.StockTransferResponse, error)
+}
+
+type stockTransferService struct {
+	repo      repositories.StockTransferRepository
+	stockRepo repositories.StockRepository
+	batchRepo repositories.StockBatchRepository
+	publisher events.Publisher
+}
+
+func NewStockTransferService(
+	repo repositories.StockTransferRepository,
+	stockRepo repositories.StockRepository,
+	batchRepo repositories.StockBatchRepository,
+	publisher events.Publisher,
+) StockTransferService {
+	return &stockTransferService{
+		repo:      repo,
+		stockRepo: stockRepo,
+		batchRepo: batchRepo,
+		publisher: publisher,
+	}
+}
+
+func (s *stockTransferService) ListTransfers(req *dto.ListStockTransferRequest) ([]dto.StockTransferResponse, int64, error) {
+	transfers, total, err := s.repo.FindAll(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var responses []dto.StockTransferResponse
+	for _, t := range transfers {
+		responses = append(responses, *mapStockTransferToResponse(&t))
+	}
+	return responses, total, nil
+}
+
+func (s *stockTransferService) GetTransferByID(id uint) (*dto.StockTransferResponse, error) {
+	transfer, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transfer stok tidak ditemukan")
+		}
+		return nil, err
+	}
+	return mapStockTransferToResponse(transfer), nil
+}
+
+// CreateTransfer membuat dokumen transfer berstatus draft. Stok belum bergerak.
+func (s *stockTransferService) CreateTransfer(userID uint, req dto.CreateStockTransferRequest) (*dto.StockTransferResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := s.createDraft(tx, userID, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.GetTransferByID(transfer.ID)
+}
+
+// ShipTransfer mengirim transfer (draft → shipped): stok dikurangi FIFO dari gudang asal
+// dan tercatat sebagai in-transit sampai diterima di gudang tujuan.
+func (s *stockTransferService) ShipTransfer(id, userID uint) error {
+	return s.withLockedTransfer(id, func(tx *gorm.DB, transfer *models.TransferStok) error {
+		if transfer.Status != "draft" {
+			return fmt.Errorf("transfer dalam status '%s', hanya draft yang dapat dikirim", transfer.Status)
+		}
+		changes, err := s.ship(tx, transfer, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.publishStockEvents(tx, transfer, changes)
+	})
+}
+
+// ReceiveTransfer menerima transfer di gudang tujuan (shipped → received):
+// setiap batch in-transit menjadi batch baru dengan TanggalMasuk & HargaModal batch asal.
+func (s *stockTransferService) ReceiveTransfer(id, userID uint) error {
+	return s.withLockedTransfer(id, func(tx *gorm.DB, transfer *models.TransferStok) error {
+		if transfer.Status != "shipped" {
+			return fmt.Errorf("transfer dalam status '%s', hanya transfer shipped yang dapat diterima", transfer.Status)
+		}
+		changes, err := s.receive(tx, transfer, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.publishStockEvents(tx, transfer, changes)
+	})
+}
+
+// CancelTransfer membatalkan transfer. Jika sudah shipped, stok in-transit dikembalikan
+// ke batch asalnya di gudang asal.
+func (s *stockTransferService) CancelTransfer(id, userID uint) error {
+	return s.withLockedTransfer(id, func(tx *gorm.DB, transfer *models.TransferStok) error {
+		now := time.Now()
+		var changes []stockChange
+		switch transfer.Status {
+		case "draft":
+		case "shipped":
+			restored, err := s.restoreInTransit(tx, transfer, userID, now)
+			if err != nil {
+				return err
+			}
+			changes = restored
+		default:
+			return fmt.Errorf("transfer dalam status '%s', tidak dapat dibatalkan", transfer.Status)
+		}
+		if err := s.repo.UpdateStatus(tx, transfer.ID, "cancelled", map[string]interface{}{
+			"dibatalkan_oleh": userID,
+			"dibatalkan_pada": now,
+		}); err != nil {
+			return err
+		}
+		return s.publishStockEvents(tx, transfer, changes)
+	})
+}
+
+// CreateDirectTransfer membuat dokumen transfer lalu langsung mengirim & menerimanya dalam satu TX.
+func (s *stockTransferService) CreateDirectTransfer(userID uint, req dto.CreateStockTransferRequest) (*dto.StockTransferResponse, error) {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := s.createDraft(tx, userID, req)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Kirim & terima pada tanggal dokumen (req.Date jika diisi)
+	now := transfer.DibuatPada
+	shipped, err := s.ship(tx, transfer, userID, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	received, err := s.receive(tx, transfer, userID, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.publishStockEvents(tx, transfer, append(shipped, received...)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return s.GetTransferByID(transfer.ID)
+}
+
+// ===========================
+// INTERNAL HELPERS
+// ===========================
+
+// publishStockEvents mencatat event stok (dan evaluasi peringatan stok) untuk perubahan saldo transfer
+func (s *stockTransferService) publishStockEvents(tx *gorm.DB, transfer *models.TransferStok, changes []stockChange) error {
+	if err := publishWithStockEvents(tx, s.publisher, s.stockRepo, "transfer", transfer.ID, changes); err != nil {
+		return fmt.Errorf("gagal mencatat event: %w", err)
+	}
+	return nil
+}
+
+func (s *stockTransferService) withLockedTransfer(id uint, fn func(tx *gorm.DB, transfer *models.TransferStok) error) error {
+	tx := s.repo.BeginTx()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transfer, err := s.repo.FindByIDForUpdate(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("transfer stok tidak ditemukan")
+		}
+		return err
+	}
+
+	if err := fn(tx, transfer); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (s *stockTransferService) createDraft(tx *gorm.DB, userID uint, req dto.CreateStockTransferRequest) (*models.TransferStok, error) {
+	if req.SourceWarehouseID == req.TargetWarehouseID {
+		return nil, errors.New("gudang asal dan gudang tujuan tidak boleh sama")
+	}
+
+	// Nomor dokumen dari jam sistem (unik); req.Date hanya menjadi tanggal dokumen & pergerakan stok
+	createdAt := time.Now()
+	now := createdAt
+	if req.Date != nil && !req.Date.IsZero() {
+		now = *req.Date
+	}
+	var items []models.ItemTransferStok
+	for _, item := range req.Items {
+		items = append(items, models.ItemTransferStok{
+			IDProduk:   item.ProductID,
+			Jumlah:     item.Quantity,
+			DibuatPada: now,
+		})
+	}
+
+	transfer := models.TransferStok{
+		NomorTransfer:  fmt.Sprintf("TRF/%s/%d", createdAt.Format("20060102150405"), userID),
+		IDGudangAsal:   req.SourceWarehouseID,
+		IDGudangTujuan: req.TargetWarehouseID,
+		Status:         "draft",
+		Keterangan:     req.Notes,
+		DibuatOleh:     userID,
+		DibuatPada:     now,
+		DiperbaruiPada: now,
+		Items:          items,
+	}
+	if err := s.repo.Create(tx, &transfer); err != nil {
+		return nil, fmt.Errorf("gagal membuat dokumen transfer: %w", err)
+	}
+	return &transfer, nil
+}
+
+// ship mengurangi batch gudang asal (FIFO) dan mencatat breakdown batch in-transit
+func (s *stockTransferService) ship(tx *gorm.DB, transfer *models.TransferStok, userID uint, now time.Time) ([]stockChange, error) {
+	var changes []stockChange
+	for i := range transfer.Items {
+		item := &transfer.Items[i]
+
+		usages, err := deductFIFO(tx, s.batchRepo, item.IDProduk, transfer.IDGudangAsal, item.Jumlah)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Batches = nil
+		for _, usage := range usages {
+			sourceBatchID := usage.Batch.ID
+
+			if err := s.stockRepo.UpdateStockBalance(tx, item.IDProduk, transfer.IDGudangAsal, -usage.Jumlah); err != nil {
+				return nil, fmt.Errorf("gagal update stok gudang asal: %w", err)
+			}
+			changes = append(changes, stockChange{ProductID: item.IDProduk, WarehouseID: transfer.IDGudangAsal, Delta: -usage.Jumlah})
+			movement := models.PergerakanStok{
+				IDProduk:       item.IDProduk,
+				IDGudang:       transfer.IDGudangAsal,
+				IDBatch:        &sourceBatchID,
+				TipePergerakan: "transfer_out",
+				TipeReferensi:  "transfer",
+				IDReferensi:    &transfer.ID,
+				Jumlah:         -usage.Jumlah,
+				IDPengguna:     userID,
+				Keterangan:     fmt.Sprintf("Transfer %s ke gudang #%d (Batch #%d, in-transit)", transfer.NomorTransfer, transfer.IDGudangTujuan, sourceBatchID),
+				DibuatPada:     now,
+			}
+			if err := s.stockRepo.CreateStockMovement(tx, &movement); err != nil {
+				return nil, fmt.Errorf("gagal log pergerakan stok: %w", err)
+			}
+
+			record := models.ItemTransferStokBatch{
+				IDItemTransfer: item.ID,
+				IDBatchAsal:    sourceBatchID,
+				Jumlah:         usage.Jumlah,
+				HargaModal:     usage.Batch.HargaModal,
+				DibuatPada:     now,
+			}
+			if err := s.repo.CreateBatchUsage(tx, &record); err != nil {
+				return nil, fmt.Errorf("gagal mencatat batch transfer: %w", err)
+			}
+			record.BatchAsal = usage.Batch
+			item.Batches = append(item.Batches, record)
+		}
+	}
+
+	transfer.Status = "shipped"
+	return changes, s.repo.UpdateStatus(tx, transfer.ID, "shipped", map[string]interface{}{
+		"dikirim_oleh": userID,
+		"dikirim_pada": now,
+	})
+}
+
+// receive membuat batch baru di gudang tujuan dari setiap batch in-transit
+func (s *stockTransferService) receive(tx *gorm.DB, transfer *models.TransferStok, userID uint, now time.Time) ([]stockChange, error) {
+	var changes []stockChange
+	for _, item := range transfer.Items {
+		for _, usage := range item.Batches {
+			targetBatch := cloneBatchForWarehouse(usage.BatchAsal, transfer.IDGudangTujuan, usage.Jumlah,
+				fmt.Sprintf("Transfer %s dari batch #%d", transfer.NomorTransfer, usage.IDBatchAsal))
+			targetBatch.IDReferensi = &transfer.ID
+			targetBatch.DibuatPada = now
+			targetBatch.DiperbaruiPada = now
+			if err := s.batchRepo.Create(tx, &targetBatch); err != nil {
+				return nil, fmt.Errorf("gagal membuat batch tujuan: %w", err)
+			}
+			if err := s.repo.SetTargetBatch(tx, usage.ID, targetBatch.ID); err != nil {
+				return nil, fmt.Errorf("gagal link batch tujuan: %w", err)
+			}
+
+			if err := s.stockRepo.UpdateStockBalance(tx, item.IDProduk, transfer.IDGudangTujuan, usage.Jumlah); err != nil {
+				return nil, fmt.Errorf("gagal update stok gudang tujuan: %w", err)
+			}
+			changes = append(changes, stockChange{ProductID: item.IDProduk, WarehouseID: transfer.IDGudangTujuan, Delta: usage.Jumlah})
+			movement := models.PergerakanStok{
+				IDProduk:       item.IDProduk,
+				IDGudang:       transfer.IDGudangTujuan,
+				IDBatch:        &targetBatch.ID,
+				TipePergerakan: "transfer_in",
+				TipeReferensi:  "transfer",
+				IDReferensi:    &transfer.ID,
+				Jumlah:         usage.Jumlah,
+				IDPengguna:     userID,
+				Keterangan:     fmt.Sprintf("Terima transfer %s dari gudang #%d (Batch #%d → #%d)", transfer.NomorTransfer, transfer.IDGudangAsal, usage.IDBatchAsal, targetBatch.ID),
+				DibuatPada:     now,
+			}
+			if err := s.stockRepo.CreateStockMovement(tx, &movement); err != nil {
+				return nil, fmt.Errorf("gagal log pergerakan stok: %w", err)
+			}
+		}
+	}
+
+	transfer.Status = "received"
+	return changes, s.repo.UpdateStatus(tx, transfer.ID, "received", map[string]interface{}{
+		"diterima_oleh": userID,
+		"diterima_pada": now,
+	})
+}
+
+// restoreInTransit mengembalikan qty in-transit ke batch asal (transfer shipped dibatalkan)
+func (s *stockTransferService) restoreInTransit(tx *gorm.DB, transfer *models.TransferStok, userID uint, now time.Time) ([]stockChange, error) {
+	var changes []stockChange
+	for _, item := range transfer.Items {
+		for _, usage := range item.Batches {
+			batch, err := s.batchRepo.FindByIDForUpdate(tx, usage.IDBatchAsal)
+			if err != nil {
+				return nil, fmt.Errorf("gagal mengambil batch #%d: %w", usage.IDBatchAsal, err)
+			}
+			batch.JumlahSaatIni += usage.Jumlah
+			batch.Aktif = true
+			if err := s.batchRepo.Update(tx, batch); err != nil {
+				return nil, fmt.Errorf("gagal mengembalikan batch #%d: %w", batch.ID, err)
+			}
+
+			if err := s.stockRepo.UpdateStockBalance(tx, item.IDProduk, transfer.IDGudangAsal, usage.Jumlah); err != nil {
+				return nil, fmt.Errorf("gagal update stok gudang asal: %w", err)
+			}
+			changes = append(changes, stockChange{ProductID: item.IDProduk, WarehouseID: transfer.IDGudangAsal, Delta: usage.Jumlah})
+			batchID := batch.ID
+			movement := models.PergerakanStok{
+				IDProduk:       item.IDProduk,
+				IDGudang:       transfer.IDGudangAsal,
+				IDBatch:        &batchID,
+				TipePergerakan: "transfer_in",
+				TipeReferensi:  "transfer_cancel",
+				IDReferensi:    &transfer.ID,
+				Jumlah:         usage.Jumlah,
+				IDPengguna:     userID,
+				Keterangan:     fmt.Sprintf("Batal transfer %s — stok in-transit kembali ke batch #%d", transfer.NomorTransfer, batchID),
+				DibuatPada:     now,
+			}
+			if err := s.stockRepo.CreateStockMovement(tx, &movement); err != nil {
+				return nil, fmt.Errorf("gagal log pergerakan stok: %w", err)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// ===========================
+// MAPPING HELPERS
+// ===========================
+
+func mapStockTransferToResponse(t *models.TransferStok) *dto.StockTransferResponse {
+	var items []dto.StockTransferItemResponse
+	for _, item := range t.Items {
+		var batches []dto.StockTransferBatchResponse
+		for _, b := range item.Batches {
+			batches = append(batches, dto.StockTransferBatchResponse{
+				SourceBatchID: b.IDBatchAsal,
+				TargetBatchID: b.IDBatchTujuan,
+				Quantity:      b.Jumlah,
+				CostPrice:     b.HargaModal,
+			})
+		}
+		items = append(items, dto.StockTransferItemResponse{
+			ID:          item.ID,
+			ProductID:   item.IDProduk,
+			ProductSKU:  item.Produk.SKU,
+			ProductName: item.Produk.Nama,
+			Quantity:    item.Jumlah,
+			Batches:     batches,
+		})
+	}
+
+	resp := &dto.StockTransferResponse{
+		ID:                  t.ID,
+		TransferNumber:      t.NomorTransfer,
+		SourceWarehouseID:   t.IDGudangAsal,
+		SourceWarehouseName: t.GudangAsal.Nama,
+		TargetWarehouseID:   t.IDGudangTujuan,
+		TargetWarehouseName: t.GudangTujuan.Nama,
+		Status:              t.Status,
+		Notes:               t.Keterangan,
+		CreatedBy:           t.DibuatOlehPengguna.Nama,
+		ShippedAt:           t.DikirimPada,
+		ReceivedAt:          t.DiterimaPada,
+		CancelledAt:         t.DibatalkanPada,
+		CreatedAt:           t.DibuatPada,
+		Items:               items,
+	}
+	if t.DikirimOlehPengguna != nil {
+		resp.ShippedBy = t.DikirimOlehPengguna.Nama
+	}
+	if t.DiterimaOlehPengguna != nil {
+		resp.ReceivedBy = t.DiterimaOlehPengguna.Nama
+	}
+	if t.DibatalkanOlehPengguna != nil {
+		resp.CancelledBy = t.DibatalkanOlehPengguna.Nama
+	}
+	return resp
+}
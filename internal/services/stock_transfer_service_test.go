@@ -0,0 +1,48 @@
+package services
+
+import (
+	"real-erp-mebel/be/internal/models"
+	"testing"
+)
+
+func TestMapStockTransferToResponse_InTransit(t *testing.T) {
+	target := uint(31)
+	transfer := &models.TransferStok{
+		ID:             7,
+		NomorTransfer:  "TRF/20241102080000/1",
+		IDGudangAsal:   1,
+		GudangAsal:     models.Gudang{Nama: "Gudang Utama"},
+		IDGudangTujuan: 2,
+		GudangTujuan:   models.Gudang{Nama: "Showroom"},
+		Status:         "shipped",
+		Items: []models.ItemTransferStok{
+			{
+				ID:       3,
+				IDProduk: 4,
+				Jumlah:   5,
+				Batches: []models.ItemTransferStokBatch{
+					{IDBatchAsal: 12, Jumlah: 3, HargaModal: 875000},
+					{IDBatchAsal: 15, IDBatchTujuan: &target, Jumlah: 2, HargaModal: 900000},
+				},
+			},
+		},
+	}
+
+	resp := mapStockTransferToResponse(transfer)
+
+	if resp.SourceWarehouseName != "Gudang Utama" || resp.TargetWarehouseName != "Showroom" {
+		t.Errorf("Unexpected warehouse names: %s → %s", resp.SourceWarehouseName, resp.TargetWarehouseName)
+	}
+	if resp.ShippedBy != "" {
+		t.Errorf("Expected empty ShippedBy when pengguna not loaded, got %q", resp.ShippedBy)
+	}
+	if len(resp.Items) != 1 || len(resp.Items[0].Batches) != 2 {
+		t.Fatalf("Expected 1 item with 2 batches, got %+v", resp.Items)
+	}
+	if resp.Items[0].Batches[0].TargetBatchID != nil {
+		t.Errorf("Expected in-transit batch without target, got %d", *resp.Items[0].Batches[0].TargetBatchID)
+	}
+	if b := resp.Items[0].Batches[1]; b.TargetBatchID == nil || *b.TargetBatchID != 31 || b.CostPrice != 900000 {
+		t.Errorf("Unexpected received batch breakdown: %+v", b)
+	}
+}
@@ -1,8 +1,14 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"time"
+
+	"real-erp-mebel/be/internal/config"
 	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
 	"real-erp-mebel/be/internal/repositories"
 	"real-erp-mebel/be/internal/utils"
@@ -15,22 +21,29 @@ import (
 type UserService interface {
 	GetUserByID(id uint) (*dto.UserResponse, error)
 	ListUsers(req dto.ListUsersRequest) (*dto.ListUsersResponse, error)
-	CreateUser(req dto.CreateUserRequest) (*dto.UserResponse, error)
-	UpdateUser(id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error)
+	CreateUser(actorRole string, req dto.CreateUserRequest) (*dto.UserResponse, error)
+	UpdateUser(actorID uint, actorRole string, id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error)
 	DeleteUser(id uint) error
 	ChangePassword(userID uint, req dto.ChangePasswordRequest) error
+	CreateInvitation(inviterID uint, inviterRole string, req dto.CreateInvitationRequest) (*dto.InvitationResponse, error)
+	ListInvitations(req dto.ListInvitationsRequest) (*dto.ListInvitationsResponse, error)
+	RevokeInvitation(id uint) error
 }
 
 type userService struct {
-	userRepo repositories.UserRepository
-	logger   *zap.Logger
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	invitationRepo repositories.InvitationRepository
+	logger         *zap.Logger
 }
 
 // NewUserService membuat instance UserService baru
 func NewUserService() UserService {
 	return &userService{
-		userRepo: repositories.NewUserRepository(),
-		logger:   utils.GetLogger(),
+		userRepo:       repositories.NewUserRepository(),
+		roleRepo:       repositories.NewRoleRepository(),
+		invitationRepo: repositories.NewInvitationRepository(),
+		logger:         utils.GetLogger(),
 	}
 }
 
@@ -91,7 +104,12 @@ func (s *userService) ListUsers(req dto.ListUsersRequest) (*dto.ListUsersRespons
 	}, nil
 }
 
-func (s *userService) CreateUser(req dto.CreateUserRequest) (*dto.UserResponse, error) {
+func (s *userService) CreateUser(actorRole string, req dto.CreateUserRequest) (*dto.UserResponse, error) {
+	// Tidak boleh membuat user dengan permission yang tidak dimiliki pembuatnya (mis. owner / role custom "*")
+	if err := s.ensureCanGrantRole(actorRole, req.Peran); err != nil {
+		return nil, err
+	}
+
 	// Check if email already exists
 	existingUser, err := s.userRepo.FindByEmail(req.Email)
 	if err == nil && existingUser != nil {
@@ -101,6 +119,10 @@ func (s *userService) CreateUser(req dto.CreateUserRequest) (*dto.UserResponse,
 		return nil, utils.ErrUserExists
 	}
 
+	if err := s.validateRole(req.Peran); err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -136,7 +158,7 @@ func (s *userService) CreateUser(req dto.CreateUserRequest) (*dto.UserResponse,
 	return s.toUserResponse(user), nil
 }
 
-func (s *userService) UpdateUser(id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
+func (s *userService) UpdateUser(actorID uint, actorRole string, id uint, req dto.UpdateUserRequest) (*dto.UserResponse, error) {
 	// Get existing user
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
@@ -152,7 +174,20 @@ func (s *userService) UpdateUser(id uint, req dto.UpdateUserRequest) (*dto.UserR
 		user.Nama = *req.Nama
 	}
 
-	if req.Peran != nil {
+	if req.Peran != nil && *req.Peran != user.Peran {
+		// Role sendiri tidak boleh diubah (mencegah eskalasi, mis. admin_gudang -> owner)
+		if actorID == id {
+			return nil, utils.NewAppError(utils.ErrCodeForbidden, "Cannot change your own role", nil)
+		}
+		// Role lama & baru harus tercakup permission actor (mis. admin_gudang tidak bisa mencabut owner)
+		for _, kode := range []string{user.Peran, *req.Peran} {
+			if err := s.ensureCanGrantRole(actorRole, kode); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.validateRole(*req.Peran); err != nil {
+			return nil, err
+		}
 		user.Peran = *req.Peran
 	}
 
@@ -256,6 +291,154 @@ func (s *userService) ChangePassword(userID uint, req dto.ChangePasswordRequest)
 	return nil
 }
 
+func (s *userService) CreateInvitation(inviterID uint, inviterRole string, req dto.CreateInvitationRequest) (*dto.InvitationResponse, error) {
+	// Tidak boleh mengundang ke role dengan permission yang tidak dimiliki pengundang
+	if err := s.ensureCanGrantRole(inviterRole, req.Peran); err != nil {
+		return nil, err
+	}
+
+	if existingUser, err := s.userRepo.FindByEmail(req.Email); err == nil && existingUser != nil {
+		return nil, utils.ErrUserExists
+	}
+
+	if err := s.validateRole(req.Peran); err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeInternalError, "Failed to generate invitation token", err)
+	}
+
+	now := time.Now()
+	// Undangan lama untuk email yang sama dibatalkan agar hanya satu token yang berlaku
+	if err := s.invitationRepo.RevokePendingByEmail(req.Email, now); err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to revoke previous invitations", err)
+	}
+
+	invitation := &models.UndanganPengguna{
+		Email:           req.Email,
+		Peran:           req.Peran,
+		TokenHash:       tokenHash,
+		KedaluwarsaPada: now.Add(inviteExpiration()),
+		DibuatOleh:      inviterID,
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		s.logger.Error("Failed to create invitation",
+			zap.String("email", req.Email),
+			zap.Error(err),
+		)
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to create invitation", err)
+	}
+
+	s.logger.Info("Invitation created",
+		zap.Uint("invitation_id", invitation.ID),
+		zap.String("email", invitation.Email),
+		zap.String("peran", invitation.Peran),
+		zap.Uint("invited_by", inviterID),
+	)
+
+	response := toInvitationResponse(invitation, now)
+	response.Token = token
+	return response, nil
+}
+
+func (s *userService) ListInvitations(req dto.ListInvitationsRequest) (*dto.ListInvitationsResponse, error) {
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	invitations, total, err := s.invitationRepo.FindAll(page, pageSize)
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to list invitations", err)
+	}
+
+	now := time.Now()
+	responses := make([]dto.InvitationResponse, len(invitations))
+	for i := range invitations {
+		responses[i] = *toInvitationResponse(&invitations[i], now)
+	}
+
+	return &dto.ListInvitationsResponse{
+		Invitations: responses,
+		Pagination: dto.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int(math.Ceil(float64(total) / float64(pageSize))),
+		},
+	}, nil
+}
+
+func (s *userService) RevokeInvitation(id uint) error {
+	if _, err := s.invitationRepo.FindByID(id); err != nil {
+		return utils.NewAppError(utils.ErrCodeNotFound, "Invitation not found", nil)
+	}
+
+	if err := s.invitationRepo.Revoke(id, time.Now()); err != nil {
+		if errors.Is(err, repositories.ErrInvitationUnavailable) {
+			return utils.NewAppError(utils.ErrCodeValidationError, "Invitation has already been used or revoked", nil)
+		}
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to revoke invitation", err)
+	}
+
+	s.logger.Info("Invitation revoked", zap.Uint("invitation_id", id))
+	return nil
+}
+
+// ensureCanGrantRole memastikan seluruh permission role `kode` juga dimiliki role actor, sehingga user manager
+// tidak bisa mengeskalasi akses lewat role lain (owner, role custom dengan "*" / role.manage, finance, dll).
+// Role yang tidak terdaftar dilewati di sini dan ditolak oleh validateRole.
+func (s *userService) ensureCanGrantRole(actorRole, kode string) error {
+	actorPermissions, _, err := s.roleRepo.FindPermissionCodes(actorRole)
+	if err != nil {
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to load role permissions", err)
+	}
+	granted := make(map[string]bool, len(actorPermissions))
+	for _, p := range actorPermissions {
+		if p == middleware.PermissionAll {
+			return nil
+		}
+		granted[p] = true
+	}
+
+	targetPermissions, _, err := s.roleRepo.FindPermissionCodes(kode)
+	if err != nil {
+		return utils.NewAppError(utils.ErrCodeDatabaseError, "Failed to load role permissions", err)
+	}
+	for _, p := range targetPermissions {
+		if !granted[p] {
+			return utils.NewAppError(utils.ErrCodeForbidden,
+				fmt.Sprintf("Cannot assign role %s: permission %s exceeds your own", kode, p), nil)
+		}
+	}
+	return nil
+}
+
+// validateRole memastikan role terdaftar di tabel peran dan masih aktif
+func (s *userService) validateRole(kode string) error {
+	role, err := s.roleRepo.FindByKode(kode)
+	if err != nil {
+		s.logger.Warn("Invalid role",
+			zap.String("peran", kode),
+			zap.Error(err),
+		)
+		return utils.NewAppError(utils.ErrCodeValidationError, "Role not found", err)
+	}
+	if !role.Aktif {
+		return utils.NewAppError(utils.ErrCodeValidationError, "Role is inactive", nil)
+	}
+	return nil
+}
+
 // toUserResponse mengkonversi model User ke DTO UserResponse
 func (s *userService) toUserResponse(user *models.User) *dto.UserResponse {
 	return &dto.UserResponse{
@@ -266,3 +449,41 @@ func (s *userService) toUserResponse(user *models.User) *dto.UserResponse {
 		Aktif: user.Aktif,
 	}
 }
+
+// inviteExpiration membaca masa berlaku undangan dari config (fallback 72 jam)
+func inviteExpiration() time.Duration {
+	d, err := time.ParseDuration(config.AppConfig.Auth.InviteExpiration)
+	if err != nil || d <= 0 {
+		return 72 * time.Hour
+	}
+	return d
+}
+
+// invitationStatus menurunkan status undangan dari kolom waktunya
+func invitationStatus(invitation *models.UndanganPengguna, now time.Time) string {
+	switch {
+	case invitation.DipakaiPada != nil:
+		return "accepted"
+	case invitation.DibatalkanPada != nil:
+		return "revoked"
+	case !now.Before(invitation.KedaluwarsaPada):
+		return "expired"
+	default:
+		return "pending"
+	}
+}
+
+// toInvitationResponse mengkonversi model Invitation ke DTO (tanpa token)
+func toInvitationResponse(invitation *models.UndanganPengguna, now time.Time) *dto.InvitationResponse {
+	return &dto.InvitationResponse{
+		ID:              invitation.ID,
+		Email:           invitation.Email,
+		Peran:           invitation.Peran,
+		Status:          invitationStatus(invitation, now),
+		KedaluwarsaPada: invitation.KedaluwarsaPada,
+		DipakaiPada:     invitation.DipakaiPada,
+		DibatalkanPada:  invitation.DibatalkanPada,
+		DibuatOleh:      invitation.Pembuat.Nama,
+		DibuatPada:      invitation.DibuatPada,
+	}
+}
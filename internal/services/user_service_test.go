@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/middleware"
 	"real-erp-mebel/be/internal/models"
 	"real-erp-mebel/be/internal/repositories"
 	"real-erp-mebel/be/internal/utils"
@@ -122,10 +123,68 @@ func (m *MockUserRepository) Count(search, peran string, aktif *bool) (int64, er
 	return int64(len(m.users)), nil
 }
 
+func (m *MockUserRepository) IncrementTokenVersion(id uint) error {
+	user, exists := m.users[id]
+	if !exists {
+		return gorm.ErrRecordNotFound
+	}
+	user.VersiToken++
+	return nil
+}
+
+func (m *MockUserRepository) CountAll() (int64, error) {
+	return int64(len(m.users)), nil
+}
+
+func (m *MockUserRepository) CreateIfEmpty(user *models.User) (bool, error) {
+	if len(m.users) > 0 {
+		return false, nil
+	}
+	return true, m.Create(user)
+}
+
+// MockRoleRepository adalah mock untuk RoleRepository (hanya lookup role yang dipakai user service)
+type MockRoleRepository struct {
+	repositories.RoleRepository
+	roles       map[string]*models.PeranPengguna
+	permissions map[string][]string
+}
+
+func (m *MockRoleRepository) FindPermissionCodes(kode string) ([]string, bool, error) {
+	permissions, exists := m.permissions[kode]
+	return permissions, exists, nil
+}
+
+func (m *MockRoleRepository) FindByKode(kode string) (*models.PeranPengguna, error) {
+	role, exists := m.roles[kode]
+	if !exists {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+// newMockRoleRepository berisi role bawaan, satu role custom dengan akses penuh, dan satu role nonaktif
+func newMockRoleRepository() *MockRoleRepository {
+	roles := make(map[string]*models.PeranPengguna)
+	permissions := make(map[string][]string)
+	for _, kode := range []string{"owner", "kasir", "admin_gudang", "finance"} {
+		roles[kode] = &models.PeranPengguna{Kode: kode, Sistem: true, Aktif: true}
+		permissions[kode] = middleware.DefaultRolePermissions[kode]
+	}
+	roles["super_admin"] = &models.PeranPengguna{Kode: "super_admin", Aktif: true}
+	permissions["super_admin"] = []string{middleware.PermissionAll}
+	roles["staf_gudang"] = &models.PeranPengguna{Kode: "staf_gudang", Aktif: true}
+	permissions["staf_gudang"] = []string{middleware.PermProductRead, middleware.PermStockRead, middleware.PermStockIn}
+	roles["supervisor_lama"] = &models.PeranPengguna{Kode: "supervisor_lama", Aktif: false}
+	permissions["supervisor_lama"] = []string{}
+	return &MockRoleRepository{roles: roles, permissions: permissions}
+}
+
 // Helper function untuk membuat user service dengan mock
 func newTestUserService(mockRepo repositories.UserRepository) *userService {
 	return &userService{
 		userRepo: mockRepo,
+		roleRepo: newMockRoleRepository(),
 		logger:   zap.NewNop(), // No-op logger untuk testing
 	}
 }
@@ -211,7 +270,7 @@ func TestCreateUser_Success(t *testing.T) {
 	}
 
 	// Execute
-	result, err := service.CreateUser(req)
+	result, err := service.CreateUser("owner", req)
 
 	// Assert
 	if err != nil {
@@ -264,7 +323,7 @@ func TestCreateUser_EmailExists(t *testing.T) {
 	}
 
 	// Execute
-	result, err := service.CreateUser(req)
+	result, err := service.CreateUser("owner", req)
 
 	// Assert
 	if err == nil {
@@ -291,7 +350,7 @@ func TestCreateUser_DatabaseError(t *testing.T) {
 	}
 
 	// Execute
-	result, err := service.CreateUser(req)
+	result, err := service.CreateUser("owner", req)
 
 	// Assert
 	if err == nil {
@@ -306,6 +365,44 @@ func TestCreateUser_DatabaseError(t *testing.T) {
 	}
 }
 
+func TestCreateUser_InvalidRole(t *testing.T) {
+	service := newTestUserService(NewMockUserRepository())
+
+	for _, peran := range []string{"tidak_ada", "supervisor_lama"} {
+		req := dto.CreateUserRequest{
+			Email:    peran + "@example.com",
+			Password: "password123",
+			Nama:     "Test User",
+			Peran:    peran,
+		}
+
+		_, err := service.CreateUser("owner", req)
+		if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeValidationError {
+			t.Errorf("Expected validation error for role %s, got %v", peran, err)
+		}
+	}
+}
+
+func TestCreateUser_OwnerRequiresOwner(t *testing.T) {
+	service := newTestUserService(NewMockUserRepository())
+
+	req := dto.CreateUserRequest{
+		Email:    "owner2@example.com",
+		Password: "password123",
+		Nama:     "Owner Baru",
+		Peran:    "owner",
+	}
+
+	_, err := service.CreateUser("admin_gudang", req)
+	if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeForbidden {
+		t.Errorf("Expected forbidden when admin_gudang creates owner, got %v", err)
+	}
+
+	if _, err := service.CreateUser("owner", req); err != nil {
+		t.Errorf("Expected owner to create owner, got %v", err)
+	}
+}
+
 // ==================== Test ListUsers ====================
 
 func TestListUsers_Success(t *testing.T) {
@@ -452,7 +549,7 @@ func TestUpdateUser_Success(t *testing.T) {
 	}
 
 	// Execute
-	result, err := service.UpdateUser(1, req)
+	result, err := service.UpdateUser(100, "owner", 1, req)
 
 	// Assert
 	if err != nil {
@@ -476,7 +573,7 @@ func TestUpdateUser_NotFound(t *testing.T) {
 	}
 
 	// Execute: User tidak ada
-	result, err := service.UpdateUser(999, req)
+	result, err := service.UpdateUser(100, "owner", 999, req)
 
 	// Assert
 	if err == nil {
@@ -516,7 +613,7 @@ func TestUpdateUser_UpdateAllFields(t *testing.T) {
 	}
 
 	// Execute
-	result, err := service.UpdateUser(1, req)
+	result, err := service.UpdateUser(100, "owner", 1, req)
 
 	// Assert
 	if err != nil {
@@ -533,6 +630,41 @@ func TestUpdateUser_UpdateAllFields(t *testing.T) {
 	}
 }
 
+func TestUpdateUser_RoleGuards(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	service := newTestUserService(mockRepo)
+
+	hashedPwd, _ := hashPassword("password123")
+	mockRepo.SetUser(&models.User{ID: 1, Email: "admin@example.com", Password: hashedPwd, Nama: "Admin", Peran: "admin_gudang", Aktif: true})
+	mockRepo.SetUser(&models.User{ID: 2, Email: "kasir@example.com", Password: hashedPwd, Nama: "Kasir", Peran: "kasir", Aktif: true})
+	mockRepo.SetUser(&models.User{ID: 3, Email: "owner@example.com", Password: hashedPwd, Nama: "Owner", Peran: "owner", Aktif: true})
+
+	owner := "owner"
+	finance := "finance"
+	cases := []struct {
+		name    string
+		actorID uint
+		role    string
+		id      uint
+		peran   *string
+	}{
+		{"promote self", 1, "admin_gudang", 1, &owner},
+		{"change own role", 1, "admin_gudang", 1, &finance},
+		{"promote other to owner", 1, "admin_gudang", 2, &owner},
+		{"demote owner", 1, "admin_gudang", 3, &finance},
+	}
+	for _, tc := range cases {
+		_, err := service.UpdateUser(tc.actorID, tc.role, tc.id, dto.UpdateUserRequest{Peran: tc.peran})
+		if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeForbidden {
+			t.Errorf("%s: expected forbidden, got %v", tc.name, err)
+		}
+	}
+
+	if _, err := service.UpdateUser(3, "owner", 2, dto.UpdateUserRequest{Peran: &owner}); err != nil {
+		t.Errorf("Expected owner to promote kasir to owner, got %v", err)
+	}
+}
+
 // ==================== Test DeleteUser ====================
 
 func TestDeleteUser_Success(t *testing.T) {
@@ -754,3 +886,93 @@ func TestToUserResponse(t *testing.T) {
 	// Verify password tidak ada di response
 	// (Password field tidak ada di UserResponse DTO)
 }
+
+// ==================== Test Invitations ====================
+
+func TestCreateInvitation_OwnerRequiresOwner(t *testing.T) {
+	service := newTestUserService(NewMockUserRepository())
+
+	_, err := service.CreateInvitation(2, "admin_gudang", dto.CreateInvitationRequest{
+		Email: "owner2@example.com",
+		Peran: "owner",
+	})
+
+	if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeForbidden {
+		t.Errorf("Expected forbidden error, got %v", err)
+	}
+}
+
+func TestInvitationStatus(t *testing.T) {
+	now := time.Date(2024, 11, 2, 8, 0, 0, 0, time.UTC)
+	used := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		invitation models.UndanganPengguna
+		want       string
+	}{
+		{"pending", models.UndanganPengguna{KedaluwarsaPada: now.Add(time.Hour)}, "pending"},
+		{"expired", models.UndanganPengguna{KedaluwarsaPada: now}, "expired"},
+		{"accepted", models.UndanganPengguna{KedaluwarsaPada: now.Add(-time.Hour), DipakaiPada: &used}, "accepted"},
+		{"revoked", models.UndanganPengguna{KedaluwarsaPada: now.Add(time.Hour), DibatalkanPada: &used}, "revoked"},
+	}
+	for _, tt := range tests {
+		if got := invitationStatus(&tt.invitation, now); got != tt.want {
+			t.Errorf("%s: invitationStatus = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	token, hash, err := generateOpaqueToken()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(token) != 64 || hash == token {
+		t.Errorf("Expected 64-char token with distinct hash, got %q / %q", token, hash)
+	}
+	if hashOpaqueToken(token) != hash {
+		t.Error("Expected hashOpaqueToken to match the generated hash")
+	}
+}
+
+func TestRoleEscalation_CustomRoles(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	service := newTestUserService(mockRepo)
+
+	hashedPwd, _ := hashPassword("password123")
+	mockRepo.SetUser(&models.User{ID: 2, Email: "staf@example.com", Password: hashedPwd, Nama: "Staf", Peran: "staf_gudang", Aktif: true})
+
+	// admin_gudang (punya user.manage) tidak boleh memberi role custom "*", finance, maupun kasir
+	// (sales.create / shift.operate tidak dimiliki admin_gudang)
+	for _, peran := range []string{"super_admin", "finance", "kasir"} {
+		_, err := service.CreateUser("admin_gudang", dto.CreateUserRequest{
+			Email: peran + "@example.com", Password: "password123", Nama: "Baru", Peran: peran,
+		})
+		if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeForbidden {
+			t.Errorf("CreateUser %s: expected forbidden, got %v", peran, err)
+		}
+
+		p := peran
+		_, err = service.UpdateUser(1, "admin_gudang", 2, dto.UpdateUserRequest{Peran: &p})
+		if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeForbidden {
+			t.Errorf("UpdateUser %s: expected forbidden, got %v", peran, err)
+		}
+
+		_, err = service.CreateInvitation(1, "admin_gudang", dto.CreateInvitationRequest{Email: "undangan@example.com", Peran: peran})
+		if appErr := utils.GetAppError(err); err == nil || appErr.Code != utils.ErrCodeForbidden {
+			t.Errorf("CreateInvitation %s: expected forbidden, got %v", peran, err)
+		}
+	}
+
+	// Role yang permission-nya tercakup tetap boleh diberikan
+	if _, err := service.CreateUser("admin_gudang", dto.CreateUserRequest{
+		Email: "staf2@example.com", Password: "password123", Nama: "Staf Gudang", Peran: "staf_gudang",
+	}); err != nil {
+		t.Errorf("Expected admin_gudang to create staf_gudang, got %v", err)
+	}
+	super := "super_admin"
+	if _, err := service.UpdateUser(3, "owner", 2, dto.UpdateUserRequest{Peran: &super}); err != nil {
+		t.Errorf("Expected owner to assign custom full-access role, got %v", err)
+	}
+}
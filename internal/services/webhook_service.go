@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"real-erp-mebel/be/internal/dto"
+	"real-erp-mebel/be/internal/events"
+	"real-erp-mebel/be/internal/models"
+	"real-erp-mebel/be/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookService interface {
+	CreateWebhook(userID uint, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error)
+	ListWebhooks(req *dto.ListWebhookRequest) ([]dto.WebhookResponse, int64, error)
+	GetWebhookByID(id uint) (*dto.WebhookResponse, error)
+	UpdateWebhook(id uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	DeleteWebhook(id uint) error
+
+	// Log pengiriman & kirim ulang manual
+	ListDeliveries(webhookID uint, req *dto.ListWebhookDeliveryRequest) ([]dto.WebhookDeliveryResponse, int64, error)
+	RetryDelivery(webhookID, deliveryID uint) (*dto.WebhookDeliveryResponse, error)
+}
+
+type webhookService struct {
+	repo repositories.WebhookRepository
+}
+
+func NewWebhookService(repo repositories.WebhookRepository) WebhookService {
+	return &webhookService{repo: repo}
+}
+
+// CreateWebhook mendaftarkan endpoint webhook. Secret dibuat otomatis jika tidak dikirim
+// dan hanya dikembalikan di response ini.
+func (s *webhookService) CreateWebhook(userID uint, req *dto.CreateWebhookRequest) (*dto.WebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	tipeEvent, err := normalizeWebhookEventTypes(req.TipeEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, _, err = generateOpaqueToken(); err != nil {
+			return nil, fmt.Errorf("gagal membuat secret webhook: %w", err)
+		}
+	}
+
+	now := time.Now()
+	webhook := models.LanggananWebhook{
+		Nama:           strings.TrimSpace(req.Nama),
+		URL:            req.URL,
+		Secret:         secret,
+		TipeEvent:      strings.Join(tipeEvent, ","),
+		Aktif:          req.Aktif == nil || *req.Aktif,
+		DibuatOleh:     userID,
+		DibuatPada:     now,
+		DiperbaruiPada: now,
+	}
+	if err := s.repo.Create(&webhook); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan webhook: %w", err)
+	}
+
+	resp, err := s.GetWebhookByID(webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Secret = secret
+	return resp, nil
+}
+
+func (s *webhookService) ListWebhooks(req *dto.ListWebhookRequest) ([]dto.WebhookResponse, int64, error) {
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	webhooks, total, err := s.repo.FindAll(page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	responses := make([]dto.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		responses = append(responses, *mapWebhookToResponse(&webhooks[i]))
+	}
+	return responses, total, nil
+}
+
+func (s *webhookService) GetWebhookByID(id uint) (*dto.WebhookResponse, error) {
+	webhook, err := s.findWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapWebhookToResponse(webhook), nil
+}
+
+func (s *webhookService) UpdateWebhook(id uint, req *dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	webhook, err := s.findWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Nama != nil {
+		webhook.Nama = strings.TrimSpace(*req.Nama)
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+	}
+	if req.TipeEvent != nil {
+		tipeEvent, err := normalizeWebhookEventTypes(req.TipeEvent)
+		if err != nil {
+			return nil, err
+		}
+		webhook.TipeEvent = strings.Join(tipeEvent, ",")
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if req.Aktif != nil {
+		webhook.Aktif = *req.Aktif
+	}
+	webhook.DiperbaruiPada = time.Now()
+
+	if err := s.repo.Update(webhook); err != nil {
+		return nil, fmt.Errorf("gagal mengubah webhook: %w", err)
+	}
+	return s.GetWebhookByID(id)
+}
+
+func (s *webhookService) DeleteWebhook(id uint) error {
+	if _, err := s.findWebhook(id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("gagal menghapus webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(webhookID uint, req *dto.ListWebhookDeliveryRequest) ([]dto.WebhookDeliveryResponse, int64, error) {
+	if _, err := s.findWebhook(webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	deliveries, total, err := s.repo.FindDeliveries(webhookID, req.Status, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	responses := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		responses = append(responses, *mapWebhookDeliveryToResponse(&deliveries[i]))
+	}
+	return responses, total, nil
+}
+
+// RetryDelivery menjadwalkan ulang pengiriman yang gagal / masih pending agar segera dikirim worker
+func (s *webhookService) RetryDelivery(webhookID, deliveryID uint) (*dto.WebhookDeliveryResponse, error) {
+	delivery, err := s.repo.FindDeliveryByID(deliveryID)
+	if err != nil || delivery.IDWebhook != webhookID {
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengiriman webhook tidak ditemukan")
+		}
+		return nil, err
+	}
+	if delivery.Status == "success" {
+		return nil, errors.New("pengiriman webhook sudah berhasil, tidak perlu dikirim ulang")
+	}
+
+	now := time.Now()
+	if err := s.repo.UpdateDelivery(deliveryID, map[string]interface{}{
+		"status":               "pending",
+		"percobaan_berikutnya": now,
+		"diperbarui_pada":      now,
+	}); err != nil {
+		return nil, fmt.Errorf("gagal menjadwalkan ulang pengiriman webhook: %w", err)
+	}
+
+	delivery, err = s.repo.FindDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return mapWebhookDeliveryToResponse(delivery), nil
+}
+
+func (s *webhookService) findWebhook(id uint) (*models.LanggananWebhook, error) {
+	webhook, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook tidak ditemukan")
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// validateWebhookURL memastikan webhook dikirim via http/https
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url webhook harus diawali http:// atau https://")
+	}
+	return nil
+}
+
+// normalizeWebhookEventTypes memvalidasi tipe event webhook, membuang duplikat,
+// dan meringkas daftar yang mengandung "*" menjadi ["*"].
+func normalizeWebhookEventTypes(types []string) ([]string, error) {
+	known := make(map[string]bool, len(events.Types))
+	for _, t := range events.Types {
+		known[t] = true
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if t == events.WebhookEventAll {
+			return []string{events.WebhookEventAll}, nil
+		}
+		if !known[t] {
+			return nil, fmt.Errorf("tipe event '%s' tidak dikenal (pilihan: %s atau %s)", t, strings.Join(events.Types, ", "), events.WebhookEventAll)
+		}
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("minimal satu tipe event wajib dipilih")
+	}
+	return result, nil
+}
+
+func mapWebhookToResponse(w *models.LanggananWebhook) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:             w.ID,
+		Nama:           w.Nama,
+		URL:            w.URL,
+		TipeEvent:      strings.Split(w.TipeEvent, ","),
+		Aktif:          w.Aktif,
+		NamaPembuat:    w.DibuatOlehPengguna.Nama,
+		DibuatPada:     w.DibuatPada,
+		DiperbaruiPada: w.DiperbaruiPada,
+	}
+}
+
+func mapWebhookDeliveryToResponse(d *models.PengirimanWebhook) *dto.WebhookDeliveryResponse {
+	resp := &dto.WebhookDeliveryResponse{
+		ID:             d.ID,
+		IDWebhook:      d.IDWebhook,
+		IDEvent:        d.IDEvent,
+		TipeEvent:      d.TipeEvent,
+		Status:         d.Status,
+		Percobaan:      d.Percobaan,
+		StatusHTTP:     d.StatusHTTP,
+		ResponTerakhir: d.ResponTerakhir,
+		ErrorTerakhir:  d.ErrorTerakhir,
+		DurasiMs:       d.DurasiMs,
+		Payload:        d.Payload,
+		TerkirimPada:   d.TerkirimPada,
+		DibuatPada:     d.DibuatPada,
+	}
+	if d.Status == "pending" {
+		next := d.PercobaanBerikutnya
+		resp.PercobaanBerikutnya = &next
+	}
+	return resp
+}
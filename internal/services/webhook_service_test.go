@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"real-erp-mebel/be/internal/events"
+)
+
+func TestNormalizeWebhookEventTypes(t *testing.T) {
+	types, err := normalizeWebhookEventTypes([]string{events.TypeSaleCreated, " stock.low ", events.TypeSaleCreated})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(types) != 2 || types[0] != events.TypeSaleCreated || types[1] != events.TypeStockLow {
+		t.Errorf("Expected deduplicated types, got %v", types)
+	}
+
+	types, err = normalizeWebhookEventTypes([]string{events.TypeSaleCreated, events.WebhookEventAll})
+	if err != nil || len(types) != 1 || types[0] != events.WebhookEventAll {
+		t.Errorf("Expected [*], got %v (err %v)", types, err)
+	}
+
+	if _, err := normalizeWebhookEventTypes([]string{"order.deleted"}); err == nil {
+		t.Error("Expected error for unknown event type")
+	}
+	if _, err := normalizeWebhookEventTypes(nil); err == nil {
+		t.Error("Expected error for empty event types")
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, u := range []string{"https://example.com/hook", "http://10.0.0.5:8080/erp"} {
+		if err := validateWebhookURL(u); err != nil {
+			t.Errorf("Expected %s to be valid, got %v", u, err)
+		}
+	}
+	for _, u := range []string{"ftp://example.com", "example.com/hook", "https://"} {
+		if err := validateWebhookURL(u); err == nil {
+			t.Errorf("Expected %s to be rejected", u)
+		}
+	}
+}
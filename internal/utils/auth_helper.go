@@ -19,3 +19,15 @@ func GetUserIDValidity(c *gin.Context) uint {
 	}
 	return 0
 }
+
+// GetUserRole extracts role from context safely
+func GetUserRole(c *gin.Context) string {
+	role, exists := c.Get("role")
+	if !exists {
+		return ""
+	}
+	if r, ok := role.(string); ok {
+		return r
+	}
+	return ""
+}
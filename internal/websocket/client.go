@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"log"
 	"net/http"
 	"time"
@@ -53,8 +54,36 @@ func (c *Client) ReadPump() {
 			break
 		}
 
-		// Handle incoming message if needed
-		log.Printf("Received message from client: %s", message)
+		c.handleMessage(message)
+	}
+}
+
+// clientMessage adalah pesan kontrol dari client, contoh:
+// {"action": "subscribe", "topics": ["warehouse:1"]}
+// {"action": "replay", "since_id": 120}
+type clientMessage struct {
+	Action  string   `json:"action"`
+	Topics  []string `json:"topics"`
+	SinceID uint     `json:"since_id"`
+}
+
+// handleMessage memproses pesan kontrol dari client
+func (c *Client) handleMessage(message []byte) {
+	var msg clientMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		log.Printf("Ignoring invalid message from user %d: %v", c.UserID, err)
+		return
+	}
+
+	switch msg.Action {
+	case "subscribe":
+		c.Hub.Subscribe(c, msg.Topics...)
+	case "unsubscribe":
+		c.Hub.Unsubscribe(c, msg.Topics...)
+	case "replay":
+		go c.Hub.Replay(c, msg.SinceID)
+	default:
+		log.Printf("Ignoring unknown action %q from user %d", msg.Action, c.UserID)
 	}
 }
 
@@ -0,0 +1,115 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// maxReplayEvents membatasi jumlah event per permintaan replay agar buffer Send client tidak penuh.
+// Jika has_more = true, client meminta replay lagi dengan ID terakhir yang diterima.
+const maxReplayEvents = 100
+
+// EventMessage adalah format event domain yang dikirim ke client.
+// ID berasal dari outbox dan naik terus, sehingga client bisa menyimpan ID terakhir
+// dan meminta replay setelah reconnect. Event replay bisa tumpang tindih dengan event live,
+// jadi client sebaiknya mengabaikan ID yang sudah pernah diterima.
+type EventMessage struct {
+	ID         uint            `json:"id"`
+	Type       string          `json:"type"`
+	Topics     []string        `json:"topics,omitempty"`
+	Data       json.RawMessage `json:"data"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// ReplaySource mengambil event dengan ID > sinceID (urut ID naik), maksimal limit
+type ReplaySource func(sinceID uint, limit int) ([]EventMessage, error)
+
+// replayBatch adalah hasil replay yang dikirim ke satu client lewat goroutine Hub.Run
+type replayBatch struct {
+	Client  *Client
+	SinceID uint
+	Events  []EventMessage
+	HasMore bool
+}
+
+// SetReplaySource memasang sumber event untuk replay (dipanggil sekali saat startup)
+func (h *Hub) SetReplaySource(source ReplaySource) {
+	h.replaySource = source
+}
+
+// PublishEvent mengirim event ke client yang berlangganan salah satu topic-nya (tanpa topic = semua client)
+func (h *Hub) PublishEvent(event EventMessage) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(event.Topics) == 0 {
+		h.BroadcastMessage(data)
+	} else {
+		h.PublishTopics(event.Topics, data)
+	}
+	return nil
+}
+
+// Replay mengirim ulang event setelah sinceID ke client, hanya untuk topic yang sedang diikuti client
+func (h *Hub) Replay(client *Client, sinceID uint) {
+	if h.replaySource == nil {
+		return
+	}
+	events, err := h.replaySource(sinceID, maxReplayEvents+1)
+	if err != nil {
+		log.Printf("Failed to load replay events for user %d: %v", client.UserID, err)
+		return
+	}
+
+	batch := replayBatch{Client: client, SinceID: sinceID, Events: events}
+	if len(events) > maxReplayEvents {
+		batch.Events = events[:maxReplayEvents]
+		batch.HasMore = true
+	}
+	h.replays <- batch
+}
+
+// applyReplay mengirim event replay yang boleh diterima client lalu ack {"type":"replay"}
+func (h *Hub) applyReplay(batch replayBatch) {
+	client := batch.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	var lastID uint
+	sent := 0
+	for _, event := range batch.Events {
+		lastID = event.ID
+		if len(event.Topics) > 0 && !client.subscribedToAny(event.Topics) {
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		select {
+		case client.Send <- data:
+			sent++
+		default:
+			h.removeClient(client)
+			return
+		}
+	}
+
+	ack, _ := json.Marshal(map[string]interface{}{
+		"type": "replay",
+		"data": map[string]interface{}{
+			"since_id": batch.SinceID,
+			"last_id":  lastID,
+			"count":    sent,
+			"has_more": batch.HasMore,
+		},
+	})
+	select {
+	case client.Send <- ack:
+	default:
+		h.removeClient(client)
+	}
+}
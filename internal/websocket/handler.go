@@ -2,13 +2,37 @@ package websocket
 
 import (
 	"log"
+	"strconv"
+	"strings"
+
+	"real-erp-mebel/be/internal/middleware"
+	"real-erp-mebel/be/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// HandleWebSocket handles websocket requests from clients
+// HandleWebSocket handles websocket requests from clients.
+// JWT divalidasi sebelum upgrade, sama seperti AuthMiddleware. Browser tidak bisa mengirim
+// header Authorization pada WebSocket, jadi token juga diterima lewat query ?token=.
+// Topic tambahan bisa diminta lewat query ?topics=warehouse:1,warehouse:2, dan event yang
+// terlewat selama terputus bisa diminta lewat ?since_id=<ID event terakhir>.
 func HandleWebSocket(hub *Hub) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		tokenString := c.Query("token")
+		if tokenString == "" {
+			var err error
+			if tokenString, err = middleware.BearerToken(c.GetHeader("Authorization")); err != nil {
+				utils.Unauthorized(c, err.Error())
+				return
+			}
+		}
+
+		claims, err := middleware.ValidateAccessToken(tokenString)
+		if err != nil {
+			utils.Unauthorized(c, err.Error())
+			return
+		}
+
 		// Upgrade connection to websocket
 		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 		if err != nil {
@@ -16,25 +40,35 @@ func HandleWebSocket(hub *Hub) gin.HandlerFunc {
 			return
 		}
 
-		// Get user ID from query or header (you can modify this based on your auth)
-		userID := c.Query("user_id")
-		if userID == "" {
-			userID = "anonymous"
-		}
-
 		// Create new client
 		client := &Client{
 			Hub:    hub,
 			Conn:   conn,
 			Send:   make(chan []byte, 256),
-			UserID: userID,
+			UserID: claims.UserID,
+			Role:   claims.Role,
+			topics: make(map[string]bool),
 		}
 
 		// Register client
 		hub.Register <- client
 
+		// Topic default: user sendiri + role-nya
+		topics := []string{TopicUser(client.UserID), TopicRole(client.Role)}
+		if extra := c.Query("topics"); extra != "" {
+			for _, topic := range strings.Split(extra, ",") {
+				topics = append(topics, strings.TrimSpace(topic))
+			}
+		}
+		hub.Subscribe(client, topics...)
+
 		// Start goroutines for reading and writing
 		go client.WritePump()
 		go client.ReadPump()
+
+		// Replay dijalankan setelah subscribe agar event difilter dengan topic client
+		if sinceID, err := strconv.ParseUint(c.Query("since_id"), 10, 64); err == nil {
+			go hub.Replay(client, uint(sinceID))
+		}
 	}
 }
@@ -1,6 +1,7 @@
 package websocket
 
 import (
+	"encoding/json"
 	"log"
 
 	"github.com/gorilla/websocket"
@@ -11,7 +12,25 @@ type Client struct {
 	Hub    *Hub
 	Conn   *websocket.Conn
 	Send   chan []byte
-	UserID string
+	UserID uint
+	Role   string
+
+	// Topic yang diikuti client; hanya diubah dari goroutine Hub.Run
+	topics map[string]bool
+}
+
+// topicMessage adalah pesan untuk satu atau beberapa topic (tanpa topic = semua client).
+// Client yang berlangganan lebih dari satu topic tujuan tetap menerima pesan sekali.
+type topicMessage struct {
+	Topics []string
+	Data   []byte
+}
+
+// subscription adalah permintaan subscribe/unsubscribe dari client
+type subscription struct {
+	Client    *Client
+	Topics    []string
+	Subscribe bool
 }
 
 // Hub maintains the set of active clients and broadcasts messages to the clients
@@ -27,15 +46,30 @@ type Hub struct {
 
 	// Unregister requests from clients
 	Unregister chan *Client
+
+	// Pesan yang ditargetkan ke topic tertentu
+	publish chan topicMessage
+
+	// Permintaan subscribe/unsubscribe topic
+	subscriptions chan subscription
+
+	// Hasil replay event untuk client yang reconnect
+	replays chan replayBatch
+
+	// Sumber event untuk replay (outbox)
+	replaySource ReplaySource
 }
 
 // NewHub creates a new Hub instance
 func NewHub() *Hub {
 	return &Hub{
-		Clients:    make(map[*Client]bool),
-		Broadcast:  make(chan []byte),
-		Register:   make(chan *Client),
-		Unregister: make(chan *Client),
+		Clients:       make(map[*Client]bool),
+		Broadcast:     make(chan []byte),
+		Register:      make(chan *Client),
+		Unregister:    make(chan *Client),
+		publish:       make(chan topicMessage),
+		subscriptions: make(chan subscription),
+		replays:       make(chan replayBatch),
 	}
 }
 
@@ -49,26 +83,114 @@ func (h *Hub) Run() {
 
 		case client := <-h.Unregister:
 			if _, ok := h.Clients[client]; ok {
-				delete(h.Clients, client)
-				close(client.Send)
+				h.removeClient(client)
 				log.Printf("Client disconnected. Total clients: %d", len(h.Clients))
 			}
 
 		case message := <-h.Broadcast:
 			// Broadcast message to all connected clients
-			for client := range h.Clients {
-				select {
-				case client.Send <- message:
-				default:
-					close(client.Send)
-					delete(h.Clients, client)
-				}
-			}
+			h.deliver(topicMessage{Data: message})
+
+		case message := <-h.publish:
+			h.deliver(message)
+
+		case sub := <-h.subscriptions:
+			h.applySubscription(sub)
+
+		case batch := <-h.replays:
+			h.applyReplay(batch)
+		}
+	}
+}
+
+// deliver mengirim pesan ke client yang berlangganan salah satu topic (atau semua client jika tanpa topic)
+func (h *Hub) deliver(message topicMessage) {
+	for client := range h.Clients {
+		if len(message.Topics) > 0 && !client.subscribedToAny(message.Topics) {
+			continue
+		}
+		select {
+		case client.Send <- message.Data:
+		default:
+			h.removeClient(client)
+		}
+	}
+}
+
+// applySubscription memproses subscribe/unsubscribe lalu mengirim daftar topic terbaru ke client
+func (h *Hub) applySubscription(sub subscription) {
+	client := sub.Client
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+
+	rejected := []string{}
+	for _, topic := range sub.Topics {
+		if !sub.Subscribe {
+			delete(client.topics, topic)
+			continue
+		}
+		if !client.canSubscribe(topic) {
+			rejected = append(rejected, topic)
+			continue
+		}
+		client.topics[topic] = true
+	}
+
+	topics := make([]string, 0, len(client.topics))
+	for topic := range client.topics {
+		topics = append(topics, topic)
+	}
+	ack, _ := json.Marshal(map[string]interface{}{
+		"type": "subscriptions",
+		"data": map[string]interface{}{
+			"topics":   topics,
+			"rejected": rejected,
+		},
+	})
+
+	select {
+	case client.Send <- ack:
+	default:
+		h.removeClient(client)
+	}
+}
+
+func (c *Client) subscribedToAny(topics []string) bool {
+	for _, topic := range topics {
+		if c.topics[topic] {
+			return true
 		}
 	}
+	return false
+}
+
+func (h *Hub) removeClient(client *Client) {
+	delete(h.Clients, client)
+	close(client.Send)
 }
 
 // BroadcastMessage sends a message to all connected clients
 func (h *Hub) BroadcastMessage(message []byte) {
 	h.Broadcast <- message
 }
+
+// Publish sends a message only to clients subscribed to the topic
+func (h *Hub) Publish(topic string, message []byte) {
+	h.PublishTopics([]string{topic}, message)
+}
+
+// PublishTopics sends a message once to every client subscribed to at least one of the topics
+func (h *Hub) PublishTopics(topics []string, message []byte) {
+	h.publish <- topicMessage{Topics: topics, Data: message}
+}
+
+// Subscribe menambahkan topic ke client (topic yang tidak diizinkan diabaikan)
+func (h *Hub) Subscribe(client *Client, topics ...string) {
+	h.subscriptions <- subscription{Client: client, Topics: topics, Subscribe: true}
+}
+
+// Unsubscribe menghapus topic dari client
+func (h *Hub) Unsubscribe(client *Client, topics ...string) {
+	h.subscriptions <- subscription{Client: client, Topics: topics}
+}
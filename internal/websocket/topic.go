@@ -0,0 +1,57 @@
+package websocket
+
+import (
+	"fmt"
+	"strings"
+
+	"real-erp-mebel/be/internal/middleware"
+)
+
+// Topic dipakai untuk menargetkan pesan ke sebagian client saja.
+// Setiap client otomatis berlangganan topic user dan role miliknya.
+const (
+	topicPrefixUser       = "user:"
+	topicPrefixRole       = "role:"
+	topicPrefixWarehouse  = "warehouse:"
+	topicPrefixPermission = "permission:"
+)
+
+// TopicUser mengembalikan topic untuk satu user
+func TopicUser(userID uint) string {
+	return fmt.Sprintf("%s%d", topicPrefixUser, userID)
+}
+
+// TopicRole mengembalikan topic untuk semua user dengan role tertentu
+func TopicRole(role string) string {
+	return topicPrefixRole + role
+}
+
+// TopicWarehouse mengembalikan topic untuk event sebuah gudang
+func TopicWarehouse(warehouseID uint) string {
+	return fmt.Sprintf("%s%d", topicPrefixWarehouse, warehouseID)
+}
+
+// TopicPermission mengembalikan topic untuk semua user yang memiliki permission tertentu
+// (misalnya approver retur berlangganan permission:sales_return.approve)
+func TopicPermission(permission string) string {
+	return topicPrefixPermission + permission
+}
+
+// canSubscribe mengecek apakah client boleh berlangganan topic:
+// user/role hanya miliknya sendiri, gudang butuh permission stock.read,
+// dan topic permission hanya untuk role yang memiliki permission tersebut.
+func (c *Client) canSubscribe(topic string) bool {
+	switch {
+	case strings.HasPrefix(topic, topicPrefixUser):
+		return topic == TopicUser(c.UserID)
+	case strings.HasPrefix(topic, topicPrefixRole):
+		return topic == TopicRole(c.Role)
+	case strings.HasPrefix(topic, topicPrefixWarehouse):
+		return len(topic) > len(topicPrefixWarehouse) && middleware.RoleHasPermission(c.Role, middleware.PermStockRead)
+	case strings.HasPrefix(topic, topicPrefixPermission):
+		permission := strings.TrimPrefix(topic, topicPrefixPermission)
+		return permission != "" && middleware.RoleHasPermission(c.Role, permission)
+	default:
+		return false
+	}
+}
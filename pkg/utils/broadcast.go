@@ -8,17 +8,39 @@ import (
 
 // BroadcastUpdate mengirim update ke semua client yang terhubung via WebSocket
 func BroadcastUpdate(hub *websocket.Hub, eventType string, data interface{}) error {
+	return BroadcastToTopic(hub, "", eventType, data)
+}
+
+// BroadcastToTopic mengirim update hanya ke client yang berlangganan topic
+// (lihat websocket.TopicUser, TopicRole, TopicWarehouse, TopicPermission). Topic kosong = semua client.
+func BroadcastToTopic(hub *websocket.Hub, topic, eventType string, data interface{}) error {
+	if topic == "" {
+		return BroadcastToTopics(hub, nil, eventType, data)
+	}
+	return BroadcastToTopics(hub, []string{topic}, eventType, data)
+}
+
+// BroadcastToTopics mengirim update sekali ke setiap client yang berlangganan minimal satu topic.
+// Tanpa topic = semua client.
+func BroadcastToTopics(hub *websocket.Hub, topics []string, eventType string, data interface{}) error {
 	message := map[string]interface{}{
 		"type": eventType,
 		"data": data,
 	}
+	if len(topics) > 0 {
+		message["topics"] = topics
+	}
 
 	jsonData, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
-	hub.BroadcastMessage(jsonData)
+	if len(topics) == 0 {
+		hub.BroadcastMessage(jsonData)
+	} else {
+		hub.PublishTopics(topics, jsonData)
+	}
 	return nil
 }
 